@@ -19,7 +19,8 @@ import (
 )
 
 type Migration struct {
-	db *md.Database
+	db     *md.Database
+	pepper string
 }
 
 func main() {
@@ -44,7 +45,7 @@ func main() {
 		}
 	}()
 
-	migration := &Migration{db: client.DB}
+	migration := &Migration{db: client.DB, pepper: cfg.TokenPepper}
 
 	switch migrationName {
 	case "single-to-multi-club":
@@ -57,6 +58,8 @@ func main() {
 		err = migration.AddScoringProfiles(context.Background())
 	case "add-search-keys":
 		err = migration.AddSearchKeys(context.Background())
+	case "hash-tokens":
+		err = migration.HashTokens(context.Background())
 	default:
 		log.Fatalf("Unknown migration: %s", migrationName)
 	}
@@ -553,6 +556,80 @@ func (m *Migration) addSearchKeysIndexes(ctx context.Context) error {
 	return nil
 }
 
+// HashTokens replaces plaintext session, refresh and magic link tokens with their
+// hash. Clients keep their tokens, so nobody is logged out. Run it with the same
+// TOKEN_PEPPER as the API server while it accepts legacy tokens, then unset
+// ACCEPT_LEGACY_TOKENS; it is safe to run more than once.
+func (m *Migration) HashTokens(ctx context.Context) error {
+	log.Println("Starting token hashing migration...")
+
+	for _, name := range []string{"api_tokens", "refresh_tokens", "magic_link_tokens"} {
+		if err := m.hashTokensIn(ctx, m.db.Collection(name)); err != nil {
+			return fmt.Errorf("failed to hash tokens in %s: %w", name, err)
+		}
+	}
+
+	log.Println("Token hashing migration completed successfully")
+	return nil
+}
+
+// hashTokensIn converts every document in a collection that still has a plaintext token
+func (m *Migration) hashTokensIn(ctx context.Context, collection *md.Collection) error {
+	// The original non-sparse unique index would reject documents without a token
+	if _, err := collection.Indexes().DropOne(ctx, "token_1"); err == nil {
+		log.Printf("Dropped plaintext token index on %s", collection.Name())
+	}
+
+	cursor, err := collection.Find(ctx,
+		bson.M{"token": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"_id": 1, "token": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var processed, updated int
+	pepper := []byte(m.pepper)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Token string             `bson:"token"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			log.Printf("Failed to decode token document: %v", err)
+			continue
+		}
+		processed++
+
+		// Match on the plaintext too so a token rotated meanwhile is left alone
+		result, err := collection.UpdateOne(ctx,
+			bson.M{"_id": doc.ID, "token": doc.Token},
+			bson.M{
+				"$set":   bson.M{"token_hash": repo.HashToken(doc.Token, pepper)},
+				"$unset": bson.M{"token": ""},
+			},
+		)
+		if err != nil {
+			log.Printf("Failed to hash token %s: %v", doc.ID.Hex(), err)
+			continue
+		}
+		if result.ModifiedCount > 0 {
+			updated++
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor error during migration: %w", err)
+	}
+
+	log.Printf("Hashed tokens in %s: processed %d documents, updated %d documents", collection.Name(), processed, updated)
+	return nil
+}
+
 // generateSimpleSearchKeys creates basic search keys without external dependencies
 func generateSimpleSearchKeys(text string) bson.M {
 	normalized := strings.ToLower(text)
@@ -63,6 +63,9 @@ const (
 	EventAuthTokenValidated EventType = "auth.token.validated" // #nosec G101 - token event name, not credential
 	EventAuthTokenRevoked   EventType = "auth.token.revoked"   // #nosec G101 - token event name, not credential
 	EventAuthFailure        EventType = "auth.failure"
+	EventAuthAPIKeyCreated  EventType = "auth.apikey.created"
+	EventAuthAPIKeyListed   EventType = "auth.apikey.listed"
+	EventAuthAPIKeyRevoked  EventType = "auth.apikey.revoked"
 
 	// Authorization events
 	EventAuthzPermissionCheck EventType = "authz.permission.check"
@@ -78,13 +81,20 @@ const (
 	EventDataImport EventType = "data.import"
 
 	// Administrative events
-	EventAdminUserCreate   EventType = "admin.user.create"
-	EventAdminUserUpdate   EventType = "admin.user.update"
-	EventAdminUserDelete   EventType = "admin.user.delete"
-	EventAdminClubCreate   EventType = "admin.club.create"
-	EventAdminClubUpdate   EventType = "admin.club.update"
-	EventAdminClubDelete   EventType = "admin.club.delete"
-	EventAdminConfigChange EventType = "admin.config.change"
+	EventAdminUserCreate        EventType = "admin.user.create"
+	EventAdminUserUpdate        EventType = "admin.user.update"
+	EventAdminUserDelete        EventType = "admin.user.delete"
+	EventAdminClubCreate        EventType = "admin.club.create"
+	EventAdminClubUpdate        EventType = "admin.club.update"
+	EventAdminClubDelete        EventType = "admin.club.delete"
+	EventAdminConfigChange      EventType = "admin.config.change"
+	EventAdminStatsRead         EventType = "admin.stats.read"
+	EventAdminUserSearch        EventType = "admin.user.search"
+	EventAdminUserLogout        EventType = "admin.user.logout"
+	EventAdminUserDisable       EventType = "admin.user.disable"
+	EventAdminUserImpersonate   EventType = "admin.user.impersonate"
+	EventAdminOwnershipTransfer EventType = "admin.ownership.transfer"
+	EventAdminEmailsRead        EventType = "admin.emails.read"
 
 	// Security events
 	EventSecurityThreatDetected     EventType = "security.threat.detected"
@@ -395,6 +405,8 @@ func (al *AuditLogger) getSecurityLevel(eventType EventType) string {
 		return "MEDIUM"
 	case EventAdminUserDelete, EventAdminClubDelete:
 		return "MEDIUM"
+	case EventAdminUserImpersonate, EventAdminOwnershipTransfer, EventAdminUserDisable:
+		return "MEDIUM"
 	default:
 		return "LOW"
 	}
@@ -416,6 +428,8 @@ func (al *AuditLogger) getRiskScore(eventType EventType, result string) int {
 		baseScore = 60
 	case EventAdminUserDelete, EventAdminClubDelete:
 		baseScore = 70
+	case EventAdminUserImpersonate, EventAdminOwnershipTransfer:
+		baseScore = 50
 	default:
 		baseScore = 10
 	}
@@ -439,7 +453,7 @@ func (al *AuditLogger) getDataClassification(eventType EventType) string {
 		return "CONFIDENTIAL"
 	case EventDataCreate, EventDataUpdate, EventDataDelete:
 		return "RESTRICTED"
-	case EventAdminUserCreate, EventAdminUserUpdate, EventAdminUserDelete:
+	case EventAdminUserCreate, EventAdminUserUpdate, EventAdminUserDelete, EventAdminUserSearch, EventAdminUserImpersonate, EventAdminEmailsRead:
 		return "CONFIDENTIAL"
 	default:
 		return "INTERNAL"
@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
+	"strings"
 
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,24 +23,90 @@ func NewAuthorizationService() *AuthorizationService {
 type AuthorizationPattern int
 
 const (
-	AuthPatternPublic        AuthorizationPattern = iota // No authentication required
-	AuthPatternAuthenticated                             // Basic authentication only
-	AuthPatternPlatformOwner                             // Platform owner check required
-	AuthPatternClubAdmin                                 // Club admin check required
-	AuthPatternResourceBased                             // Custom resource-specific logic
+	AuthPatternPublic         AuthorizationPattern = iota // No authentication required
+	AuthPatternAuthenticated                              // Basic authentication only
+	AuthPatternPlatformOwner                              // Platform owner check required
+	AuthPatternClubPermission                             // Club role permission check required
+	AuthPatternResourceBased                              // Custom resource-specific logic
 )
 
+// clubPermissionMethods maps club-scoped methods to the permission they require.
+// The check itself runs in the service method, which knows the club from the request.
+var clubPermissionMethods = map[string]service.Permission{
+	"/klubbspel.v1.ClubService/UpdateClub":                   service.PermManageClub,
+	"/klubbspel.v1.SeriesService/CreateSeries":               service.PermManageSeries,
+	"/klubbspel.v1.SeriesService/UpdateSeries":               service.PermManageSeries,
+	"/klubbspel.v1.SeriesService/DeleteSeries":               service.PermManageSeries,
+	"/klubbspel.v1.SeriesService/CloneSeries":                service.PermManageSeries,
+	"/klubbspel.v1.SeriesService/ReviewSeriesEntrant":        service.PermManageSeries,
+	"/klubbspel.v1.MatchRuleService/CreateMatchRule":         service.PermManageClub,
+	"/klubbspel.v1.MatchRuleService/UpdateMatchRule":         service.PermManageClub,
+	"/klubbspel.v1.MatchRuleService/DeleteMatchRule":         service.PermManageClub,
+	"/klubbspel.v1.MatchRuleService/ListMatchRules":          service.PermViewAdminData,
+	"/klubbspel.v1.MatchRuleService/TestMatchRule":           service.PermManageClub,
+	"/klubbspel.v1.MatchReviewService/ListMatchFlags":        service.PermManageMatches,
+	"/klubbspel.v1.MatchReviewService/ReviewMatchFlag":       service.PermManageMatches,
+	"/klubbspel.v1.MatchReviewService/GetAbuseThresholds":    service.PermManageClub,
+	"/klubbspel.v1.MatchReviewService/UpdateAbuseThresholds": service.PermManageClub,
+	"/klubbspel.v1.ClubMembershipService/InvitePlayer":       service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/ResendInvitation":   service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/RevokeInvitation":   service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/ListJoinRequests":   service.PermViewAdminData,
+	"/klubbspel.v1.ClubMembershipService/ReviewJoinRequest":  service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/UpdateMemberRole":   service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/AddPlayerToClub":    service.PermManageMembers,
+	"/klubbspel.v1.ClubMembershipService/ListClubMembers":    service.PermViewMembers,
+	"/klubbspel.v1.PlayerService/CreatePlayer":               service.PermManageMembers, // Require member management for player creation
+}
+
+// apiKeyScopeMethods maps the methods API keys may call to the scope they require.
+// Any method not listed here, including all account and key management, needs a
+// human session.
+var apiKeyScopeMethods = map[string]service.Scope{
+	"/klubbspel.v1.ClubService/ListClubs":             service.ScopeClubsRead,
+	"/klubbspel.v1.ClubService/GetClub":               service.ScopeClubsRead,
+	"/klubbspel.v1.PlayerService/ListPlayers":         service.ScopePlayersRead,
+	"/klubbspel.v1.PlayerService/GetPlayer":           service.ScopePlayersRead,
+	"/klubbspel.v1.PlayerService/GetPlayerStats":      service.ScopePlayersRead,
+	"/klubbspel.v1.PlayerService/GetHeadToHead":       service.ScopePlayersRead,
+	"/klubbspel.v1.SeriesService/ListSeries":          service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/GetSeries":           service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/GetSeriesSummary":    service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/ListSeriesEntrants":  service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/GetLadderStandings":  service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/GetSeriesRules":      service.ScopeSeriesRead,
+	"/klubbspel.v1.SeriesService/CreateSeries":        service.ScopeSeriesWrite,
+	"/klubbspel.v1.SeriesService/UpdateSeries":        service.ScopeSeriesWrite,
+	"/klubbspel.v1.SeriesService/ReviewSeriesEntrant": service.ScopeSeriesWrite,
+	"/klubbspel.v1.MatchService/ListMatches":          service.ScopeMatchesRead,
+	"/klubbspel.v1.MatchService/ReportMatch":          service.ScopeMatchesWrite,
+	"/klubbspel.v1.MatchService/ReportMatchV2":        service.ScopeMatchesWrite,
+	"/klubbspel.v1.MatchService/UpdateMatch":          service.ScopeMatchesWrite,
+	"/klubbspel.v1.MatchService/DeleteMatch":          service.ScopeMatchesWrite,
+	"/klubbspel.v1.LeaderboardService/GetLeaderboard": service.ScopeLeaderboardsRead,
+}
+
 // GetAuthorizationPattern returns the authorization pattern for a given gRPC method
 func (a *AuthorizationService) GetAuthorizationPattern(method string) AuthorizationPattern {
 	// Public methods - no authentication required
 	publicMethods := map[string]bool{
 		"/klubbspel.v1.ClubService/ListClubs":             true,
 		"/klubbspel.v1.PlayerService/ListPlayers":         true,
+		"/klubbspel.v1.PlayerService/GetPlayerStats":      true,
+		"/klubbspel.v1.PlayerService/GetHeadToHead":       true,
 		"/klubbspel.v1.SeriesService/ListSeries":          true,
+		"/klubbspel.v1.SeriesService/ListSeriesEntrants":  true,
 		"/klubbspel.v1.LeaderboardService/GetLeaderboard": true,
 		"/klubbspel.v1.MatchService/ListMatches":          true,
 		"/klubbspel.v1.AuthService/SendMagicLink":         true,
 		"/klubbspel.v1.AuthService/ValidateToken":         true,
+		"/klubbspel.v1.AuthService/RefreshToken":          true,
+		"/klubbspel.v1.AuthService/BeginPasskeyLogin":     true,
+		"/klubbspel.v1.AuthService/FinishPasskeyLogin":    true,
+		"/klubbspel.v1.AuthService/ListOIDCProviders":     true,
+		"/klubbspel.v1.AuthService/StartOIDCLogin":        true,
+		"/klubbspel.v1.AuthService/CompleteOIDCLogin":     true,
+		"/klubbspel.v1.NotificationService/Unsubscribe":   true,
 	}
 
 	if publicMethods[method] {
@@ -46,30 +115,24 @@ func (a *AuthorizationService) GetAuthorizationPattern(method string) Authorizat
 
 	// Platform owner methods - highest privilege level
 	platformOwnerMethods := map[string]bool{
-		"/klubbspel.v1.ClubService/DeleteClub":      true,
-		"/klubbspel.v1.PlayerService/DeletePlayer":  true,
-		"/klubbspel.v1.AdminService/GetSystemStats": true,
-		"/klubbspel.v1.AdminService/ManageUsers":    true,
+		"/klubbspel.v1.ClubService/DeleteClub":                 true,
+		"/klubbspel.v1.PlayerService/DeletePlayer":             true,
+		"/klubbspel.v1.AdminService/GetSystemStats":            true,
+		"/klubbspel.v1.AdminService/SearchUsers":               true,
+		"/klubbspel.v1.AdminService/ForceLogout":               true,
+		"/klubbspel.v1.AdminService/TransferPlatformOwnership": true,
+		"/klubbspel.v1.AdminService/DisableUser":               true,
+		"/klubbspel.v1.AdminService/ImpersonateUser":           true,
+		"/klubbspel.v1.AdminService/ListEmailDeliveries":       true,
 	}
 
 	if platformOwnerMethods[method] {
 		return AuthPatternPlatformOwner
 	}
 
-	// Club admin methods - require club admin or platform owner
-	clubAdminMethods := map[string]bool{
-		"/klubbspel.v1.ClubService/UpdateClub":                 true,
-		"/klubbspel.v1.SeriesService/CreateSeries":             true,
-		"/klubbspel.v1.SeriesService/UpdateSeries":             true,
-		"/klubbspel.v1.SeriesService/DeleteSeries":             true,
-		"/klubbspel.v1.ClubMembershipService/InvitePlayer":     true,
-		"/klubbspel.v1.ClubMembershipService/UpdateMemberRole": true,
-		"/klubbspel.v1.ClubMembershipService/ListClubMembers":  true,
-		"/klubbspel.v1.PlayerService/CreatePlayer":             true, // Require club admin for player creation
-	}
-
-	if clubAdminMethods[method] {
-		return AuthPatternClubAdmin
+	// Club permission methods - require a club role granting the permission, or platform owner
+	if _, ok := clubPermissionMethods[method]; ok {
+		return AuthPatternClubPermission
 	}
 
 	// Resource-based methods - require custom authorization logic
@@ -78,6 +141,8 @@ func (a *AuthorizationService) GetAuthorizationPattern(method string) Authorizat
 		"/klubbspel.v1.MatchService/UpdateMatch":          true,
 		"/klubbspel.v1.MatchService/DeleteMatch":          true,
 		"/klubbspel.v1.ClubMembershipService/LeaveClub":   true,
+		"/klubbspel.v1.SeriesService/RegisterForSeries":   true, // Custom logic: self-registration, club admins may register others
+		"/klubbspel.v1.SeriesService/WithdrawFromSeries":  true,
 		"/klubbspel.v1.PlayerService/FindMergeCandidates": true, // Custom logic: authenticated users can find candidates
 		"/klubbspel.v1.PlayerService/MergePlayer":         true, // Custom logic: users can merge email-less profiles to themselves
 	}
@@ -126,8 +191,8 @@ func (a *AuthorizationService) CheckAuthorization(ctx context.Context, method st
 		}
 		return nil
 
-	case AuthPatternClubAdmin:
-		// Club admin check will be performed in the service method
+	case AuthPatternClubPermission:
+		// Club permission check will be performed in the service method
 		// since it requires club_id from the request
 		if subject == nil {
 			return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
@@ -200,6 +265,66 @@ func (a *AuthorizationService) CheckClubManagement(ctx context.Context, subject
 	return nil
 }
 
+// RequiredPermission returns the club permission a method requires, if any
+func (a *AuthorizationService) RequiredPermission(method string) (service.Permission, bool) {
+	permission, ok := clubPermissionMethods[method]
+	return permission, ok
+}
+
+// CheckClubPermission verifies that the subject holds the permission required by method in a club
+func (a *AuthorizationService) CheckClubPermission(ctx context.Context, subject Subject, method, clubID string) error {
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	permission, ok := a.RequiredPermission(method)
+	if !ok {
+		return nil
+	}
+
+	allowed, err := subject.HasClubPermission(ctx, clubID, permission)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !allowed {
+		return status.Error(codes.PermissionDenied, "CLUB_PERMISSION_REQUIRED")
+	}
+
+	return nil
+}
+
+// RequiredScope returns the scope an API key needs to call a method; false means
+// API keys may not call it at all
+func (a *AuthorizationService) RequiredScope(method string) (service.Scope, bool) {
+	scope, ok := apiKeyScopeMethods[method]
+	return scope, ok
+}
+
+// IsReadOnlyMethod reports whether a read-only (impersonation) session may call
+// a method: Get and List calls outside the admin service, plus signing out
+func (a *AuthorizationService) IsReadOnlyMethod(method string) bool {
+	if method == "/klubbspel.v1.AuthService/RevokeToken" {
+		return true
+	}
+	if strings.HasPrefix(method, "/klubbspel.v1.AdminService/") {
+		return false
+	}
+	name := method[strings.LastIndex(method, "/")+1:]
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
+
+// CheckAPIKeyScope verifies that an API key may call a method
+func (a *AuthorizationService) CheckAPIKeyScope(method string, key *repo.APIKey) error {
+	scope, ok := a.RequiredScope(method)
+	if !ok {
+		return status.Error(codes.PermissionDenied, "API_KEY_NOT_ALLOWED")
+	}
+	if !service.HasScope(key, scope) {
+		return status.Error(codes.PermissionDenied, "API_KEY_SCOPE_REQUIRED")
+	}
+	return nil
+}
+
 // Middleware function for gRPC interceptor integration
 func (a *AuthorizationService) AuthorizeRequest(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo) error {
 	method := info.FullMethod
@@ -282,8 +407,11 @@ func (a *AuthorizationService) GetRequiredRole(method string) string {
 		return "Authenticated user"
 	case AuthPatternPlatformOwner:
 		return "Platform owner"
-	case AuthPatternClubAdmin:
-		return "Club admin or platform owner"
+	case AuthPatternClubPermission:
+		if permission, ok := a.RequiredPermission(method); ok {
+			return "Club role with " + string(permission) + " permission, or platform owner"
+		}
+		return "Club role permission or platform owner"
 	case AuthPatternResourceBased:
 		return "Resource-specific permissions"
 	default:
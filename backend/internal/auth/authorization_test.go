@@ -0,0 +1,25 @@
+package auth
+
+import "testing"
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	tests := []struct {
+		method   string
+		expected bool
+	}{
+		{"/klubbspel.v1.LeaderboardService/GetLeaderboard", true},
+		{"/klubbspel.v1.MatchService/ListMatches", true},
+		{"/klubbspel.v1.AuthService/RevokeToken", true},
+		{"/klubbspel.v1.MatchService/ReportMatch", false},
+		{"/klubbspel.v1.ClubMembershipService/LeaveClub", false},
+		{"/klubbspel.v1.AdminService/GetSystemStats", false},
+		{"/klubbspel.v1.AdminService/ListEmailDeliveries", false},
+	}
+
+	authz := NewAuthorizationService()
+	for _, test := range tests {
+		if got := authz.IsReadOnlyMethod(test.method); got != test.expected {
+			t.Errorf("IsReadOnlyMethod(%q) = %v, want %v", test.method, got, test.expected)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 type AuthInterceptor struct {
 	TokenRepo  *repo.TokenRepo
 	PlayerRepo *repo.PlayerRepo
+	authz      *AuthorizationService
 }
 
 // NewAuthInterceptor creates a new authentication interceptor
@@ -24,6 +25,7 @@ func NewAuthInterceptor(tokenRepo *repo.TokenRepo, playerRepo *repo.PlayerRepo)
 	return &AuthInterceptor{
 		TokenRepo:  tokenRepo,
 		PlayerRepo: playerRepo,
+		authz:      NewAuthorizationService(),
 	}
 }
 
@@ -38,6 +40,13 @@ func (a *AuthInterceptor) UnaryInterceptor(
 
 	// Check if the method is public (doesn't require authentication)
 	if a.isPublicMethod(method) {
+		// Attach the caller when a valid token is sent so that public reads
+		// can still include CLUB_ONLY content the caller has access to
+		if token, err := a.extractToken(ctx); err == nil {
+			if authCtx, err := a.authenticate(ctx, method, token); err == nil {
+				ctx = authCtx
+			}
+		}
 		return handler(ctx, req)
 	}
 
@@ -47,17 +56,55 @@ func (a *AuthInterceptor) UnaryInterceptor(
 		return nil, err
 	}
 
+	ctx, err = a.authenticate(ctx, method, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// authenticate validates a bearer token and adds the caller to the context.
+// API keys are additionally checked against the scope the method requires.
+func (a *AuthInterceptor) authenticate(ctx context.Context, method, token string) (context.Context, error) {
+	if repo.IsAPIKey(token) {
+		key, err := a.TokenRepo.FindAPIKey(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_API_KEY")
+		}
+		if err := a.authz.CheckAPIKeyScope(method, key); err != nil {
+			return nil, err
+		}
+
+		// Update last used timestamp (async, don't block)
+		go func() {
+			_ = a.TokenRepo.UpdateAPIKeyLastUsed(context.Background(), key.ID)
+		}()
+
+		return service.WithSubject(ctx, service.NewAPIKeySubject(key, a.PlayerRepo)), nil
+	}
+
+	// Validate token exists and is not expired (this will trigger DB lookup)
+	apiToken, err := a.TokenRepo.GetAPIToken(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_TOKEN")
+	}
+
+	// Impersonation sessions may look but not touch
+	if apiToken.ReadOnly && !a.authz.IsReadOnlyMethod(method) {
+		return nil, status.Error(codes.PermissionDenied, "IMPERSONATION_READ_ONLY")
+	}
+
 	// Create lazy subject for authorization
 	subject := service.NewLazySubject(token, a.TokenRepo, a.PlayerRepo)
 
 	// Add subject and token to context
 	ctx = service.WithSubject(ctx, subject)
 	ctx = service.WithToken(ctx, token)
-
-	return handler(ctx, req)
+	return ctx, nil
 }
 
-// extractToken extracts and validates the API token from the request
+// extractToken extracts the bearer token from the request
 func (a *AuthInterceptor) extractToken(ctx context.Context) (string, error) {
 	// Extract metadata from context
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -83,12 +130,6 @@ func (a *AuthInterceptor) extractToken(ctx context.Context) (string, error) {
 		return "", status.Error(codes.Unauthenticated, "EMPTY_TOKEN")
 	}
 
-	// Validate token exists and is not expired (this will trigger DB lookup)
-	_, err := a.TokenRepo.GetAPIToken(ctx, token)
-	if err != nil {
-		return "", status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_TOKEN")
-	}
-
 	return token, nil
 }
 
@@ -100,12 +141,16 @@ func (a *AuthInterceptor) isPublicMethod(method string) bool {
 		"/klubbspel.v1.ClubService/GetClub":   true,
 
 		// Player service - public read access
-		"/klubbspel.v1.PlayerService/ListPlayers": true,
-		"/klubbspel.v1.PlayerService/GetPlayer":   true,
+		"/klubbspel.v1.PlayerService/ListPlayers":    true,
+		"/klubbspel.v1.PlayerService/GetPlayer":      true,
+		"/klubbspel.v1.PlayerService/GetPlayerStats": true,
+		"/klubbspel.v1.PlayerService/GetHeadToHead":  true,
 
 		// Series service - public read access
-		"/klubbspel.v1.SeriesService/ListSeries": true,
-		"/klubbspel.v1.SeriesService/GetSeries":  true,
+		"/klubbspel.v1.SeriesService/ListSeries":         true,
+		"/klubbspel.v1.SeriesService/GetSeries":          true,
+		"/klubbspel.v1.SeriesService/GetSeriesSummary":   true,
+		"/klubbspel.v1.SeriesService/ListSeriesEntrants": true,
 
 		// Leaderboard service - public read access
 		"/klubbspel.v1.LeaderboardService/GetLeaderboard": true,
@@ -114,9 +159,18 @@ func (a *AuthInterceptor) isPublicMethod(method string) bool {
 		"/klubbspel.v1.MatchService/ListMatches": true,
 		"/klubbspel.v1.MatchService/GetMatch":    true,
 
-		// Auth service - public for magic link flow
-		"/klubbspel.v1.AuthService/SendMagicLink": true,
-		"/klubbspel.v1.AuthService/ValidateToken": true,
+		// Auth service - public for magic link, refresh, passkey and OIDC login flows
+		"/klubbspel.v1.AuthService/SendMagicLink":      true,
+		"/klubbspel.v1.AuthService/ValidateToken":      true,
+		"/klubbspel.v1.AuthService/RefreshToken":       true,
+		"/klubbspel.v1.AuthService/BeginPasskeyLogin":  true,
+		"/klubbspel.v1.AuthService/FinishPasskeyLogin": true,
+		"/klubbspel.v1.AuthService/ListOIDCProviders":  true,
+		"/klubbspel.v1.AuthService/StartOIDCLogin":     true,
+		"/klubbspel.v1.AuthService/CompleteOIDCLogin":  true,
+
+		// Notification service - unsubscribe links are signed and work without login
+		"/klubbspel.v1.NotificationService/Unsubscribe": true,
 	}
 
 	return publicMethods[method]
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/service"
 )
 
 // Subject represents an authenticated user with lazy-loaded authorization context
@@ -32,6 +33,7 @@ type Subject interface {
 	CanManageClub(ctx context.Context, clubID string) (bool, error)
 	CanCreateSeries(ctx context.Context, clubID string) (bool, error)
 	CanInviteToClub(ctx context.Context, clubID string) (bool, error)
+	HasClubPermission(ctx context.Context, clubID string, permission service.Permission) (bool, error)
 }
 
 // LazySubject implements Subject with lazy loading and context caching
@@ -122,7 +124,7 @@ func (s *LazySubject) IsClubAdmin(ctx context.Context, clubID string) (bool, err
 	}
 
 	for _, membership := range s.player.ClubMemberships {
-		if membership.ClubID.Hex() == clubID && membership.Role == "admin" {
+		if membership.ClubID.Hex() == clubID && membership.Role == repo.ClubRoleAdmin {
 			return true, nil
 		}
 	}
@@ -150,7 +152,7 @@ func (s *LazySubject) GetAdminClubIDs(ctx context.Context) ([]string, error) {
 
 	var adminClubs []string
 	for _, membership := range s.player.ClubMemberships {
-		if membership.Role == "admin" {
+		if membership.Role == repo.ClubRoleAdmin {
 			adminClubs = append(adminClubs, membership.ClubID.Hex())
 		}
 	}
@@ -193,13 +195,30 @@ func (s *LazySubject) CanManageClub(ctx context.Context, clubID string) (bool, e
 }
 
 func (s *LazySubject) CanCreateSeries(ctx context.Context, clubID string) (bool, error) {
-	// Only club admins and platform owners can create series
-	return s.CanManageClub(ctx, clubID)
+	// Series managers, club admins and platform owners can create series
+	return s.HasClubPermission(ctx, clubID, service.PermManageSeries)
 }
 
 func (s *LazySubject) CanInviteToClub(ctx context.Context, clubID string) (bool, error) {
-	// Only club admins and platform owners can invite players
-	return s.CanManageClub(ctx, clubID)
+	// Club admins and platform owners can invite players
+	return s.HasClubPermission(ctx, clubID, service.PermManageMembers)
+}
+
+// HasClubPermission evaluates the club role permission matrix; platform owners hold every permission
+func (s *LazySubject) HasClubPermission(ctx context.Context, clubID string, permission service.Permission) (bool, error) {
+	isPlatformOwner, err := s.IsPlatformOwner(ctx)
+	if err != nil {
+		return false, err
+	}
+	if isPlatformOwner {
+		return true, nil
+	}
+
+	role, err := s.GetClubRole(ctx, clubID)
+	if err != nil {
+		return false, err
+	}
+	return service.RoleHasPermission(role, permission), nil
 }
 
 // Context keys for storing subject in request context
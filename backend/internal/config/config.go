@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 type Config struct {
 	MongoURI      string
@@ -12,23 +15,66 @@ type Config struct {
 	Environment   string // development, staging, production
 
 	// Email configuration
-	EmailProvider    string // sendgrid, mailhog, mock
+	EmailProvider    string // sendgrid, smtp, mailhog, mock
 	EmailFromName    string
 	EmailFromAddress string
 	EmailBaseURL     string
+	PublicAPIURL     string // Public REST API URL for one-click unsubscribe headers; defaults to EmailBaseURL
 
 	// SendGrid specific
 	SendGridAPIKey string
 
-	// SMTP specific (for MailHog)
-	SMTPHost     string
-	SMTPPort     string
-	SMTPUsername string
-	SMTPPassword string
-	SMTPTLSMode  string
+	// SMTP specific (own mail server or MailHog)
+	SMTPHost          string
+	SMTPPort          string
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPTLSMode       string // none, tls or starttls; empty means starttls for smtp and none for MailHog
+	SMTPBounceAddress string // Envelope sender for bounces; defaults to EmailFromAddress
+	SMTPHeloName      string
+
+	// DKIM signing for the smtp provider. The key is read from DKIMPrivateKeyFile
+	// when DKIMPrivateKey is empty.
+	DKIMDomain         string
+	DKIMSelector       string
+	DKIMPrivateKey     string
+	DKIMPrivateKeyFile string
 
 	// GDPR configuration
 	GDPREncryptionKey string
+
+	// Token expiry policy (Go durations, e.g. "15m", "720h")
+	AccessTokenTTL  string // Lifetime of an access token before it must be refreshed
+	RefreshTokenTTL string // Idle lifetime of a session; each refresh extends it
+
+	// Server-side secret mixed into stored token hashes. Changing it signs everyone out.
+	TokenPepper string
+	// Accept plaintext tokens stored before tokens were hashed; only until hash-tokens has run
+	AcceptLegacyTokens bool
+
+	// Signs notification unsubscribe links; required outside development
+	NotificationSecret string
+
+	// Passkey (WebAuthn) relying party; defaults derive from EmailBaseURL
+	WebAuthnRPID    string
+	WebAuthnRPName  string
+	WebAuthnOrigins string // Comma-separated allowed origins
+
+	// OpenID Connect identity providers (Google, Microsoft, ...)
+	OIDCProviders   []OIDCProviderConfig
+	OIDCRedirectURL string // Defaults to <EmailBaseURL>/auth/oidc/callback
+}
+
+// OIDCProviderConfig is one OpenID Connect client registration. Providers are
+// listed in OIDC_PROVIDERS and configured with OIDC_<ID>_* variables, e.g.
+// OIDC_GOOGLE_ISSUER, OIDC_GOOGLE_CLIENT_ID and OIDC_GOOGLE_CLIENT_SECRET.
+type OIDCProviderConfig struct {
+	ID           string
+	DisplayName  string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
 
 func FromEnv() Config {
@@ -46,6 +92,7 @@ func FromEnv() Config {
 		EmailFromName:    getenv("EMAIL_FROM_NAME", "Klubbspel"),
 		EmailFromAddress: getenv("EMAIL_FROM_ADDRESS", "noreply@klubbspel.se"),
 		EmailBaseURL:     getenv("EMAIL_BASE_URL", "http://localhost:5000"),
+		PublicAPIURL:     getenv("PUBLIC_API_URL", ""),
 
 		// SendGrid
 		SendGridAPIKey: getenv("SENDGRID_API_KEY", ""),
@@ -55,11 +102,66 @@ func FromEnv() Config {
 		SMTPPort:     getenv("SMTP_PORT", "1025"),
 		SMTPUsername: getenv("SMTP_USERNAME", ""),
 		SMTPPassword: getenv("SMTP_PASSWORD", ""),
-		SMTPTLSMode:  getenv("SMTP_TLS_MODE", "none"),
+		SMTPTLSMode:  getenv("SMTP_TLS_MODE", ""),
+
+		SMTPBounceAddress: getenv("SMTP_BOUNCE_ADDRESS", ""),
+		SMTPHeloName:      getenv("SMTP_HELO_NAME", ""),
+
+		// DKIM
+		DKIMDomain:         getenv("DKIM_DOMAIN", ""),
+		DKIMSelector:       getenv("DKIM_SELECTOR", ""),
+		DKIMPrivateKey:     getenv("DKIM_PRIVATE_KEY", ""),
+		DKIMPrivateKeyFile: getenv("DKIM_PRIVATE_KEY_FILE", ""),
 
 		// GDPR configuration
 		GDPREncryptionKey: getenv("GDPR_ENCRYPTION_KEY", ""),
+
+		// Token expiry
+		AccessTokenTTL:  getenv("ACCESS_TOKEN_TTL", "1h"),
+		RefreshTokenTTL: getenv("REFRESH_TOKEN_TTL", "720h"), // 30 days
+		TokenPepper:     getenv("TOKEN_PEPPER", ""),
+
+		AcceptLegacyTokens: getenv("ACCEPT_LEGACY_TOKENS", "false") == "true",
+
+		// Notifications
+		NotificationSecret: getenv("NOTIFICATION_SECRET", ""),
+
+		// Passkeys
+		WebAuthnRPID:    getenv("WEBAUTHN_RP_ID", ""),
+		WebAuthnRPName:  getenv("WEBAUTHN_RP_NAME", "Klubbspel"),
+		WebAuthnOrigins: getenv("WEBAUTHN_ORIGINS", ""),
+
+		// OpenID Connect
+		OIDCProviders:   oidcProvidersFromEnv(),
+		OIDCRedirectURL: getenv("OIDC_REDIRECT_URL", ""),
+	}
+}
+
+// oidcProvidersFromEnv reads the providers named in OIDC_PROVIDERS, skipping
+// any without an issuer or client ID
+func oidcProvidersFromEnv() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, id := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(id, "-", "_")) + "_"
+		provider := OIDCProviderConfig{
+			ID:           id,
+			DisplayName:  getenv(prefix+"DISPLAY_NAME", id),
+			IssuerURL:    os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if provider.IssuerURL == "" || provider.ClientID == "" {
+			continue
+		}
+		providers = append(providers, provider)
 	}
+	return providers
 }
 
 func getenv(k, d string) string {
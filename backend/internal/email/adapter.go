@@ -3,6 +3,7 @@ package email
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ type EmailProvider string
 const (
 	ProviderSendGrid EmailProvider = "sendgrid"
 	ProviderMailHog  EmailProvider = "mailhog"
+	ProviderSMTP     EmailProvider = "smtp"
 	ProviderMock     EmailProvider = "mock"
 )
 
@@ -26,6 +28,12 @@ type EmailConfig struct {
 	FromEmail string
 	BaseURL   string
 
+	// Public URL of the REST API, used for one-click unsubscribe links
+	APIBaseURL string
+
+	// Locale used when the recipient has none or an unsupported one
+	DefaultLocale string
+
 	// SendGrid specific
 	SendGridAPIKey string
 
@@ -35,71 +43,177 @@ type EmailConfig struct {
 	SMTPUsername string
 	SMTPPassword string
 	SMTPTLSMode  string // none, tls, starttls
+
+	// Production SMTP (ProviderSMTP)
+	SMTPBounceAddress string // Envelope sender that receives bounces; defaults to FromEmail
+	SMTPHeloName      string // Host name sent in EHLO; defaults to "localhost"
+
+	// DKIM signing for ProviderSMTP; disabled when DKIMPrivateKey is empty
+	DKIMDomain     string
+	DKIMSelector   string
+	DKIMPrivateKey string // PEM encoded RSA or Ed25519 key
+}
+
+// Service sends the platform's transactional emails. locale selects the
+// translation; unsupported or empty locales fall back to the default locale.
+type Service interface {
+	SendMagicLink(ctx context.Context, toEmail, locale, token, returnURL string) error
+	SendClubInvitationMagicLink(ctx context.Context, toEmail, locale, token, returnURL, clubName, inviterName, inviterEmail string) error
+	SendInvitation(ctx context.Context, toEmail, locale, clubName, inviterName string) error
+	SendJoinRequestNotification(ctx context.Context, toEmail, locale, clubID, clubName, requesterName, requesterEmail string) error
+	SendEmail(ctx context.Context, toEmail, subject, body string) error
+
+	// Member notifications carry a signed unsubscribe token for the footer link
+	// and the one-click List-Unsubscribe header
+	SendNotification(ctx context.Context, toEmail, locale, template, link string, data map[string]string, unsubscribeToken string) error
+	SendDigest(ctx context.Context, toEmail, locale string, items []DigestItem, unsubscribeToken string) error
 }
 
-// EmailAdapter provides a unified interface for different email providers
+// EmailAdapter renders templates and hands the result to the configured transport
 type EmailAdapter struct {
-	config  EmailConfig
-	service Service
+	config    EmailConfig
+	transport Transport
+	renderer  *Renderer
 }
 
-// NewEmailAdapter creates the appropriate email service based on configuration
+// NewEmailAdapter creates an adapter that sends directly through the configured provider
 func NewEmailAdapter(config EmailConfig) (*EmailAdapter, error) {
+	transport, err := NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewEmailAdapterWithTransport(config, transport)
+}
+
+// NewTransport creates the provider transport selected by configuration
+func NewTransport(config EmailConfig) (Transport, error) {
 	log.Info().
 		Str("provider", string(config.Provider)).
 		Str("from_email", config.FromEmail).
-		Msg("Creating email adapter")
-
-	var service Service
+		Msg("Creating email transport")
 
 	switch config.Provider {
 	case "sendgrid":
-		service = NewSendGridService(config.FromName, config.FromEmail, config.BaseURL)
+		return NewSendGridService(config.FromName, config.FromEmail), nil
 	case "mailhog":
 		mailhogService, err := NewMailHogService(config)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create MailHog service")
 			return nil, fmt.Errorf("failed to create MailHog service: %w", err)
 		}
-		service = mailhogService
+		return mailhogService, nil
+	case "smtp":
+		smtpTransport, err := NewSMTPTransport(config)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to create SMTP transport")
+			return nil, fmt.Errorf("failed to create SMTP transport: %w", err)
+		}
+		return smtpTransport, nil
 	case "mock":
 		mockService, err := NewMockEmailService(config)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to create Mock service")
 			return nil, fmt.Errorf("failed to create Mock service: %w", err)
 		}
-		service = mockService
+		return mockService, nil
 	default:
 		log.Error().
 			Str("provider", string(config.Provider)).
 			Msg("Unknown email provider")
 		return nil, fmt.Errorf("unknown email provider: %s", config.Provider)
 	}
+}
+
+// NewEmailAdapterWithTransport creates an adapter around an existing transport,
+// such as an OutboxTransport
+func NewEmailAdapterWithTransport(config EmailConfig, transport Transport) (*EmailAdapter, error) {
+	log.Info().
+		Str("provider", string(config.Provider)).
+		Str("default_locale", config.DefaultLocale).
+		Msg("Creating email adapter")
+
+	if config.DefaultLocale == "" {
+		config.DefaultLocale = "sv"
+	}
+	renderer, err := NewRenderer(config.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email renderer: %w", err)
+	}
 
 	return &EmailAdapter{
-		config:  config,
-		service: service,
+		config:    config,
+		transport: transport,
+		renderer:  renderer,
 	}, nil
 }
 
 // SendMagicLink sends a magic link email
-func (a *EmailAdapter) SendMagicLink(ctx context.Context, toEmail, token, returnURL string) error {
-	return a.service.SendMagicLink(ctx, toEmail, token, returnURL)
+func (a *EmailAdapter) SendMagicLink(ctx context.Context, toEmail, locale, token, returnURL string) error {
+	// Default to leaderboard page if returnURL is /login or empty
+	if returnURL == "" || returnURL == "/login" {
+		returnURL = "/leaderboard"
+	}
+
+	return a.send(ctx, locale, TemplateMagicLink, toEmail, a.magicLinkURL(token, returnURL), nil)
 }
 
 // SendClubInvitationMagicLink sends a club invitation email with magic link
-func (a *EmailAdapter) SendClubInvitationMagicLink(ctx context.Context, toEmail, token, returnURL, clubName, inviterName, inviterEmail string) error {
-	return a.service.SendClubInvitationMagicLink(ctx, toEmail, token, returnURL, clubName, inviterName, inviterEmail)
+func (a *EmailAdapter) SendClubInvitationMagicLink(ctx context.Context, toEmail, locale, token, returnURL, clubName, inviterName, inviterEmail string) error {
+	// Default to root page if returnURL is /login or empty
+	if returnURL == "" || returnURL == "/login" {
+		returnURL = "/"
+	}
+
+	return a.send(ctx, locale, TemplateClubInvitation, toEmail, a.magicLinkURL(token, returnURL), map[string]string{
+		"ClubName":     clubName,
+		"InviterName":  inviterName,
+		"InviterEmail": inviterEmail,
+	})
 }
 
 // SendInvitation sends an invitation email
-func (a *EmailAdapter) SendInvitation(ctx context.Context, toEmail, clubName, inviterName string) error {
-	return a.service.SendInvitation(ctx, toEmail, clubName, inviterName)
+func (a *EmailAdapter) SendInvitation(ctx context.Context, toEmail, locale, clubName, inviterName string) error {
+	inviteURL := fmt.Sprintf("%s/invite?club=%s", a.config.BaseURL, url.QueryEscape(clubName))
+
+	return a.send(ctx, locale, TemplateInvitation, toEmail, inviteURL, map[string]string{
+		"ClubName":    clubName,
+		"InviterName": inviterName,
+	})
 }
 
-// SendEmail sends a generic email
+// SendJoinRequestNotification notifies a club admin about a join request
+func (a *EmailAdapter) SendJoinRequestNotification(ctx context.Context, toEmail, locale, clubID, clubName, requesterName, requesterEmail string) error {
+	reviewURL := fmt.Sprintf("%s/clubs/%s/join-requests", a.config.BaseURL, clubID)
+
+	return a.send(ctx, locale, TemplateJoinRequest, toEmail, reviewURL, map[string]string{
+		"ClubName":       clubName,
+		"RequesterName":  requesterName,
+		"RequesterEmail": requesterEmail,
+	})
+}
+
+// SendEmail sends a generic plain-text email
 func (a *EmailAdapter) SendEmail(ctx context.Context, toEmail, subject, body string) error {
-	return a.service.SendEmail(ctx, toEmail, subject, body)
+	return a.transport.Send(ctx, &Message{
+		To:       toEmail,
+		Subject:  subject,
+		Text:     body,
+		Template: "generic",
+	})
+}
+
+// SendNotification sends one member notification rendered from template
+func (a *EmailAdapter) SendNotification(ctx context.Context, toEmail, locale, template, link string, data map[string]string, unsubscribeToken string) error {
+	req := RenderRequest{To: toEmail, Locale: locale, Template: template, URL: link, Data: data}
+	a.setUnsubscribe(&req, unsubscribeToken)
+	return a.render(ctx, req)
+}
+
+// SendDigest sends several member notifications as one summary email
+func (a *EmailAdapter) SendDigest(ctx context.Context, toEmail, locale string, items []DigestItem, unsubscribeToken string) error {
+	req := RenderRequest{To: toEmail, Locale: locale, Template: TemplateDigest, Items: items}
+	a.setUnsubscribe(&req, unsubscribeToken)
+	return a.render(ctx, req)
 }
 
 // GetProvider returns the current email provider
@@ -107,6 +221,37 @@ func (a *EmailAdapter) GetProvider() EmailProvider {
 	return a.config.Provider
 }
 
+func (a *EmailAdapter) send(ctx context.Context, locale, name, toEmail, link string, data map[string]string) error {
+	return a.render(ctx, RenderRequest{To: toEmail, Locale: locale, Template: name, URL: link, Data: data})
+}
+
+func (a *EmailAdapter) render(ctx context.Context, req RenderRequest) error {
+	msg, err := a.renderer.Render(req)
+	if err != nil {
+		log.Error().Err(err).Str("template", req.Template).Str("locale", req.Locale).Msg("Failed to render email")
+		return fmt.Errorf("failed to render %s email: %w", req.Template, err)
+	}
+	return a.transport.Send(ctx, msg)
+}
+
+// setUnsubscribe points the footer at the unsubscribe page and the
+// List-Unsubscribe header at the API, which mail clients POST to directly
+func (a *EmailAdapter) setUnsubscribe(req *RenderRequest, token string) {
+	if token == "" {
+		return
+	}
+	req.UnsubscribeURL = fmt.Sprintf("%s/unsubscribe?token=%s", a.config.BaseURL, url.QueryEscape(token))
+	apiBaseURL := a.config.APIBaseURL
+	if apiBaseURL == "" {
+		apiBaseURL = a.config.BaseURL
+	}
+	req.ListUnsubscribeURL = fmt.Sprintf("%s/v1/notifications/unsubscribe?token=%s", apiBaseURL, url.QueryEscape(token))
+}
+
+func (a *EmailAdapter) magicLinkURL(token, returnURL string) string {
+	return fmt.Sprintf("%s/auth/login?apikey=%s&return_url=%s", a.config.BaseURL, url.QueryEscape(token), url.QueryEscape(returnURL))
+}
+
 // FromEnv creates an EmailConfig from environment variables
 func FromEnv() EmailConfig {
 	provider := strings.ToLower(getenv("EMAIL_PROVIDER", "mock"))
@@ -117,6 +262,10 @@ func FromEnv() EmailConfig {
 		FromEmail: getenv("EMAIL_FROM_ADDRESS", "noreply@klubbspel.se"),
 		BaseURL:   getenv("EMAIL_BASE_URL", "http://localhost:5000"),
 
+		APIBaseURL: getenv("PUBLIC_API_URL", "http://localhost:8080"),
+
+		DefaultLocale: getenv("DEFAULT_LOCALE", "sv"),
+
 		// SendGrid
 		SendGridAPIKey: os.Getenv("SENDGRID_API_KEY"),
 
@@ -125,7 +274,15 @@ func FromEnv() EmailConfig {
 		SMTPPort:     parseInt(getenv("SMTP_PORT", "1025")),
 		SMTPUsername: getenv("SMTP_USERNAME", ""),
 		SMTPPassword: getenv("SMTP_PASSWORD", ""),
-		SMTPTLSMode:  getenv("SMTP_TLS_MODE", "none"),
+		SMTPTLSMode:  getenv("SMTP_TLS_MODE", ""),
+
+		SMTPBounceAddress: getenv("SMTP_BOUNCE_ADDRESS", ""),
+		SMTPHeloName:      getenv("SMTP_HELO_NAME", ""),
+
+		// DKIM
+		DKIMDomain:     getenv("DKIM_DOMAIN", ""),
+		DKIMSelector:   getenv("DKIM_SELECTOR", ""),
+		DKIMPrivateKey: os.Getenv("DKIM_PRIVATE_KEY"),
 	}
 
 	return config
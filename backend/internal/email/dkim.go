@@ -0,0 +1,188 @@
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dkimSignedHeaders are signed when present. From is required by RFC 6376.
+var dkimSignedHeaders = []string{
+	"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type",
+	"List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// DKIMSigner adds DKIM-Signature headers (RFC 6376) using relaxed/relaxed
+// canonicalization. RSA keys sign with rsa-sha256, Ed25519 keys with
+// ed25519-sha256 (RFC 8463).
+type DKIMSigner struct {
+	Domain   string
+	Selector string
+	key      crypto.Signer
+}
+
+// NewDKIMSigner parses a PEM encoded PKCS#1 or PKCS#8 private key
+func NewDKIMSigner(domain, selector, privateKeyPEM string) (*DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return nil, errors.New("DKIM domain and selector are required")
+	}
+
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return nil, errors.New("DKIM private key is not PEM encoded")
+	}
+
+	var key crypto.Signer
+	if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = rsaKey
+	} else {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DKIM private key: %w", err)
+		}
+		switch k := parsed.(type) {
+		case *rsa.PrivateKey:
+			key = k
+		case ed25519.PrivateKey:
+			key = k
+		default:
+			return nil, fmt.Errorf("unsupported DKIM key type %T", parsed)
+		}
+	}
+
+	return &DKIMSigner{Domain: domain, Selector: selector, key: key}, nil
+}
+
+// Sign returns message with a DKIM-Signature header prepended. message must
+// use CRLF line endings.
+func (s *DKIMSigner) Sign(message []byte, now time.Time) ([]byte, error) {
+	headerEnd := bytes.Index(message, []byte("\r\n\r\n"))
+	if headerEnd < 0 {
+		return nil, errors.New("message has no header/body separator")
+	}
+	headers := parseHeaders(string(message[:headerEnd+2]))
+	body := message[headerEnd+4:]
+
+	bodyHash := sha256.Sum256(relaxedBody(body))
+
+	var signed []string
+	var canonical strings.Builder
+	for _, name := range dkimSignedHeaders {
+		if value, ok := headers[strings.ToLower(name)]; ok {
+			signed = append(signed, strings.ToLower(name))
+			canonical.WriteString(relaxedHeader(name, value))
+			canonical.WriteString("\r\n")
+		}
+	}
+
+	algorithm := "rsa-sha256"
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		algorithm = "ed25519-sha256"
+	}
+	signatureValue := fmt.Sprintf("v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d; h=%s; bh=%s; b=",
+		algorithm, s.Domain, s.Selector, now.Unix(), strings.Join(signed, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+	// The signature header itself is signed with an empty b= and no trailing CRLF
+	canonical.WriteString(relaxedHeader("DKIM-Signature", signatureValue))
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+	var signature []byte
+	var err error
+	if algorithm == "ed25519-sha256" {
+		signature, err = s.key.Sign(rand.Reader, digest[:], crypto.Hash(0))
+	} else {
+		signature, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	header := "DKIM-Signature: " + signatureValue + foldBase64(base64.StdEncoding.EncodeToString(signature)) + "\r\n"
+	return append([]byte(header), message...), nil
+}
+
+// parseHeaders returns the unfolded value of each header by lowercase name.
+// When a header repeats, the last instance wins, as DKIM verifiers sign from
+// the bottom up.
+func parseHeaders(raw string) map[string]string {
+	headers := map[string]string{}
+	var name, value string
+	flush := func() {
+		if name != "" {
+			headers[strings.ToLower(name)] = value
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(raw, "\r\n"), "\r\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			value += "\r\n" + line
+			continue
+		}
+		flush()
+		name, value, _ = strings.Cut(line, ":")
+	}
+	flush()
+	return headers
+}
+
+// relaxedHeader canonicalizes one header field (RFC 6376 section 3.4.2)
+func relaxedHeader(name, value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "")
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(collapseWhitespace(value))
+}
+
+// relaxedBody canonicalizes a body (RFC 6376 section 3.4.4)
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// foldBase64 wraps a long signature so header lines stay under 78 characters
+func foldBase64(s string) string {
+	var b strings.Builder
+	for len(s) > 64 {
+		b.WriteString(s[:64])
+		b.WriteString("\r\n\t")
+		s = s[64:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/smtp"
+	"time"
 )
 
 // MailHogService sends emails using SMTP (primarily for MailHog testing)
@@ -19,105 +20,16 @@ func NewMailHogService(config EmailConfig) (*MailHogService, error) {
 	}, nil
 }
 
-// SendMagicLink sends a magic link email via SMTP
-func (s *MailHogService) SendMagicLink(ctx context.Context, toEmail, token, returnURL string) error {
-	// Default to leaderboard page if returnURL is /login or empty
-	if returnURL == "" || returnURL == "/login" {
-		returnURL = "/leaderboard"
-	}
-
-	// Build magic link URL
-	magicURL := fmt.Sprintf("%s/auth/login?apikey=%s", s.config.BaseURL, token)
-	if returnURL != "" {
-		magicURL += fmt.Sprintf("&return_url=%s", returnURL)
-	}
-
-	subject := "Logga in på Klubbspel"
-
-	// Plain text version for SMTP (Swedish)
-	body := fmt.Sprintf(`Hej!
-
-Klicka på länken nedan för att logga in på Klubbspel:
-
-%s
-
-Denna länk går ut om 15 minuter av säkerhetsskäl.
-
-Om du inte begärt denna inloggning kan du bortse från detta mejl.
-
-Med vänliga hälsningar,
-Klubbspel-teamet`, magicURL)
-
-	return s.SendEmail(ctx, toEmail, subject, body)
-}
-
-// SendClubInvitationMagicLink sends a club invitation email with magic link
-func (s *MailHogService) SendClubInvitationMagicLink(ctx context.Context, toEmail, token, returnURL, clubName, inviterName, inviterEmail string) error {
-	// Default to root page if returnURL is /login or empty
-	if returnURL == "" || returnURL == "/login" {
-		returnURL = "/"
-	}
-
-	// Build magic link URL
-	magicURL := fmt.Sprintf("%s/auth/login?apikey=%s", s.config.BaseURL, token)
-	if returnURL != "" {
-		magicURL += fmt.Sprintf("&return_url=%s", returnURL)
-	}
-
-	subject := fmt.Sprintf("Du har blivit tillagd som medlem i %s", clubName)
-
-	// Enhanced body with club and inviter context (Swedish)
-	body := fmt.Sprintf(`Hej!
-
-%s (%s) har lagt till dig som medlem i klubben "%s" på Klubbspel.
-
-Klicka på länken nedan för att logga in och se klubbdetaljer:
-
-%s
-
-Denna länk går ut om 24 timmar av säkerhetsskäl.
-
-Om du har frågor kan du kontakta %s på %s.
-
-Med vänliga hälsningar,
-Klubbspel-teamet`, inviterName, inviterEmail, clubName, magicURL, inviterName, inviterEmail)
-
-	return s.SendEmail(ctx, toEmail, subject, body)
-}
-
-// SendInvitation sends an invitation email via SMTP
-func (s *MailHogService) SendInvitation(ctx context.Context, toEmail, clubName, inviterName string) error {
-	subject := fmt.Sprintf("Invitation to join %s on Klubbspel", clubName)
-
-	body := fmt.Sprintf(`Hi there!
-
-%s has invited you to join the club "%s" on Klubbspel, the table tennis tournament management platform.
-
-To accept this invitation and create your account, click the link below:
-
-%s/join?club=%s
-
-About Klubbspel:
-Klubbspel helps table tennis clubs organize tournaments, track player rankings, and manage club memberships. Join to participate in tournaments and track your progress!
-
-If you have any questions, feel free to contact %s or visit our help center.
-
-Best regards,
-The Klubbspel Team`, inviterName, clubName, s.config.BaseURL, clubName, inviterName)
-
-	return s.SendEmail(ctx, toEmail, subject, body)
-}
-
-// SendEmail sends a generic email via SMTP
-func (s *MailHogService) SendEmail(ctx context.Context, toEmail, subject, body string) error {
+// Send delivers a rendered message via SMTP
+func (s *MailHogService) Send(ctx context.Context, msg *Message) error {
 	// For development/testing, if no SMTP is configured, just log
 	if s.config.SMTPHost == "" || s.config.SMTPPort == 0 {
-		fmt.Printf("[EMAIL] To: %s\nSubject: %s\nBody:\n%s\n\n", toEmail, subject, body)
+		fmt.Printf("[EMAIL] To: %s\nSubject: %s\nBody:\n%s\n\n", msg.To, msg.Subject, msg.Text)
 		return nil
 	}
 
 	// Create the message
-	msg := s.buildSMTPMessage(toEmail, subject, body)
+	data := buildMIMEMessage(s.config.FromName, s.config.FromEmail, msg, time.Now())
 
 	// Determine auth
 	var auth smtp.Auth
@@ -131,28 +43,15 @@ func (s *MailHogService) SendEmail(ctx context.Context, toEmail, subject, body s
 	// Send based on TLS mode
 	switch s.config.SMTPTLSMode {
 	case "tls":
-		return s.sendWithTLS(addr, auth, []string{toEmail}, msg)
+		return s.sendWithTLS(addr, auth, []string{msg.To}, data)
 	case "starttls":
-		return s.sendWithStartTLS(addr, auth, []string{toEmail}, msg)
+		return s.sendWithStartTLS(addr, auth, []string{msg.To}, data)
 	default:
 		// Plain SMTP (for MailHog)
-		return smtp.SendMail(addr, auth, s.config.FromEmail, []string{toEmail}, msg)
+		return smtp.SendMail(addr, auth, s.config.FromEmail, []string{msg.To}, data)
 	}
 }
 
-// buildSMTPMessage builds a properly formatted SMTP message
-func (s *MailHogService) buildSMTPMessage(toEmail, subject, body string) []byte {
-	message := fmt.Sprintf("From: %s <%s>\r\n", s.config.FromName, s.config.FromEmail)
-	message += fmt.Sprintf("To: %s\r\n", toEmail)
-	message += fmt.Sprintf("Subject: %s\r\n", subject)
-	message += "MIME-Version: 1.0\r\n"
-	message += "Content-Type: text/plain; charset=UTF-8\r\n"
-	message += "\r\n"
-	message += body
-
-	return []byte(message)
-}
-
 // sendWithTLS sends email over TLS connection
 func (s *MailHogService) sendWithTLS(addr string, auth smtp.Auth, to []string, msg []byte) error {
 	// Create TLS connection with secure configuration (G402 fix)
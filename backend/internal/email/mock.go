@@ -2,14 +2,15 @@ package email
 
 import (
 	"context"
-	"fmt"
 	"log"
+	"sync"
 	"time"
 )
 
 // MockEmailService provides a mock implementation for testing
 type MockEmailService struct {
 	config     EmailConfig
+	mu         sync.Mutex
 	sentEmails []MockEmail
 }
 
@@ -17,9 +18,11 @@ type MockEmailService struct {
 type MockEmail struct {
 	To        string
 	Subject   string
-	Body      string
+	Body      string // Plain-text part
+	HTML      string
+	Headers   map[string]string
 	Timestamp time.Time
-	Type      string // "magic_link" or "invitation"
+	Type      string // Template name, e.g. "magic_link", or "generic"
 }
 
 // NewMockEmailService creates a new mock email service instance
@@ -30,154 +33,44 @@ func NewMockEmailService(config EmailConfig) (*MockEmailService, error) {
 	}, nil
 }
 
-// SendMagicLink logs a magic link email (mock implementation)
-func (s *MockEmailService) SendMagicLink(ctx context.Context, toEmail, token, returnURL string) error {
-	// Default to leaderboard page if returnURL is /login or empty
-	if returnURL == "" || returnURL == "/login" {
-		returnURL = "/leaderboard"
-	}
-
-	// Build magic link URL
-	magicURL := fmt.Sprintf("%s/auth/login?apikey=%s", s.config.BaseURL, token)
-	if returnURL != "" {
-		magicURL += fmt.Sprintf("&return_url=%s", returnURL)
-	}
-
-	subject := "Logga in på Klubbspel"
-	body := fmt.Sprintf(`Hej!
-
-Klicka på länken nedan för att logga in på Klubbspel:
-
-%s
-
-Denna länk går ut om 15 minuter av säkerhetsskäl.
-
-Om du inte begärt denna inloggning kan du bortse från detta mejl.
-
-Med vänliga hälsningar,
-Klubbspel-teamet`, magicURL)
-
-	// Store the mock email
-	mockEmail := MockEmail{
-		To:        toEmail,
-		Subject:   subject,
-		Body:      body,
-		Timestamp: time.Now(),
-		Type:      "magic_link",
-	}
-	s.sentEmails = append(s.sentEmails, mockEmail)
-
-	// Log for debugging
-	log.Printf("[MOCK EMAIL] Magic Link sent to %s: %s", toEmail, magicURL)
-
-	return nil
-}
-
-// SendClubInvitationMagicLink logs a club invitation magic link email (mock implementation)
-func (s *MockEmailService) SendClubInvitationMagicLink(ctx context.Context, toEmail, token, returnURL, clubName, inviterName, inviterEmail string) error {
-	// Default to root page if returnURL is /login or empty
-	if returnURL == "" || returnURL == "/login" {
-		returnURL = "/"
-	}
-
-	// Build magic link URL
-	magicURL := fmt.Sprintf("%s/auth/login?apikey=%s", s.config.BaseURL, token)
-	if returnURL != "" {
-		magicURL += fmt.Sprintf("&return_url=%s", returnURL)
-	}
-
-	subject := fmt.Sprintf("Du har blivit tillagd som medlem i %s", clubName)
-	body := fmt.Sprintf(`Hej!
-
-%s (%s) har lagt till dig som medlem i klubben "%s" på Klubbspel.
-
-Klicka på länken nedan för att logga in och se klubbdetaljer:
-
-%s
+// Send records a rendered message (mock implementation)
+func (s *MockEmailService) Send(ctx context.Context, msg *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-Denna länk går ut om 24 timmar av säkerhetsskäl.
-
-Om du har frågor kan du kontakta %s på %s.
-
-Med vänliga hälsningar,
-Klubbspel-teamet`, inviterName, inviterEmail, clubName, magicURL, inviterName, inviterEmail)
-
-	// Store the mock email
-	mockEmail := MockEmail{
-		To:        toEmail,
-		Subject:   subject,
-		Body:      body,
-		Timestamp: time.Now(),
-		Type:      "club_invitation_magic_link",
+	headers := map[string]string{}
+	if msg.ListUnsubscribe != "" {
+		headers["List-Unsubscribe"] = "<" + msg.ListUnsubscribe + ">"
+		headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
 	}
-	s.sentEmails = append(s.sentEmails, mockEmail)
-
-	// Log for debugging
-	log.Printf("[MOCK EMAIL] Club invitation magic link sent to %s for club %s by %s (%s): %s", toEmail, clubName, inviterName, inviterEmail, magicURL)
-
-	return nil
-}
-
-// SendInvitation logs an invitation email (mock implementation)
-func (s *MockEmailService) SendInvitation(ctx context.Context, toEmail, clubName, inviterName string) error {
-	subject := fmt.Sprintf("Invitation att gå med i %s på Klubbspel", clubName)
-	body := fmt.Sprintf(`Hej!
-
-%s har bjudit in dig att gå med i klubben "%s" på Klubbspel, plattformen för hantering av bordtennisturneringar.
-
-För att acceptera denna inbjudan och skapa ditt konto, klicka på länken nedan:
-
-%s/join?club=%s
 
-Om Klubbspel:
-Klubbspel hjälper bordtennisklubbar att organisera turneringar, följa spelarranking och hantera medlemskap i klubben. Gå med för att delta i turneringar och följa din utveckling!
-
-Om du har några frågor, tveka inte att kontakta %s eller besök vårt hjälpcenter.
-
-Med vänliga hälsningar,
-Klubbspel-teamet`, inviterName, clubName, s.config.BaseURL, clubName, inviterName)
-
-	// Store the mock email
-	mockEmail := MockEmail{
-		To:        toEmail,
-		Subject:   subject,
-		Body:      body,
-		Timestamp: time.Now(),
-		Type:      "invitation",
-	}
-	s.sentEmails = append(s.sentEmails, mockEmail)
-
-	// Log for debugging
-	log.Printf("[MOCK EMAIL] Invitation sent to %s for club %s", toEmail, clubName)
-
-	return nil
-}
-
-// SendEmail logs a generic email (mock implementation)
-func (s *MockEmailService) SendEmail(ctx context.Context, toEmail, subject, body string) error {
-	// Store the mock email
-	mockEmail := MockEmail{
-		To:        toEmail,
-		Subject:   subject,
-		Body:      body,
+	s.sentEmails = append(s.sentEmails, MockEmail{
+		To:        msg.To,
+		Subject:   msg.Subject,
+		Body:      msg.Text,
+		HTML:      msg.HTML,
+		Headers:   headers,
 		Timestamp: time.Now(),
-		Type:      "generic",
-	}
-	s.sentEmails = append(s.sentEmails, mockEmail)
+		Type:      msg.Template,
+	})
 
 	// Log for debugging
-	log.Printf("[MOCK EMAIL] Generic email sent to %s: %s", toEmail, subject)
+	log.Printf("[MOCK EMAIL] %s sent to %s: %s", msg.Template, msg.To, msg.Subject)
 
 	return nil
 }
 
 // GetSentEmails returns all emails that were "sent" via this mock service
 func (s *MockEmailService) GetSentEmails() []MockEmail {
-	return s.sentEmails
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MockEmail(nil), s.sentEmails...)
 }
 
 // GetLastEmail returns the most recently sent email, or nil if none
 func (s *MockEmailService) GetLastEmail() *MockEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if len(s.sentEmails) == 0 {
 		return nil
 	}
@@ -186,6 +79,8 @@ func (s *MockEmailService) GetLastEmail() *MockEmail {
 
 // GetEmailsForRecipient returns all emails sent to a specific recipient
 func (s *MockEmailService) GetEmailsForRecipient(email string) []MockEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	var result []MockEmail
 	for _, sent := range s.sentEmails {
 		if sent.To == email {
@@ -197,10 +92,14 @@ func (s *MockEmailService) GetEmailsForRecipient(email string) []MockEmail {
 
 // ClearSentEmails clears the sent emails list (useful for tests)
 func (s *MockEmailService) ClearSentEmails() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.sentEmails = make([]MockEmail, 0)
 }
 
 // CountSentEmails returns the total number of emails sent
 func (s *MockEmailService) CountSentEmails() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return len(s.sentEmails)
 }
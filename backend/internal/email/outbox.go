@@ -0,0 +1,166 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// Outbox delivery defaults
+const (
+	DefaultMaxAttempts  = 8
+	DefaultBaseDelay    = 30 * time.Second // Delay before the first retry; doubles per attempt
+	DefaultMaxDelay     = time.Hour
+	DefaultPollInterval = 2 * time.Second
+	DefaultLease        = 2 * time.Minute // How long a claimed email is reserved for one worker
+)
+
+// OutboxStore persists queued emails; *repo.EmailOutboxRepo implements it
+type OutboxStore interface {
+	Enqueue(ctx context.Context, email *repo.OutboxEmail) error
+	ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*repo.OutboxEmail, error)
+	MarkSent(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, now, nextAttemptAt time.Time, lastError string, dead bool) error
+}
+
+// OutboxTransport queues messages instead of sending them, so callers do not
+// depend on the provider being up. An OutboxWorker delivers them.
+type OutboxTransport struct {
+	Store OutboxStore
+	Now   func() time.Time // Defaults to time.Now
+}
+
+// Send enqueues msg for delivery
+func (t *OutboxTransport) Send(ctx context.Context, msg *Message) error {
+	return t.Store.Enqueue(ctx, &repo.OutboxEmail{
+		To:              msg.To,
+		Subject:         msg.Subject,
+		Text:            msg.Text,
+		HTML:            msg.HTML,
+		Template:        msg.Template,
+		ListUnsubscribe: msg.ListUnsubscribe,
+		CreatedAt:       now(t.Now),
+	})
+}
+
+// OutboxWorker delivers queued emails through a provider transport, retrying
+// failures with exponential backoff and dead-lettering after MaxAttempts
+type OutboxWorker struct {
+	Store     OutboxStore
+	Transport Transport
+	Now       func() time.Time // Defaults to time.Now; tests inject a fake clock
+
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	PollInterval time.Duration
+	Lease        time.Duration
+}
+
+// NewOutboxWorker creates a worker with the default retry policy
+func NewOutboxWorker(store OutboxStore, transport Transport) *OutboxWorker {
+	return &OutboxWorker{
+		Store:        store,
+		Transport:    transport,
+		MaxAttempts:  DefaultMaxAttempts,
+		BaseDelay:    DefaultBaseDelay,
+		MaxDelay:     DefaultMaxDelay,
+		PollInterval: DefaultPollInterval,
+		Lease:        DefaultLease,
+	}
+}
+
+// Run delivers due emails until ctx is cancelled
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("Email outbox processing failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue delivers every email that is due now and returns how many were
+// attempted
+func (w *OutboxWorker) ProcessDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for ctx.Err() == nil {
+		email, err := w.Store.ClaimNext(ctx, now(w.Now), w.Lease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return attempted, nil
+		}
+		if err != nil {
+			return attempted, err
+		}
+		attempted++
+
+		if err := w.deliver(ctx, email); err != nil {
+			return attempted, err
+		}
+	}
+	return attempted, ctx.Err()
+}
+
+// deliver sends one claimed email and records the outcome
+func (w *OutboxWorker) deliver(ctx context.Context, email *repo.OutboxEmail) error {
+	sendErr := w.Transport.Send(ctx, &Message{
+		To:              email.To,
+		Subject:         email.Subject,
+		Text:            email.Text,
+		HTML:            email.HTML,
+		Template:        email.Template,
+		ListUnsubscribe: email.ListUnsubscribe,
+	})
+
+	at := now(w.Now)
+	if sendErr == nil {
+		return w.Store.MarkSent(ctx, email.ID, at)
+	}
+
+	dead := email.Attempts >= w.MaxAttempts
+	next := at.Add(w.backoff(email.Attempts))
+	log.Warn().
+		Err(sendErr).
+		Str("email_id", email.ID.Hex()).
+		Str("template", email.Template).
+		Int("attempt", email.Attempts).
+		Bool("dead_letter", dead).
+		Time("next_attempt_at", next).
+		Msg("Email delivery failed")
+
+	return w.Store.MarkFailed(ctx, email.ID, at, next, sendErr.Error(), dead)
+}
+
+// backoff returns the delay after the given failed attempt: BaseDelay,
+// 2*BaseDelay, 4*BaseDelay, ... capped at MaxDelay
+func (w *OutboxWorker) backoff(attempt int) time.Duration {
+	delay := w.BaseDelay
+	for i := 1; i < attempt && delay < w.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > w.MaxDelay {
+		delay = w.MaxDelay
+	}
+	return delay
+}
+
+func now(clock func() time.Time) time.Time {
+	if clock != nil {
+		return clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,170 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// memoryOutbox is an in-memory OutboxStore with the same claim rules as the Mongo repo
+type memoryOutbox struct {
+	emails []*repo.OutboxEmail
+}
+
+func (m *memoryOutbox) Enqueue(ctx context.Context, email *repo.OutboxEmail) error {
+	email.ID = primitive.NewObjectID()
+	email.Status = repo.OutboxStatusPending
+	email.NextAttemptAt = email.CreatedAt
+	m.emails = append(m.emails, email)
+	return nil
+}
+
+func (m *memoryOutbox) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*repo.OutboxEmail, error) {
+	sort.SliceStable(m.emails, func(i, j int) bool { return m.emails[i].NextAttemptAt.Before(m.emails[j].NextAttemptAt) })
+	for _, email := range m.emails {
+		due := email.Status == repo.OutboxStatusPending && !email.NextAttemptAt.After(now)
+		expired := email.Status == repo.OutboxStatusSending && !email.LockedUntil.After(now)
+		if due || expired {
+			locked := now.Add(lease)
+			email.Status, email.LockedUntil = repo.OutboxStatusSending, &locked
+			email.Attempts++
+			return email, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (m *memoryOutbox) MarkSent(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	email := m.find(id)
+	email.Status, email.SentAt, email.LockedUntil = repo.OutboxStatusSent, &now, nil
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(ctx context.Context, id primitive.ObjectID, now, nextAttemptAt time.Time, lastError string, dead bool) error {
+	email := m.find(id)
+	email.Status, email.NextAttemptAt, email.LastError, email.LockedUntil = repo.OutboxStatusPending, nextAttemptAt, lastError, nil
+	if dead {
+		email.Status = repo.OutboxStatusDead
+	}
+	return nil
+}
+
+func (m *memoryOutbox) find(id primitive.ObjectID) *repo.OutboxEmail {
+	for _, email := range m.emails {
+		if email.ID == id {
+			return email
+		}
+	}
+	return nil
+}
+
+// flakyTransport fails the first failures sends, then delegates
+type flakyTransport struct {
+	failures int
+	next     Transport
+}
+
+func (f *flakyTransport) Send(ctx context.Context, msg *Message) error {
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("provider unavailable")
+	}
+	return f.next.Send(ctx, msg)
+}
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newOutboxFixture(t *testing.T, failures int) (*EmailAdapter, *OutboxWorker, *memoryOutbox, *MockEmailService, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	store := &memoryOutbox{}
+	mock, err := NewMockEmailService(EmailConfig{})
+	require.NoError(t, err)
+
+	adapter, err := NewEmailAdapterWithTransport(EmailConfig{BaseURL: "https://klubbspel.se"}, &OutboxTransport{Store: store, Now: clock.Now})
+	require.NoError(t, err)
+
+	worker := NewOutboxWorker(store, &flakyTransport{failures: failures, next: mock})
+	worker.Now = clock.Now
+	worker.MaxAttempts = 3
+	return adapter, worker, store, mock, clock
+}
+
+func TestOutboxDeliversQueuedEmail(t *testing.T) {
+	ctx := context.Background()
+	adapter, worker, store, mock, _ := newOutboxFixture(t, 0)
+
+	require.NoError(t, adapter.SendMagicLink(ctx, "anna@example.com", "en", "tok", ""))
+	require.Zero(t, mock.CountSentEmails(), "sending only enqueues")
+
+	attempted, err := worker.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempted)
+	require.Equal(t, "Sign in to Klubbspel", mock.GetLastEmail().Subject)
+	require.Equal(t, repo.OutboxStatusSent, store.emails[0].Status)
+}
+
+func TestOutboxRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	adapter, worker, store, mock, clock := newOutboxFixture(t, 2)
+
+	require.NoError(t, adapter.SendEmail(ctx, "anna@example.com", "Hello", "Body"))
+
+	_, err := worker.ProcessDue(ctx)
+	require.NoError(t, err)
+	email := store.emails[0]
+	require.Equal(t, repo.OutboxStatusPending, email.Status)
+	require.Equal(t, "provider unavailable", email.LastError)
+	require.Equal(t, clock.Now().Add(DefaultBaseDelay), email.NextAttemptAt)
+
+	// Not due yet
+	attempted, err := worker.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, attempted)
+
+	// Second failure doubles the delay
+	clock.Advance(DefaultBaseDelay)
+	_, err = worker.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, clock.Now().Add(2*DefaultBaseDelay), email.NextAttemptAt)
+
+	clock.Advance(2 * DefaultBaseDelay)
+	_, err = worker.ProcessDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, repo.OutboxStatusSent, email.Status)
+	require.Equal(t, 3, email.Attempts)
+	require.Equal(t, 1, mock.CountSentEmails())
+}
+
+func TestOutboxDeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	adapter, worker, store, mock, clock := newOutboxFixture(t, 10)
+
+	require.NoError(t, adapter.SendEmail(ctx, "anna@example.com", "Hello", "Body"))
+	for i := 0; i < 5; i++ {
+		_, err := worker.ProcessDue(ctx)
+		require.NoError(t, err)
+		clock.Advance(DefaultMaxDelay)
+	}
+
+	require.Equal(t, repo.OutboxStatusDead, store.emails[0].Status)
+	require.Equal(t, 3, store.emails[0].Attempts)
+	require.Zero(t, mock.CountSentEmails())
+}
+
+func TestOutboxBackoffIsCapped(t *testing.T) {
+	worker := NewOutboxWorker(nil, nil)
+	require.Equal(t, DefaultBaseDelay, worker.backoff(1))
+	require.Equal(t, 4*DefaultBaseDelay, worker.backoff(3))
+	require.Equal(t, DefaultMaxDelay, worker.backoff(20))
+}
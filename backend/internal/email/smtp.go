@@ -0,0 +1,296 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SMTP TLS modes
+const (
+	SMTPTLSNone     = "none"     // Plain text; only for local relays such as MailHog
+	SMTPTLSImplicit = "tls"      // TLS from the first byte, usually port 465
+	SMTPTLSStartTLS = "starttls" // Upgrade with STARTTLS, usually port 587; the server must support it
+)
+
+// SMTP connection defaults
+const (
+	DefaultSMTPTimeout       = 30 * time.Second // Per message, including connecting
+	DefaultSMTPMaxIdle       = time.Minute      // Idle connections older than this are reopened
+	DefaultSMTPMaxPerConnect = 100              // Messages sent before the connection is recycled
+)
+
+// SMTPTransport delivers messages through an SMTP server. One connection is
+// kept open and reused between messages; it is reopened after errors, after
+// MaxIdle without traffic and after MaxPerConnection messages.
+type SMTPTransport struct {
+	config EmailConfig
+	dkim   *DKIMSigner
+
+	Timeout          time.Duration
+	MaxIdle          time.Duration
+	MaxPerConnection int
+	Now              func() time.Time // Defaults to time.Now
+
+	mu       sync.Mutex
+	client   *smtp.Client
+	conn     net.Conn
+	lastUsed time.Time
+	sent     int
+}
+
+// NewSMTPTransport creates an SMTP transport. The TLS mode defaults to
+// STARTTLS and DKIM signing is enabled when a DKIM key is configured.
+func NewSMTPTransport(config EmailConfig) (*SMTPTransport, error) {
+	if config.SMTPHost == "" || config.SMTPPort == 0 {
+		return nil, errors.New("SMTP host and port are required")
+	}
+
+	switch config.SMTPTLSMode {
+	case "":
+		config.SMTPTLSMode = SMTPTLSStartTLS
+	case SMTPTLSNone, SMTPTLSImplicit, SMTPTLSStartTLS:
+	default:
+		return nil, fmt.Errorf("unknown SMTP TLS mode %q", config.SMTPTLSMode)
+	}
+	if config.SMTPTLSMode == SMTPTLSNone {
+		log.Warn().Str("host", config.SMTPHost).Msg("SMTP TLS is disabled; credentials and emails are sent in clear text")
+	}
+
+	t := &SMTPTransport{
+		config:           config,
+		Timeout:          DefaultSMTPTimeout,
+		MaxIdle:          DefaultSMTPMaxIdle,
+		MaxPerConnection: DefaultSMTPMaxPerConnect,
+	}
+
+	if config.DKIMPrivateKey != "" {
+		signer, err := NewDKIMSigner(config.DKIMDomain, config.DKIMSelector, config.DKIMPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		t.dkim = signer
+	}
+
+	return t, nil
+}
+
+// Send delivers msg, reusing the open connection when possible
+func (t *SMTPTransport) Send(ctx context.Context, msg *Message) error {
+	data := buildMIMEMessage(t.config.FromName, t.config.FromEmail, msg, now(t.Now))
+	if t.dkim != nil {
+		signed, err := t.dkim.Sign(data, now(t.Now))
+		if err != nil {
+			return err
+		}
+		data = signed
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.ensureConnected(ctx); err != nil {
+		return err
+	}
+
+	if err := t.send(msg.To, data); err != nil {
+		// The connection may be mid-transaction or broken; start over next time
+		t.closeLocked()
+		return err
+	}
+
+	t.lastUsed = now(t.Now)
+	t.sent++
+	if t.sent >= t.MaxPerConnection {
+		t.closeLocked()
+	}
+	return nil
+}
+
+// Close quits the open connection, if any
+func (t *SMTPTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeLocked()
+	return nil
+}
+
+// send runs one mail transaction on the open connection
+func (t *SMTPTransport) send(to string, data []byte) error {
+	if err := t.client.Mail(t.envelopeSender()); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	if err := t.client.Rcpt(to); err != nil {
+		return fmt.Errorf("failed to set recipient %s: %w", to, err)
+	}
+
+	w, err := t.client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send DATA command: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+	return nil
+}
+
+// ensureConnected reuses the open connection when it is fresh and still
+// answers, and dials a new one otherwise. The deadline covers the whole send.
+func (t *SMTPTransport) ensureConnected(ctx context.Context) error {
+	deadline := now(t.Now).Add(t.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if t.client != nil {
+		if now(t.Now).Sub(t.lastUsed) < t.MaxIdle {
+			_ = t.conn.SetDeadline(deadline)
+			if err := t.client.Reset(); err == nil {
+				return nil
+			}
+		}
+		t.closeLocked()
+	}
+
+	addr := net.JoinHostPort(t.config.SMTPHost, strconv.Itoa(t.config.SMTPPort))
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if t.config.SMTPTLSMode == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: t.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, t.config.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("failed to create SMTP client: %w", err)
+	}
+	t.client, t.conn, t.sent = client, conn, 0
+
+	if err := t.handshake(); err != nil {
+		t.closeLocked()
+		return err
+	}
+	return nil
+}
+
+// handshake greets the server, upgrades to TLS and authenticates
+func (t *SMTPTransport) handshake() error {
+	if t.config.SMTPHeloName != "" {
+		if err := t.client.Hello(t.config.SMTPHeloName); err != nil {
+			return fmt.Errorf("SMTP EHLO failed: %w", err)
+		}
+	}
+
+	if t.config.SMTPTLSMode == SMTPTLSStartTLS {
+		if ok, _ := t.client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := t.client.StartTLS(t.tlsConfig()); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if t.config.SMTPUsername == "" {
+		return nil
+	}
+	ok, mechanisms := t.client.Extension("AUTH")
+	if !ok {
+		return errors.New("SMTP server does not support authentication")
+	}
+	if err := t.client.Auth(t.auth(mechanisms)); err != nil {
+		return fmt.Errorf("SMTP authentication failed: %w", err)
+	}
+	return nil
+}
+
+// auth picks PLAIN, or LOGIN for servers that only offer that
+func (t *SMTPTransport) auth(mechanisms string) smtp.Auth {
+	offered := strings.Fields(strings.ToUpper(mechanisms))
+	for _, m := range offered {
+		if m == "PLAIN" {
+			return smtp.PlainAuth("", t.config.SMTPUsername, t.config.SMTPPassword, t.config.SMTPHost)
+		}
+	}
+	for _, m := range offered {
+		if m == "LOGIN" {
+			return &loginAuth{username: t.config.SMTPUsername, password: t.config.SMTPPassword}
+		}
+	}
+	return smtp.PlainAuth("", t.config.SMTPUsername, t.config.SMTPPassword, t.config.SMTPHost)
+}
+
+func (t *SMTPTransport) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName: t.config.SMTPHost,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// envelopeSender is the MAIL FROM address, where bounces are delivered
+func (t *SMTPTransport) envelopeSender() string {
+	if t.config.SMTPBounceAddress != "" {
+		return t.config.SMTPBounceAddress
+	}
+	return t.config.FromEmail
+}
+
+func (t *SMTPTransport) closeLocked() {
+	if t.client == nil {
+		return
+	}
+	if err := t.client.Quit(); err != nil {
+		_ = t.client.Close()
+	}
+	t.client, t.conn, t.sent = nil, nil, 0
+}
+
+// loginAuth implements the non-standard but widespread LOGIN mechanism. Like
+// smtp.PlainAuth it refuses to send credentials over an unencrypted connection
+// to a remote host.
+type loginAuth struct {
+	username, password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
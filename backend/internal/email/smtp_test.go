@@ -0,0 +1,189 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer is a minimal SMTP server that records transactions
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	connections int
+	mailFrom    []string
+	messages    []string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.connections++
+			s.mu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch command {
+		case "EHLO":
+			reply("250-fake")
+			reply("250 AUTH PLAIN LOGIN")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.mailFrom = append(s.mailFrom, strings.TrimSpace(line))
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default: // RCPT, RSET, NOOP
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestSMTPTransportReusesConnectionAndSignsWithDKIM(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeSMTPServer(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	transport, err := NewSMTPTransport(EmailConfig{
+		FromName:          "Klubbspel",
+		FromEmail:         "noreply@klubbspel.se",
+		SMTPHost:          "127.0.0.1",
+		SMTPPort:          server.port(),
+		SMTPUsername:      "club",
+		SMTPPassword:      "secret",
+		SMTPTLSMode:       SMTPTLSNone,
+		SMTPBounceAddress: "bounces@klubbspel.se",
+		DKIMDomain:        "klubbspel.se",
+		DKIMSelector:      "mail",
+		DKIMPrivateKey:    string(keyPEM),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = transport.Close() })
+
+	for _, to := range []string{"anna@example.com", "bo@example.com"} {
+		require.NoError(t, transport.Send(ctx, &Message{
+			To:       to,
+			Subject:  "Välkommen till Klubbspel",
+			Text:     "Hej!\n\n.Rad som börjar med punkt\n",
+			HTML:     "<p>Hej!</p>",
+			Template: "generic",
+		}))
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Equal(t, 1, server.connections, "the second message reuses the connection")
+	require.Len(t, server.messages, 2)
+	require.Equal(t, "MAIL FROM:<bounces@klubbspel.se>", server.mailFrom[0])
+
+	message := server.messages[0]
+	require.True(t, strings.HasPrefix(message, "DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=klubbspel.se; s=mail;"))
+	require.Contains(t, message, "Content-Transfer-Encoding: quoted-printable")
+	verifyDKIM(t, message, &key.PublicKey)
+}
+
+func TestSMTPTransportRequiresKnownTLSMode(t *testing.T) {
+	_, err := NewSMTPTransport(EmailConfig{SMTPHost: "mail.example.com", SMTPPort: 587, SMTPTLSMode: "ssl"})
+	require.Error(t, err)
+
+	transport, err := NewSMTPTransport(EmailConfig{SMTPHost: "mail.example.com", SMTPPort: 587})
+	require.NoError(t, err)
+	require.Equal(t, SMTPTLSStartTLS, transport.config.SMTPTLSMode, "STARTTLS is the default")
+}
+
+// verifyDKIM checks a relaxed/relaxed rsa-sha256 signature the way a receiving server would
+func verifyDKIM(t *testing.T, message string, pub *rsa.PublicKey) {
+	t.Helper()
+	headerPart, body, ok := strings.Cut(message, "\r\n\r\n")
+	require.True(t, ok)
+	headers := parseHeaders(headerPart + "\r\n")
+
+	rawSignature := headers["dkim-signature"]
+	tags := map[string]string{}
+	for _, tag := range strings.Split(strings.Join(strings.Fields(rawSignature), ""), ";") {
+		name, value, _ := strings.Cut(tag, "=")
+		tags[name] = value
+	}
+
+	bodyHash := sha256.Sum256(relaxedBody([]byte(body)))
+	require.Equal(t, base64.StdEncoding.EncodeToString(bodyHash[:]), tags["bh"], "body hash")
+
+	var canonical strings.Builder
+	for _, name := range strings.Split(tags["h"], ":") {
+		canonical.WriteString(relaxedHeader(name, headers[name]) + "\r\n")
+	}
+	unsigned := rawSignature[:strings.LastIndex(rawSignature, "; b=")+len("; b=")]
+	canonical.WriteString(relaxedHeader("DKIM-Signature", unsigned))
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(canonical.String()))
+	require.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+	require.Equal(t, "from:to:subject:date:message-id:mime-version:content-type", tags["h"])
+}
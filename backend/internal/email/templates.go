@@ -0,0 +1,202 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+)
+
+// Template names, matching the keys in i18n/emails.*.json
+const (
+	TemplateMagicLink      = "magic_link"
+	TemplateClubInvitation = "club_invitation"
+	TemplateInvitation     = "invitation"
+	TemplateJoinRequest    = "join_request"
+
+	// Member notifications; the names match the notification types
+	TemplateMatchReported   = "match_reported"
+	TemplateLadderOvertaken = "ladder_overtaken"
+	TemplateSeriesEnding    = "series_ending"
+	TemplateSeriesSummary   = "series_summary"
+	TemplateDigest          = "digest"
+)
+
+//go:embed templates/layout.html templates/layout.txt
+var layoutFS embed.FS
+
+// DigestItem is one notification inside a daily digest. It is rendered with
+// the summary line of its template.
+type DigestItem struct {
+	Template string
+	URL      string
+	Data     map[string]string
+}
+
+// RenderRequest describes one email to render
+type RenderRequest struct {
+	To       string
+	Locale   string
+	Template string
+	URL      string            // Call to action link
+	Data     map[string]string // Fills the placeholders in the bundle strings
+	Items    []DigestItem      // Listed below the paragraphs (digests only)
+
+	// Unsubscribe links for member notifications: a page for the footer and a
+	// one-click endpoint for the List-Unsubscribe header
+	UnsubscribeURL     string
+	ListUnsubscribeURL string
+}
+
+// layoutData is what the shared layouts render
+type layoutData struct {
+	Lang           string
+	Subject        string
+	Heading        string
+	Greeting       string
+	Paragraphs     []string
+	Items          []layoutItem
+	Button         string
+	URL            string
+	CopyLink       string
+	Notes          []string
+	Signoff        string
+	Team           string
+	Unsubscribe    string
+	UnsubscribeURL string
+}
+
+type layoutItem struct {
+	Text string
+	URL  string
+}
+
+// Renderer turns a template name, locale and data into a Message
+type Renderer struct {
+	defaultLocale string
+	html          *htmltemplate.Template
+	text          *texttemplate.Template
+}
+
+// NewRenderer parses the shared layouts. Locales without a bundle fall back
+// to defaultLocale.
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	if !i18n.IsSupported(defaultLocale) {
+		return nil, fmt.Errorf("unsupported default locale: %s", defaultLocale)
+	}
+
+	html, err := htmltemplate.ParseFS(layoutFS, "templates/layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML layout: %w", err)
+	}
+	text, err := texttemplate.ParseFS(layoutFS, "templates/layout.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text layout: %w", err)
+	}
+
+	return &Renderer{defaultLocale: defaultLocale, html: html, text: text}, nil
+}
+
+// Render builds the message described by req
+func (r *Renderer) Render(req RenderRequest) (*Message, error) {
+	locale := req.Locale
+	if !i18n.IsSupported(locale) {
+		locale = r.defaultLocale
+	}
+
+	bundle, err := i18n.LoadEmails(locale)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, ok := bundle.Templates[req.Template]
+	if !ok {
+		return nil, fmt.Errorf("no email template %s for locale %s", req.Template, locale)
+	}
+
+	layout := layoutData{
+		Lang:           locale,
+		Greeting:       bundle.Common.Greeting,
+		URL:            req.URL,
+		CopyLink:       bundle.Common.CopyLink,
+		Signoff:        bundle.Common.Signoff,
+		Team:           bundle.Common.Team,
+		Unsubscribe:    bundle.Common.Unsubscribe,
+		UnsubscribeURL: req.UnsubscribeURL,
+	}
+	if layout.Subject, err = fill(tmpl.Subject, req.Data); err != nil {
+		return nil, err
+	}
+	if layout.Heading, err = fill(tmpl.Heading, req.Data); err != nil {
+		return nil, err
+	}
+	if layout.Button, err = fill(tmpl.Button, req.Data); err != nil {
+		return nil, err
+	}
+	if layout.Paragraphs, err = fillAll(tmpl.Paragraphs, req.Data); err != nil {
+		return nil, err
+	}
+	if layout.Notes, err = fillAll(tmpl.Notes, req.Data); err != nil {
+		return nil, err
+	}
+	for _, item := range req.Items {
+		itemTmpl, ok := bundle.Templates[item.Template]
+		if !ok || itemTmpl.Summary == "" {
+			return nil, fmt.Errorf("no email summary %s for locale %s", item.Template, locale)
+		}
+		text, err := fill(itemTmpl.Summary, item.Data)
+		if err != nil {
+			return nil, err
+		}
+		layout.Items = append(layout.Items, layoutItem{Text: text, URL: item.URL})
+	}
+
+	var html, text bytes.Buffer
+	if err := r.html.Execute(&html, layout); err != nil {
+		return nil, fmt.Errorf("failed to render HTML for %s: %w", req.Template, err)
+	}
+	if err := r.text.Execute(&text, layout); err != nil {
+		return nil, fmt.Errorf("failed to render text for %s: %w", req.Template, err)
+	}
+
+	return &Message{
+		To:              req.To,
+		Subject:         layout.Subject,
+		Text:            strings.TrimSpace(text.String()),
+		HTML:            html.String(),
+		Template:        req.Template,
+		ListUnsubscribe: req.ListUnsubscribeURL,
+	}, nil
+}
+
+// fill executes a bundle string as a text template. The result is escaped by
+// the HTML layout, so placeholders are not escaped here.
+func fill(s string, data map[string]string) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	t, err := texttemplate.New("").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid email string %q: %w", s, err)
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to fill email string %q: %w", s, err)
+	}
+	return out.String(), nil
+}
+
+func fillAll(strs []string, data map[string]string) ([]string, error) {
+	out := make([]string, 0, len(strs))
+	for _, s := range strs {
+		filled, err := fill(s, data)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, filled)
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package email
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+)
+
+func TestEmailAdapterRendersLocalizedTemplates(t *testing.T) {
+	ctx := context.Background()
+	mock, err := NewMockEmailService(EmailConfig{})
+	require.NoError(t, err)
+	adapter, err := NewEmailAdapterWithTransport(EmailConfig{BaseURL: "https://klubbspel.se", DefaultLocale: "sv"}, mock)
+	require.NoError(t, err)
+
+	require.NoError(t, adapter.SendClubInvitationMagicLink(ctx, "anna@example.com", "en", "tok", "", "Spinn <BTK>", "Bo", "bo@example.com"))
+	sent := mock.GetLastEmail()
+	require.Equal(t, TemplateClubInvitation, sent.Type)
+	require.Equal(t, "You have been added as a member of Spinn <BTK>", sent.Subject)
+	require.Contains(t, sent.Body, "https://klubbspel.se/auth/login?apikey=tok&return_url=%2F")
+	require.Contains(t, sent.Body, `Bo (bo@example.com) has added you as a member of the club "Spinn <BTK>"`)
+	require.Contains(t, sent.HTML, `lang="en"`)
+	require.Contains(t, sent.HTML, "Spinn &lt;BTK&gt;", "placeholders are HTML-escaped")
+	require.NotContains(t, sent.HTML, "<BTK>")
+
+	// Unknown and empty locales fall back to the default
+	for _, locale := range []string{"", "xx"} {
+		require.NoError(t, adapter.SendMagicLink(ctx, "anna@example.com", locale, "tok", "/login"))
+		sent = mock.GetLastEmail()
+		require.Equal(t, "Logga in på Klubbspel", sent.Subject)
+		require.Contains(t, sent.Body, "return_url=%2Fleaderboard")
+		require.Contains(t, sent.HTML, `lang="sv"`)
+	}
+
+	require.NoError(t, adapter.SendEmail(ctx, "anna@example.com", "Hello", "Plain body"))
+	sent = mock.GetLastEmail()
+	require.Equal(t, "generic", sent.Type)
+	require.Empty(t, sent.HTML)
+}
+
+func TestEveryTemplateRendersInEveryLocale(t *testing.T) {
+	renderer, err := NewRenderer("sv")
+	require.NoError(t, err)
+
+	data := map[string]string{
+		"ClubName": "Klubb", "InviterName": "Bo", "InviterEmail": "bo@example.com",
+		"RequesterName": "Cia", "RequesterEmail": "cia@example.com",
+		"ReporterName": "Bo", "OpponentName": "Bo", "SeriesTitle": "Vår", "Score": "3–1",
+		"OvertakerName": "Bo", "Position": "4", "PreviousPosition": "3", "EndDate": "2025-05-31", "ChampionName": "Bo",
+	}
+	for _, locale := range i18n.SupportedLocales {
+		for _, name := range []string{TemplateMagicLink, TemplateClubInvitation, TemplateInvitation, TemplateJoinRequest, TemplateMatchReported, TemplateLadderOvertaken, TemplateSeriesEnding, TemplateSeriesSummary} {
+			msg, err := renderer.Render(RenderRequest{To: "to@example.com", Locale: locale, Template: name, URL: "https://klubbspel.se/x", Data: data})
+			require.NoError(t, err, "%s/%s", locale, name)
+			require.NotEmpty(t, msg.Subject, "%s/%s", locale, name)
+			require.NotContains(t, msg.Text, "{{", "%s/%s", locale, name)
+		}
+	}
+}
+
+func TestNotificationEmailsCarryUnsubscribeLinks(t *testing.T) {
+	ctx := context.Background()
+	mock, err := NewMockEmailService(EmailConfig{})
+	require.NoError(t, err)
+	adapter, err := NewEmailAdapterWithTransport(EmailConfig{BaseURL: "https://klubbspel.se", APIBaseURL: "https://api.klubbspel.se"}, mock)
+	require.NoError(t, err)
+
+	items := []DigestItem{
+		{Template: TemplateMatchReported, URL: "https://klubbspel.se/series/1", Data: map[string]string{"ReporterName": "Bo", "OpponentName": "Bo", "SeriesTitle": "Vår", "Score": "3–1"}},
+		{Template: TemplateSeriesEnding, URL: "https://klubbspel.se/series/1/leaderboard", Data: map[string]string{"SeriesTitle": "Vår", "EndDate": "2025-05-31", "Position": "2"}},
+	}
+	require.NoError(t, adapter.SendDigest(ctx, "anna@example.com", "en", items, "a.b"))
+
+	sent := mock.GetLastEmail()
+	require.Equal(t, TemplateDigest, sent.Type)
+	require.Contains(t, sent.Body, "Bo reported your match against Bo in Vår: 3–1")
+	require.Contains(t, sent.Body, "Vår ends on 2025-05-31, you are in position 2")
+	require.Contains(t, sent.Body, "https://klubbspel.se/unsubscribe?token=a.b")
+	require.Contains(t, sent.HTML, "https://klubbspel.se/series/1/leaderboard")
+	require.Equal(t, "<https://api.klubbspel.se/v1/notifications/unsubscribe?token=a.b>", sent.Headers["List-Unsubscribe"])
+}
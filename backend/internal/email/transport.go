@@ -0,0 +1,23 @@
+package email
+
+import (
+	"context"
+)
+
+// Message is a rendered email ready to be delivered
+type Message struct {
+	To       string
+	Subject  string
+	Text     string
+	HTML     string // Empty for plain-text emails
+	Template string // Name of the template the message was rendered from, "generic" otherwise
+
+	// One-click unsubscribe URL (RFC 8058), sent as List-Unsubscribe headers
+	ListUnsubscribe string
+}
+
+// Transport delivers rendered messages. Providers only move bytes; all copy
+// lives in the templates.
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
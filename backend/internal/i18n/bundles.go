@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sync"
+)
+
+// Bundles are embedded by file name: <bundle>.<locale>.json
+//
+//go:embed *.json
+var bundleFS embed.FS
+
+var bundleFilePattern = regexp.MustCompile(`^([a-z_]+)\.([a-z]{2,3})\.json$`)
+
+var (
+	bundleFilesOnce sync.Once
+	bundleFiles     map[string]map[string]string // bundle -> locale -> file name
+)
+
+// bundleFile returns the embedded file of bundle in locale, if any
+func bundleFile(bundle, locale string) (string, bool) {
+	bundleFilesOnce.Do(func() {
+		bundleFiles = make(map[string]map[string]string)
+		entries, _ := fs.ReadDir(bundleFS, ".")
+		for _, entry := range entries {
+			match := bundleFilePattern.FindStringSubmatch(entry.Name())
+			if match == nil {
+				continue
+			}
+			if bundleFiles[match[1]] == nil {
+				bundleFiles[match[1]] = make(map[string]string)
+			}
+			bundleFiles[match[1]][match[2]] = entry.Name()
+		}
+	})
+	name, ok := bundleFiles[bundle][locale]
+	return name, ok
+}
+
+// BundleLocales returns the locales that have their own file for bundle
+func BundleLocales(bundle string) []string {
+	var locales []string
+	for _, locale := range SupportedLocales {
+		if _, ok := bundleFile(bundle, locale); ok {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// loadBundle decodes bundle for locale into v. The locale's fallback chain is
+// decoded first, so keys missing from a file keep the value of the nearest
+// fallback: map entries and object fields are overlaid, arrays replaced.
+func loadBundle(bundle, locale string, v any) error {
+	chain := FallbackChain(locale)
+	if chain == nil {
+		return fmt.Errorf("unsupported locale %s", locale)
+	}
+
+	found := false
+	for i := len(chain) - 1; i >= 0; i-- {
+		name, ok := bundleFile(bundle, chain[i])
+		if !ok {
+			continue
+		}
+		data, err := bundleFS.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to unmarshal %s for locale %s: %w", bundle, chain[i], err)
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("no %s bundle for locale %s", bundle, locale)
+	}
+	return nil
+}
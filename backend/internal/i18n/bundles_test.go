@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// errorCodePatterns find the stable error codes returned by the Go code
+var errorCodePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:status\.Errorf?|errorWithArgs)\(\s*codes\.\w+,\s*"([A-Z][A-Z0-9_]+)"`),
+	regexp.MustCompile(`codes\.\w+:\s*"([A-Z][A-Z0-9_]+)"`), // Fallback code maps
+}
+
+var emailTemplatePattern = regexp.MustCompile(`Template\w+\s*=\s*"(\w+)"`)
+
+// scanSource returns the first submatch of patterns in the non-test Go files
+// below dir
+func scanSource(t *testing.T, dir string, patterns ...*regexp.Regexp) map[string]string {
+	t.Helper()
+	found := map[string]string{} // match -> file, for error messages
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns {
+			for _, match := range pattern.FindAllStringSubmatch(string(src), -1) {
+				found[match[1]] = path
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return found
+}
+
+func TestEveryLocaleHasEveryErrorCode(t *testing.T) {
+	used := scanSource(t, "..", errorCodePatterns...)
+	require.Contains(t, used, "VALIDATION_SCORE_TIE", "the source scan finds error codes")
+
+	for _, locale := range SupportedLocales {
+		name, ok := bundleFile("messages", locale)
+		require.True(t, ok, "messages.%s.json is missing", locale)
+		var messages map[string]string
+		data, err := bundleFS.ReadFile(name)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &messages))
+
+		for code, file := range used {
+			require.NotEmpty(t, messages[code], "%s has no text for %s (used in %s)", name, code, file)
+		}
+	}
+}
+
+func TestEveryLocaleHasEveryEmailTemplate(t *testing.T) {
+	used := scanSource(t, "../email", emailTemplatePattern)
+	require.Contains(t, used, "magic_link")
+
+	for _, locale := range SupportedLocales {
+		name, ok := bundleFile("emails", locale)
+		require.True(t, ok, "emails.%s.json is missing", locale)
+		var bundle EmailBundle
+		data, err := bundleFS.ReadFile(name)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &bundle))
+
+		require.NotEmpty(t, bundle.Common.Greeting, name)
+		require.NotEmpty(t, bundle.Common.Unsubscribe, name)
+		for template := range used {
+			require.NotEmpty(t, bundle.Templates[template].Subject, "%s has no %s template", name, template)
+		}
+	}
+}
+
+func TestEveryLocaleHasRules(t *testing.T) {
+	for _, locale := range SupportedLocales {
+		name, ok := bundleFile("rules", locale)
+		require.True(t, ok, "rules.%s.json is missing", locale)
+		var rules RulesData
+		data, err := bundleFS.ReadFile(name)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &rules))
+
+		for _, content := range []RulesContent{rules.FreePlay, rules.LadderClassic, rules.LadderAggressive} {
+			require.NotEmpty(t, content.Title, name)
+			require.NotEmpty(t, content.Rules, name)
+		}
+	}
+}
+
+func TestFallbackChainsEndInEnglish(t *testing.T) {
+	for _, locale := range Locales {
+		chain := FallbackChain(locale.Tag)
+		require.Equal(t, "en", chain[len(chain)-1], locale.Tag)
+		for _, fallback := range locale.Fallbacks {
+			require.True(t, IsSupported(fallback), "%s falls back to unsupported %s", locale.Tag, fallback)
+		}
+	}
+
+	require.Equal(t, []string{"nb", "sv", "en"}, FallbackChain("nb"))
+	require.Nil(t, FallbackChain("xx"))
+	require.Equal(t, "nb", FromAcceptLanguage("nn-NO,en;q=0.5"))
+	require.Equal(t, "fi", FromAcceptLanguage("fi-FI"))
+}
+
+func TestMessageInterpolatesArgs(t *testing.T) {
+	text, ok := Message("de", "SERIES_WINDOW", map[string]string{"match_date": "2026-10-05", "start_date": "2026-09-01", "end_date": "2026-09-30"})
+	require.True(t, ok)
+	require.Equal(t, "Das Spieldatum 2026-10-05 muss im Zeitraum der Serie 2026-09-01 – 2026-09-30 liegen.", text)
+
+	_, ok = Message("nb", "NO_SUCH_CODE", nil)
+	require.False(t, ok)
+}
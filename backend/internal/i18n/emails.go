@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"sync"
+)
+
+// EmailCommon holds the strings shared by every email of a locale
+type EmailCommon struct {
+	Greeting string `json:"greeting"`
+	CopyLink string `json:"copy_link"`
+	Signoff  string `json:"signoff"`
+	Team     string `json:"team"`
+
+	Unsubscribe string `json:"unsubscribe"`
+}
+
+// EmailTemplate holds the copy of one email. Strings may contain
+// text/template placeholders such as {{.ClubName}}. Summary is the one-line
+// version used when the email is part of a daily digest.
+type EmailTemplate struct {
+	Subject    string   `json:"subject"`
+	Summary    string   `json:"summary,omitempty"`
+	Heading    string   `json:"heading"`
+	Paragraphs []string `json:"paragraphs"`
+	Button     string   `json:"button"`
+	Notes      []string `json:"notes"`
+}
+
+// EmailBundle is the email copy for one locale
+type EmailBundle struct {
+	Common    EmailCommon              `json:"common"`
+	Templates map[string]EmailTemplate `json:"templates"`
+}
+
+var (
+	emailsMu    sync.Mutex
+	emailsCache = make(map[string]*EmailBundle)
+)
+
+// LoadEmails loads the email bundle for the specified locale. Templates and
+// common strings the locale lacks come from its fallback chain.
+func LoadEmails(locale string) (*EmailBundle, error) {
+	emailsMu.Lock()
+	defer emailsMu.Unlock()
+
+	if cached, ok := emailsCache[locale]; ok {
+		return cached, nil
+	}
+
+	var bundle EmailBundle
+	if err := loadBundle("emails", locale, &bundle); err != nil {
+		return nil, err
+	}
+
+	emailsCache[locale] = &bundle
+	return &bundle, nil
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"strings"
+)
+
+// Locale describes a language with translations. Keys a bundle lacks are
+// looked up in the Fallbacks, in order.
+type Locale struct {
+	Tag       string   // Language subtag, e.g. "nb"
+	Name      string   // Native name, e.g. "Norsk bokmål"
+	Fallbacks []string // Locales to fall back to, most similar first
+}
+
+// Locales is the locale registry. Every locale's chain ends in English, which
+// is the most complete bundle.
+var Locales = []Locale{
+	{Tag: "sv", Name: "Svenska", Fallbacks: []string{"en"}},
+	{Tag: "en", Name: "English"},
+	{Tag: "nb", Name: "Norsk bokmål", Fallbacks: []string{"sv", "en"}},
+	{Tag: "fi", Name: "Suomi", Fallbacks: []string{"sv", "en"}},
+	{Tag: "de", Name: "Deutsch", Fallbacks: []string{"en"}},
+}
+
+// localeAliases maps language subtags to the registered locale serving them
+var localeAliases = map[string]string{
+	"no": "nb", // Generic Norwegian
+	"nn": "nb", // Nynorsk readers are served bokmål
+}
+
+// SupportedLocales lists the locales that have translations
+var SupportedLocales = func() []string {
+	tags := make([]string, 0, len(Locales))
+	for _, l := range Locales {
+		tags = append(tags, l.Tag)
+	}
+	return tags
+}()
+
+// IsSupported reports whether locale has translations
+func IsSupported(locale string) bool {
+	_, ok := lookupLocale(locale)
+	return ok
+}
+
+// FallbackChain returns locale followed by the locales to try when a key is
+// missing, or nil when locale is not supported
+func FallbackChain(locale string) []string {
+	l, ok := lookupLocale(locale)
+	if !ok {
+		return nil
+	}
+	return append([]string{l.Tag}, l.Fallbacks...)
+}
+
+// FromAcceptLanguage returns the first supported locale in an Accept-Language
+// header, or "" when none match. Quality values are ignored since browsers
+// already list languages in order of preference.
+func FromAcceptLanguage(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		if alias, ok := localeAliases[lang]; ok {
+			lang = alias
+		}
+		if IsSupported(lang) {
+			return lang
+		}
+	}
+	return ""
+}
+
+func lookupLocale(tag string) (Locale, bool) {
+	for _, l := range Locales {
+		if l.Tag == tag {
+			return l, true
+		}
+	}
+	return Locale{}, false
+}
@@ -0,0 +1,57 @@
+package i18n
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	messagesMu    sync.Mutex
+	messagesCache = make(map[string]map[string]string)
+)
+
+// LoadMessages loads the error messages for the specified locale, keyed by
+// stable error code. Codes the locale lacks come from its fallback chain.
+func LoadMessages(locale string) (map[string]string, error) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+
+	if cached, ok := messagesCache[locale]; ok {
+		return cached, nil
+	}
+
+	messages := make(map[string]string)
+	if err := loadBundle("messages", locale, &messages); err != nil {
+		return nil, err
+	}
+
+	messagesCache[locale] = messages
+	return messages, nil
+}
+
+// Message returns the text for code in locale with {{name}} placeholders
+// replaced by args. ok is false when the locale has no text for code.
+func Message(locale, code string, args map[string]string) (string, bool) {
+	messages, err := LoadMessages(locale)
+	if err != nil {
+		return "", false
+	}
+	text, ok := messages[code]
+	if !ok {
+		return "", false
+	}
+	return Interpolate(text, args), true
+}
+
+// Interpolate replaces {{name}} placeholders in text with args. Unknown
+// placeholders are left as they are.
+func Interpolate(text string, args map[string]string) string {
+	if len(args) == 0 || !strings.Contains(text, "{{") {
+		return text
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
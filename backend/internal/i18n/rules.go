@@ -1,17 +1,9 @@
 package i18n
 
 import (
-	_ "embed"
-	"encoding/json"
-	"fmt"
+	"sync"
 )
 
-//go:embed rules_en.json
-var rulesEN []byte
-
-//go:embed rules_sv.json
-var rulesSV []byte
-
 type RuleExample struct {
 	Scenario string `json:"scenario"`
 	Outcome  string `json:"outcome"`
@@ -30,29 +22,29 @@ type RulesData struct {
 	LadderAggressive RulesContent `json:"ladder_aggressive"`
 }
 
-var rulesCache = make(map[string]*RulesData)
+var (
+	rulesMu    sync.Mutex
+	rulesCache = make(map[string]*RulesData)
+)
 
-// LoadRules loads rules for the specified locale
+// LoadRules loads rules for the specified locale. Unsupported locales get the
+// Swedish rules, and rules the locale lacks come from its fallback chain.
 func LoadRules(locale string) (*RulesData, error) {
+	if !IsSupported(locale) {
+		locale = "sv"
+	}
+
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
 	// Check cache first
 	if cached, ok := rulesCache[locale]; ok {
 		return cached, nil
 	}
 
-	var data []byte
-	switch locale {
-	case "sv":
-		data = rulesSV
-	case "en":
-		data = rulesEN
-	default:
-		// Default to Swedish
-		data = rulesSV
-	}
-
 	var rules RulesData
-	if err := json.Unmarshal(data, &rules); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal rules for locale %s: %w", locale, err)
+	if err := loadBundle("rules", locale, &rules); err != nil {
+		return nil, err
 	}
 
 	// Cache the loaded rules
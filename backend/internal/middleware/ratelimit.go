@@ -269,6 +269,31 @@ func GetDefaultConfig() RateLimitConfig {
 				BurstSize:               5,   // Allow up to 5 requests in burst
 				AuthenticatedMultiplier: 1.0, // Same for authenticated users
 			},
+			"/klubbspel.v1.AuthService/RefreshToken": {
+				RequestsPerSecond:       1.0, // 1 request per second
+				BurstSize:               5,   // Allow up to 5 requests in burst
+				AuthenticatedMultiplier: 1.0, // Same for authenticated users
+			},
+			"/klubbspel.v1.AuthService/BeginPasskeyLogin": {
+				RequestsPerSecond:       1.0, // 1 request per second
+				BurstSize:               5,   // Allow up to 5 requests in burst
+				AuthenticatedMultiplier: 1.0, // Same for authenticated users
+			},
+			"/klubbspel.v1.AuthService/FinishPasskeyLogin": {
+				RequestsPerSecond:       1.0, // 1 request per second
+				BurstSize:               5,   // Allow up to 5 requests in burst
+				AuthenticatedMultiplier: 1.0, // Same for authenticated users
+			},
+			"/klubbspel.v1.AuthService/StartOIDCLogin": {
+				RequestsPerSecond:       1.0, // 1 request per second
+				BurstSize:               5,   // Allow up to 5 requests in burst
+				AuthenticatedMultiplier: 1.0, // Same for authenticated users
+			},
+			"/klubbspel.v1.AuthService/CompleteOIDCLogin": {
+				RequestsPerSecond:       1.0, // 1 request per second
+				BurstSize:               5,   // Allow up to 5 requests in burst
+				AuthenticatedMultiplier: 1.0, // Same for authenticated users
+			},
 
 			// Club management endpoints
 			"/klubbspel.v1.ClubService/CreateClub": {
@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// stubIssuer is a minimal OIDC provider serving discovery, JWKS and a token
+// endpoint that enforces PKCE
+type stubIssuer struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string                 // PKCE challenge sent to /authorize
+	claims    map[string]interface{} // Claims of the next issued ID token
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stub := &stubIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 stub.server.URL,
+			"authorization_endpoint": stub.server.URL + "/authorize",
+			"token_endpoint":         stub.server.URL + "/token",
+			"jwks_uri":               stub.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "stub-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil ||
+			r.PostForm.Get("code") != "auth-code" ||
+			r.PostForm.Get("client_secret") != "secret" ||
+			CodeChallenge(r.PostForm.Get("code_verifier")) != stub.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]string{"id_token": stub.sign(t, stub.claims)})
+	})
+	stub.server = httptest.NewServer(mux)
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *stubIssuer) sign(t *testing.T, claims map[string]interface{}) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"stub-key","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *stubIssuer) validClaims(nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":            s.server.URL,
+		"aud":            "klubbspel",
+		"sub":            "user-123",
+		"email":          "anna@example.com",
+		"email_verified": true,
+		"given_name":     "Anna",
+		"family_name":    "Andersson",
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	stub := newStubIssuer(t)
+	provider := NewProvider(Config{
+		ID:           "stub",
+		IssuerURL:    stub.server.URL,
+		ClientID:     "klubbspel",
+		ClientSecret: "secret",
+		RedirectURL:  "https://klubbspel.se/auth/oidc/callback",
+	})
+	ctx := context.Background()
+
+	verifier, _ := RandomString()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("state") != "state-1" || query.Get("nonce") != "nonce-1" || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization URL %s", authURL)
+	}
+	stub.challenge = query.Get("code_challenge")
+	stub.claims = stub.validClaims("nonce-1")
+
+	claims, err := provider.Exchange(ctx, "auth-code", verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Subject != "user-123" || claims.Email != "anna@example.com" || !claims.EmailVerified || claims.GivenName != "Anna" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	if _, err := provider.Exchange(ctx, "auth-code", "wrong-verifier", "nonce-1"); !errors.Is(err, ErrTokenExchange) {
+		t.Errorf("wrong PKCE verifier: got %v, want %v", err, ErrTokenExchange)
+	}
+	if _, err := provider.Exchange(ctx, "auth-code", verifier, "other-nonce"); !errors.Is(err, ErrNonceMismatch) {
+		t.Errorf("wrong nonce: got %v, want %v", err, ErrNonceMismatch)
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	stub := newStubIssuer(t)
+	provider := NewProvider(Config{IssuerURL: stub.server.URL, ClientID: "klubbspel"})
+	ctx := context.Background()
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := stub.validClaims("n")
+		claims[key] = value
+		return claims
+	}
+
+	if _, err := provider.VerifyIDToken(ctx, stub.sign(t, stub.validClaims("n"))); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	valid := stub.sign(t, stub.validClaims("n"))
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong audience", stub.sign(t, with("aud", "someone-else"))},
+		{"wrong issuer", stub.sign(t, with("iss", "https://evil.example"))},
+		{"expired", stub.sign(t, with("exp", time.Now().Add(-time.Hour).Unix()))},
+		{"missing subject", stub.sign(t, with("sub", ""))},
+		{"tampered payload", valid[:len(valid)-10] + "AAAAAAAAAA"},
+		{"unsigned", "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"x"}`)) + "."},
+	}
+
+	for _, test := range tests {
+		if _, err := provider.VerifyIDToken(ctx, test.token); !errors.Is(err, ErrInvalidIDToken) {
+			t.Errorf("%s: got %v, want %v", test.name, err, ErrInvalidIDToken)
+		}
+	}
+
+	// The audience claim may also be an array
+	if _, err := provider.VerifyIDToken(ctx, stub.sign(t, with("aud", []string{"other", "klubbspel"}))); err != nil {
+		t.Errorf("array audience rejected: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClubInvitation status values
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired" // Set when an expired invitation is superseded
+)
+
+// ClubInvitation is an invitation to join a club that the invitee has not answered yet.
+// Expiry is evaluated on read; an expired invitation keeps its pending status.
+type ClubInvitation struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	ClubID      string             `bson:"club_id"`
+	Email       string             `bson:"email"`
+	Role        string             `bson:"role"` // Role granted on acceptance
+	InvitedBy   string             `bson:"invited_by"`
+	Status      string             `bson:"status"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	ExpiresAt   time.Time          `bson:"expires_at"`
+	LastSentAt  time.Time          `bson:"last_sent_at"`
+	SendCount   int32              `bson:"send_count"`
+	RespondedAt *time.Time         `bson:"responded_at,omitempty"`
+}
+
+// IsExpired reports whether a pending invitation can no longer be answered
+func (i *ClubInvitation) IsExpired(now time.Time) bool {
+	return i.Status == InvitationStatusPending && !now.Before(i.ExpiresAt)
+}
+
+// ClubInvitationRepo manages club invitations
+type ClubInvitationRepo struct {
+	c *mongo.Collection
+}
+
+// NewClubInvitationRepo creates the repository and ensures required indexes exist.
+func NewClubInvitationRepo(db *mongo.Database) *ClubInvitationRepo {
+	repo := &ClubInvitationRepo{c: db.Collection("club_invitations")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create club invitation indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *ClubInvitationRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// At most one open invitation per address and club
+			Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": InvitationStatusPending}),
+		},
+		{
+			Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}},
+		},
+	})
+	return err
+}
+
+// Create stores a new pending invitation
+func (r *ClubInvitationRepo) Create(ctx context.Context, invitation *ClubInvitation) (*ClubInvitation, error) {
+	invitation.ID = primitive.NewObjectID()
+	if _, err := r.c.InsertOne(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// FindByID retrieves an invitation by ID
+func (r *ClubInvitationRepo) FindByID(ctx context.Context, id string) (*ClubInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitation ClubInvitation
+	if err := r.c.FindOne(ctx, bson.M{"_id": objID}).Decode(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPending returns the open invitation for an address to a club
+func (r *ClubInvitationRepo) FindPending(ctx context.Context, clubID, email string) (*ClubInvitation, error) {
+	var invitation ClubInvitation
+	filter := bson.M{"club_id": clubID, "email": email, "status": InvitationStatusPending}
+	if err := r.c.FindOne(ctx, filter).Decode(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListPendingByClub returns unexpired pending invitations of a club, newest first
+func (r *ClubInvitationRepo) ListPendingByClub(ctx context.Context, clubID string, now time.Time) ([]*ClubInvitation, error) {
+	return r.listPending(ctx, bson.M{"club_id": clubID}, now)
+}
+
+// ListPendingByEmail returns unexpired pending invitations addressed to an email, newest first
+func (r *ClubInvitationRepo) ListPendingByEmail(ctx context.Context, email string, now time.Time) ([]*ClubInvitation, error) {
+	return r.listPending(ctx, bson.M{"email": email}, now)
+}
+
+func (r *ClubInvitationRepo) listPending(ctx context.Context, filter bson.M, now time.Time) ([]*ClubInvitation, error) {
+	filter["status"] = InvitationStatusPending
+	filter["expires_at"] = bson.M{"$gt": now}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var invitations []*ClubInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Respond moves a pending invitation to a final status. Fails with
+// mongo.ErrNoDocuments when the invitation is no longer pending.
+func (r *ClubInvitationRepo) Respond(ctx context.Context, id, status string, now time.Time) (*ClubInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{"status": status, "responded_at": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invitation ClubInvitation
+	filter := bson.M{"_id": objID, "status": InvitationStatusPending}
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Renew records a resend and extends the expiry of a pending invitation
+func (r *ClubInvitationRepo) Renew(ctx context.Context, id string, sentAt, expiresAt time.Time) (*ClubInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{"last_sent_at": sentAt, "expires_at": expiresAt},
+		"$inc": bson.M{"send_count": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invitation ClubInvitation
+	filter := bson.M{"_id": objID, "status": InvitationStatusPending}
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Reopen returns an accepted invitation to pending, undoing an acceptance
+// whose membership could not be added
+func (r *ClubInvitationRepo) Reopen(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.c.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": InvitationStatusAccepted},
+		bson.M{
+			"$set":   bson.M{"status": InvitationStatusPending},
+			"$unset": bson.M{"responded_at": ""},
+		},
+	)
+	return err
+}
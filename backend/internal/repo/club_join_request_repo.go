@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClubJoinRequest status values
+const (
+	JoinRequestStatusPending  = "pending"
+	JoinRequestStatusApproved = "approved"
+	JoinRequestStatusRejected = "rejected"
+)
+
+// ClubJoinRequest is a player's request to join a club with the request-to-join policy
+type ClubJoinRequest struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	ClubID     string             `bson:"club_id"`
+	PlayerID   string             `bson:"player_id"`
+	Email      string             `bson:"email"`
+	Message    string             `bson:"message,omitempty"`
+	Status     string             `bson:"status"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ReviewedBy string             `bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time         `bson:"reviewed_at,omitempty"`
+	ReviewNote string             `bson:"review_note,omitempty"`
+}
+
+// ClubJoinRequestRepo manages club join requests
+type ClubJoinRequestRepo struct {
+	c *mongo.Collection
+}
+
+// NewClubJoinRequestRepo creates the repository and ensures required indexes exist.
+func NewClubJoinRequestRepo(db *mongo.Database) *ClubJoinRequestRepo {
+	repo := &ClubJoinRequestRepo{c: db.Collection("club_join_requests")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create club join request indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *ClubJoinRequestRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// At most one open request per player and club
+			Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": JoinRequestStatusPending}),
+		},
+		{
+			Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Create stores a new join request
+func (r *ClubJoinRequestRepo) Create(ctx context.Context, request *ClubJoinRequest) (*ClubJoinRequest, error) {
+	request.ID = primitive.NewObjectID()
+	if _, err := r.c.InsertOne(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// FindByID retrieves a join request by ID
+func (r *ClubJoinRequestRepo) FindByID(ctx context.Context, id string) (*ClubJoinRequest, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var request ClubJoinRequest
+	if err := r.c.FindOne(ctx, bson.M{"_id": objID}).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// FindPending returns the open join request of an email address for a club
+func (r *ClubJoinRequestRepo) FindPending(ctx context.Context, clubID, email string) (*ClubJoinRequest, error) {
+	var request ClubJoinRequest
+	filter := bson.M{"club_id": clubID, "email": email, "status": JoinRequestStatusPending}
+	if err := r.c.FindOne(ctx, filter).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListByClub returns the join requests of a club (oldest first), optionally filtered by status
+func (r *ClubJoinRequestRepo) ListByClub(ctx context.Context, clubID, status string) ([]*ClubJoinRequest, error) {
+	filter := bson.M{"club_id": clubID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var requests []*ClubJoinRequest
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Review records an admin decision on a pending request. Fails with
+// mongo.ErrNoDocuments when the request is no longer pending.
+func (r *ClubJoinRequestRepo) Review(ctx context.Context, id, status, reviewedBy, note string, now time.Time) (*ClubJoinRequest, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":      status,
+		"reviewed_by": reviewedBy,
+		"reviewed_at": now,
+		"review_note": note,
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var request ClubJoinRequest
+	filter := bson.M{"_id": objID, "status": JoinRequestStatusPending}
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Reopen returns an approved request to pending, undoing an approval whose
+// membership could not be added
+func (r *ClubJoinRequestRepo) Reopen(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.c.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": JoinRequestStatusApproved},
+		bson.M{
+			"$set":   bson.M{"status": JoinRequestStatusPending},
+			"$unset": bson.M{"reviewed_by": "", "reviewed_at": "", "review_note": ""},
+		},
+	)
+	return err
+}
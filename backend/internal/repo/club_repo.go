@@ -16,10 +16,41 @@ type Club struct {
 	Name            string             `bson:"name"`
 	SupportedSports []int32            `bson:"supported_sports"`
 
+	// JoinPolicy controls how players become members (empty = open)
+	JoinPolicy string `bson:"join_policy,omitempty"`
+
+	// AbuseThresholds overrides the default suspicious-activity thresholds (nil = defaults)
+	AbuseThresholds *AbuseThresholds `bson:"abuse_thresholds,omitempty"`
+
 	// Enhanced search functionality
 	SearchKeys *SearchKeys `bson:"search_keys,omitempty"`
 }
 
+// Club join policies
+const (
+	ClubJoinPolicyOpen       = "open"        // Anyone can join directly
+	ClubJoinPolicyRequest    = "request"     // Joining creates a request that admins approve
+	ClubJoinPolicyInviteOnly = "invite_only" // Members join through invitations only
+)
+
+// EffectiveJoinPolicy returns the club's join policy, defaulting to open
+func (c *Club) EffectiveJoinPolicy() string {
+	if c.JoinPolicy == "" {
+		return ClubJoinPolicyOpen
+	}
+	return c.JoinPolicy
+}
+
+// AbuseThresholds configures when reported matches are flagged for admin review.
+// Zero values fall back to the defaults.
+type AbuseThresholds struct {
+	PairingWindowHours  int32   `bson:"pairing_window_hours"`   // Window for counting repeated pairings
+	MaxPairingsInWindow int32   `bson:"max_pairings_in_window"` // Pairings above this in the window are flagged
+	LateReportHours     int32   `bson:"late_report_hours"`      // Reports later than this after played_at are flagged
+	SelfWinMinReports   int32   `bson:"self_win_min_reports"`   // Reports needed before the self-win ratio applies
+	SelfWinRatio        float64 `bson:"self_win_ratio"`         // Share of own wins (0-1) that gets flagged
+}
+
 type ClubRepo struct{ c *mongo.Collection }
 
 func NewClubRepo(db *mongo.Database) *ClubRepo {
@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxEmail status values
+const (
+	OutboxStatusPending = "pending" // Waiting for its first or next attempt
+	OutboxStatusSending = "sending" // Claimed by a worker
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead" // Gave up after the maximum number of attempts
+)
+
+// outboxRetention is how long delivered and dead-lettered emails are kept for
+// the delivery status view
+const outboxRetention = 30 * 24 * time.Hour
+
+// OutboxEmail is a rendered email waiting for, or done with, delivery. The
+// bodies can contain sign-in links, so they are removed once the email is
+// sent or dead-lettered.
+type OutboxEmail struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	To              string             `bson:"to"`
+	Subject         string             `bson:"subject"`
+	Text            string             `bson:"text,omitempty"`
+	HTML            string             `bson:"html,omitempty"`
+	Template        string             `bson:"template"`
+	ListUnsubscribe string             `bson:"list_unsubscribe,omitempty"` // One-click unsubscribe URL
+	Status          string             `bson:"status"`
+	Attempts        int                `bson:"attempts"`
+	NextAttemptAt   time.Time          `bson:"next_attempt_at"`
+	LockedUntil     *time.Time         `bson:"locked_until,omitempty"`
+	LastError       string             `bson:"last_error,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at"`
+	SentAt          *time.Time         `bson:"sent_at,omitempty"`
+}
+
+// EmailOutboxRepo queues transactional emails for the delivery worker
+type EmailOutboxRepo struct {
+	c *mongo.Collection
+}
+
+// NewEmailOutboxRepo creates the repository and ensures required indexes exist.
+func NewEmailOutboxRepo(db *mongo.Database) *EmailOutboxRepo {
+	repo := &EmailOutboxRepo{c: db.Collection("email_outbox")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create email outbox indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *EmailOutboxRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Worker polling: due pending emails and expired claims
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		},
+		{
+			// Drop old emails automatically
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+// Enqueue stores a new email that is due immediately
+func (r *EmailOutboxRepo) Enqueue(ctx context.Context, email *OutboxEmail) error {
+	email.ID = primitive.NewObjectID()
+	email.Status = OutboxStatusPending
+	email.Attempts = 0
+	email.UpdatedAt = email.CreatedAt
+	if email.NextAttemptAt.IsZero() {
+		email.NextAttemptAt = email.CreatedAt
+	}
+	_, err := r.c.InsertOne(ctx, email)
+	return err
+}
+
+// ClaimNext marks the oldest due email as sending for lease and counts the
+// attempt. Emails whose claim expired (a worker died mid-send) are picked up
+// again. Returns mongo.ErrNoDocuments when nothing is due.
+func (r *EmailOutboxRepo) ClaimNext(ctx context.Context, now time.Time, lease time.Duration) (*OutboxEmail, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": OutboxStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"status": OutboxStatusSending, "locked_until": bson.M{"$lte": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": OutboxStatusSending, "locked_until": now.Add(lease), "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var email OutboxEmail
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&email); err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// MarkSent records a successful delivery and drops the bodies
+func (r *EmailOutboxRepo) MarkSent(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	_, err := r.c.UpdateByID(ctx, id, bson.M{
+		"$set":   bson.M{"status": OutboxStatusSent, "sent_at": now, "updated_at": now, "last_error": ""},
+		"$unset": bson.M{"locked_until": "", "text": "", "html": ""},
+	})
+	return err
+}
+
+// MarkFailed records a failed attempt. The email is retried at nextAttemptAt,
+// or dead-lettered (and its bodies dropped) when dead is set.
+func (r *EmailOutboxRepo) MarkFailed(ctx context.Context, id primitive.ObjectID, now, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status, unset := OutboxStatusPending, bson.M{"locked_until": ""}
+	if dead {
+		status, unset = OutboxStatusDead, bson.M{"locked_until": "", "text": "", "html": ""}
+	}
+	_, err := r.c.UpdateByID(ctx, id, bson.M{
+		"$set":   bson.M{"status": status, "next_attempt_at": nextAttemptAt, "updated_at": now, "last_error": lastError},
+		"$unset": unset,
+	})
+	return err
+}
+
+// List returns emails newest first, optionally filtered by status
+func (r *EmailOutboxRepo) List(ctx context.Context, status string, pageSize int32, pageToken string) ([]*OutboxEmail, string, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if pageToken != "" {
+		objID, err := primitive.ObjectIDFromHex(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		filter["_id"] = bson.M{"$lt": objID}
+	}
+
+	cursor, err := r.c.Find(ctx, filter, options.Find().
+		SetLimit(int64(pageSize+1)).
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetProjection(bson.M{"text": 0, "html": 0}))
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var emails []*OutboxEmail
+	if err := cursor.All(ctx, &emails); err != nil {
+		return nil, "", err
+	}
+
+	nextPageToken := ""
+	if len(emails) > int(pageSize) {
+		emails = emails[:pageSize]
+		nextPageToken = emails[len(emails)-1].ID.Hex()
+	}
+	return emails, nextPageToken, nil
+}
+
+// CountByStatus returns the number of emails in each status
+func (r *EmailOutboxRepo) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	cursor, err := r.c.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var rows []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -86,7 +86,7 @@ func (r *LeaderboardRepo) UpsertEntry(ctx context.Context, entry *LeaderboardEnt
 // FindBySeriesOrdered returns leaderboard entries sorted by rank
 func (r *LeaderboardRepo) FindBySeriesOrdered(ctx context.Context, seriesID string) ([]*LeaderboardEntry, error) {
 	filter := bson.M{"series_id": seriesID}
-	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}})
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "player_id", Value: 1}})
 
 	cursor, err := r.c.Find(ctx, filter, opts)
 	if err != nil {
@@ -107,6 +107,28 @@ func (r *LeaderboardRepo) FindBySeriesOrdered(ctx context.Context, seriesID stri
 	return entries, nil
 }
 
+// FindBySeriesAndPlayers returns the leaderboard entries of the given players, keyed by player ID
+func (r *LeaderboardRepo) FindBySeriesAndPlayers(ctx context.Context, seriesID string, playerIDs []string) (map[string]*LeaderboardEntry, error) {
+	cursor, err := r.c.Find(ctx, bson.M{"series_id": seriesID, "player_id": bson.M{"$in": playerIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var entries []*LeaderboardEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*LeaderboardEntry, len(entries))
+	for _, entry := range entries {
+		result[entry.PlayerID] = entry
+	}
+	return result, nil
+}
+
 // DeleteAllForSeries removes all leaderboard entries for a series
 func (r *LeaderboardRepo) DeleteAllForSeries(ctx context.Context, seriesID string) error {
 	_, err := r.c.DeleteMany(ctx, bson.M{"series_id": seriesID})
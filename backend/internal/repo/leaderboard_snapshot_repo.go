@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// snapshotRetention is how long an unused point-in-time leaderboard is kept.
+// Expired snapshots are simply calculated again on the next request.
+const snapshotRetention = 90 * 24 * time.Hour
+
+// LeaderboardSnapshot is a series' leaderboard calculated from the matches
+// played at or before AsOf, ordered by rank. Generation is the series'
+// snapshot generation the matches were read under.
+type LeaderboardSnapshot struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty"`
+	SeriesID   string              `bson:"series_id"`
+	AsOf       time.Time           `bson:"as_of"`
+	Generation int64               `bson:"generation"`
+	Entries    []*LeaderboardEntry `bson:"entries"`
+	CreatedAt  time.Time           `bson:"created_at"`
+}
+
+// LeaderboardSnapshotRepo caches point-in-time leaderboards
+type LeaderboardSnapshotRepo struct {
+	c *mongo.Collection
+}
+
+func NewLeaderboardSnapshotRepo(db *mongo.Database) *LeaderboardSnapshotRepo {
+	r := &LeaderboardSnapshotRepo{
+		c: db.Collection("leaderboard_snapshots"),
+	}
+	if err := r.createIndexes(context.Background()); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *LeaderboardSnapshotRepo) createIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "series_id", Value: 1},
+				{Key: "as_of", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(snapshotRetention.Seconds())),
+		},
+	}
+
+	_, err := r.c.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// Find returns the snapshot of a series at asOf taken in the given generation,
+// or mongo.ErrNoDocuments
+func (r *LeaderboardSnapshotRepo) Find(ctx context.Context, seriesID string, asOf time.Time, generation int64) (*LeaderboardSnapshot, error) {
+	var snapshot LeaderboardSnapshot
+	filter := bson.M{"series_id": seriesID, "as_of": asOf, "generation": generation}
+	if err := r.c.FindOne(ctx, filter).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Upsert stores the snapshot, replacing any earlier snapshot of the series at
+// the same time. A snapshot from a newer generation is kept, so standings
+// calculated before a concurrent match change never overwrite fresher ones.
+func (r *LeaderboardSnapshotRepo) Upsert(ctx context.Context, snapshot *LeaderboardSnapshot) error {
+	replacement := *snapshot
+	replacement.ID = primitive.NilObjectID
+
+	_, err := r.c.ReplaceOne(ctx,
+		bson.M{"series_id": snapshot.SeriesID, "as_of": snapshot.AsOf, "generation": bson.M{"$lte": snapshot.Generation}},
+		replacement,
+		options.Replace().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// Advance moves the snapshots of a series taken before the given time from
+// the previous generation to generation, as a match change after them leaves
+// them valid
+func (r *LeaderboardSnapshotRepo) Advance(ctx context.Context, seriesID string, before time.Time, generation int64) error {
+	_, err := r.c.UpdateMany(ctx,
+		bson.M{"series_id": seriesID, "as_of": bson.M{"$lt": before}, "generation": generation - 1},
+		bson.M{"$set": bson.M{"generation": generation}},
+	)
+	return err
+}
+
+// DeleteFrom removes the snapshots of a series taken at or after from, which
+// are the ones a change to a match played at from can affect
+func (r *LeaderboardSnapshotRepo) DeleteFrom(ctx context.Context, seriesID string, from time.Time) error {
+	_, err := r.c.DeleteMany(ctx, bson.M{"series_id": seriesID, "as_of": bson.M{"$gte": from}})
+	return err
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestLeaderboardSnapshots(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	asOf := time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	mt.Run("find decodes entries", func(mt *mtest.T) {
+		repo := &LeaderboardSnapshotRepo{c: mt.Coll}
+
+		namespace := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{
+			{Key: "series_id", Value: "s1"},
+			{Key: "as_of", Value: asOf},
+			{Key: "entries", Value: bson.A{
+				bson.M{"series_id": "s1", "player_id": "a", "rank": 1, "rating": 1016},
+				bson.M{"series_id": "s1", "player_id": "b", "rank": 2, "rating": 984},
+			}},
+		}))
+
+		snapshot, err := repo.Find(context.Background(), "s1", asOf, 3)
+		require.NoError(mt, err)
+		require.Equal(mt, int64(3), mt.GetStartedEvent().Command.Lookup("filter", "generation").AsInt64())
+		require.Len(mt, snapshot.Entries, 2)
+		require.Equal(mt, "a", snapshot.Entries[0].PlayerID)
+		require.Equal(mt, int32(984), snapshot.Entries[1].Rating)
+	})
+
+	mt.Run("find reports a miss", func(mt *mtest.T) {
+		repo := &LeaderboardSnapshotRepo{c: mt.Coll}
+
+		namespace := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch))
+
+		_, err := repo.Find(context.Background(), "s1", asOf, 0)
+		require.True(mt, errors.Is(err, mongo.ErrNoDocuments))
+	})
+
+	mt.Run("delete from drops later snapshots only", func(mt *mtest.T) {
+		repo := &LeaderboardSnapshotRepo{c: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}))
+
+		playedAt := asOf.Add(-48 * time.Hour)
+		require.NoError(mt, repo.DeleteFrom(context.Background(), "s1", playedAt))
+
+		started := mt.GetStartedEvent()
+		require.Equal(mt, "delete", started.CommandName)
+		filter := started.Command.Lookup("deletes", "0", "q").Document()
+		require.Equal(mt, "s1", filter.Lookup("series_id").StringValue())
+		require.Equal(mt, playedAt.UnixMilli(), filter.Lookup("as_of", "$gte").Time().UnixMilli())
+	})
+	mt.Run("upsert keeps a newer generation", func(mt *mtest.T) {
+		repo := &LeaderboardSnapshotRepo{c: mt.Coll}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index: 0, Code: 11000, Message: "E11000 duplicate key error collection: leaderboard_snapshots index: series_id_1_as_of_1",
+		}))
+
+		require.NoError(mt, repo.Upsert(context.Background(), &LeaderboardSnapshot{SeriesID: "s1", AsOf: asOf, Generation: 2}))
+
+		filter := mt.GetStartedEvent().Command.Lookup("updates", "0", "q").Document()
+		require.Equal(mt, int64(2), filter.Lookup("generation", "$lte").AsInt64())
+	})
+
+	mt.Run("advance moves earlier snapshots to the new generation", func(mt *mtest.T) {
+		repo := &LeaderboardSnapshotRepo{c: mt.Coll}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+
+		playedAt := asOf.Add(-48 * time.Hour)
+		require.NoError(mt, repo.Advance(context.Background(), "s1", playedAt, 5))
+
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		require.Equal(mt, playedAt.UnixMilli(), update.Lookup("q", "as_of", "$lt").Time().UnixMilli())
+		require.Equal(mt, int64(4), update.Lookup("q", "generation").AsInt64())
+		require.Equal(mt, int64(5), update.Lookup("u", "$set", "generation").AsInt64())
+	})
+}
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MatchFlag status values
+const (
+	FlagStatusPending  = "pending"
+	FlagStatusApproved = "approved" // Reviewed, match stands
+	FlagStatusRejected = "rejected" // Reviewed, match removed
+)
+
+// MatchFlag reason codes
+const (
+	FlagReasonRepeatedPairing = "REPEATED_PAIRING"
+	FlagReasonLateReport      = "LATE_REPORT"
+	FlagReasonSelfWinReporter = "SELF_WIN_REPORTER"
+)
+
+// MatchFlag is a suspicious match waiting in the admin review queue
+type MatchFlag struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	MatchID    string             `bson:"match_id"`
+	SeriesID   string             `bson:"series_id"`
+	ClubID     string             `bson:"club_id"` // Empty for open series without a club
+	ReportedBy string             `bson:"reported_by,omitempty"`
+	Reasons    []string           `bson:"reasons"`
+	Status     string             `bson:"status"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	ReviewedBy string             `bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time         `bson:"reviewed_at,omitempty"`
+	ReviewNote string             `bson:"review_note,omitempty"`
+}
+
+// MatchFlagRepo manages the match review queue
+type MatchFlagRepo struct {
+	c *mongo.Collection
+}
+
+// NewMatchFlagRepo creates the repository and ensures required indexes exist.
+func NewMatchFlagRepo(db *mongo.Database) *MatchFlagRepo {
+	repo := &MatchFlagRepo{c: db.Collection("match_flags")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create match flag indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *MatchFlagRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "match_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Create adds a flag to the review queue
+func (r *MatchFlagRepo) Create(ctx context.Context, flag *MatchFlag) (*MatchFlag, error) {
+	flag.ID = primitive.NewObjectID()
+	if _, err := r.c.InsertOne(ctx, flag); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+// FindByID retrieves a flag by ID
+func (r *MatchFlagRepo) FindByID(ctx context.Context, id string) (*MatchFlag, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var flag MatchFlag
+	if err := r.c.FindOne(ctx, bson.M{"_id": objID}).Decode(&flag); err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// ListByClub returns the flags of a club (newest first), optionally filtered by status
+func (r *MatchFlagRepo) ListByClub(ctx context.Context, clubID, status string) ([]*MatchFlag, error) {
+	filter := bson.M{"club_id": clubID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var flags []*MatchFlag
+	if err := cursor.All(ctx, &flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// Review records the outcome of an admin review
+func (r *MatchFlagRepo) Review(ctx context.Context, id, status, reviewedBy, note string, now time.Time) (*MatchFlag, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":      status,
+		"reviewed_by": reviewedBy,
+		"reviewed_at": now,
+		"review_note": note,
+	}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var flag MatchFlag
+	if err := r.c.FindOneAndUpdate(ctx, bson.M{"_id": objID, "status": FlagStatusPending}, update, opts).Decode(&flag); err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// Reopen returns a rejected flag to the review queue, undoing a rejection
+// whose match could not be removed
+func (r *MatchFlagRepo) Reopen(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.c.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": FlagStatusRejected},
+		bson.M{
+			"$set":   bson.M{"status": FlagStatusPending},
+			"$unset": bson.M{"reviewed_by": "", "reviewed_at": "", "review_note": ""},
+		},
+	)
+	return err
+}
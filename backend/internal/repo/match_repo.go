@@ -18,6 +18,10 @@ type Match struct {
 	ScoreA    int32              `bson:"score_a"`
 	ScoreB    int32              `bson:"score_b"`
 	PlayedAt  time.Time          `bson:"played_at"`
+
+	// Reporting metadata used for abuse detection
+	ReportedBy string    `bson:"reported_by,omitempty"` // Player ID of the reporter
+	ReportedAt time.Time `bson:"reported_at,omitempty"`
 }
 
 type MatchView struct {
@@ -42,15 +46,17 @@ func NewMatchRepo(db *mongo.Database, players *PlayerRepo) *MatchRepo {
 	}
 }
 
-func (r *MatchRepo) Create(ctx context.Context, seriesID, playerAID, playerBID string, scoreA, scoreB int32, playedAt time.Time) (*Match, error) {
+func (r *MatchRepo) Create(ctx context.Context, seriesID, playerAID, playerBID string, scoreA, scoreB int32, playedAt time.Time, reportedBy string) (*Match, error) {
 	m := &Match{
-		ID:        primitive.NewObjectID(),
-		SeriesID:  seriesID,
-		PlayerAID: playerAID,
-		PlayerBID: playerBID,
-		ScoreA:    scoreA,
-		ScoreB:    scoreB,
-		PlayedAt:  playedAt,
+		ID:         primitive.NewObjectID(),
+		SeriesID:   seriesID,
+		PlayerAID:  playerAID,
+		PlayerBID:  playerBID,
+		ScoreA:     scoreA,
+		ScoreB:     scoreB,
+		PlayedAt:   playedAt,
+		ReportedBy: reportedBy,
+		ReportedAt: time.Now().UTC(),
 	}
 	_, err := r.c.InsertOne(ctx, m)
 	return m, err
@@ -241,10 +247,69 @@ func (r *MatchRepo) Delete(ctx context.Context, matchID string) error {
 	return err
 }
 
+// CountBetweenPlayers counts matches in a series between two players (in either order)
+// played in [from, to]. A zero from means no lower bound; excludeID skips one match.
+func (r *MatchRepo) CountBetweenPlayers(ctx context.Context, seriesID, playerAID, playerBID string, from, to time.Time, excludeID string) (int64, error) {
+	filter := bson.M{
+		"series_id": seriesID,
+		"$or": []bson.M{
+			{"player_a_id": playerAID, "player_b_id": playerBID},
+			{"player_a_id": playerBID, "player_b_id": playerAID},
+		},
+	}
+
+	playedAt := bson.M{"$lte": to}
+	if !from.IsZero() {
+		playedAt["$gte"] = from
+	}
+	filter["played_at"] = playedAt
+
+	if excludeID != "" {
+		if objID, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+			filter["_id"] = bson.M{"$ne": objID}
+		}
+	}
+
+	return r.c.CountDocuments(ctx, filter)
+}
+
+// CountReportedWins counts the matches a player reported in a series while taking part,
+// and how many of those the reporter won
+func (r *MatchRepo) CountReportedWins(ctx context.Context, seriesID, reporterID string) (reported, won int64, err error) {
+	filter := bson.M{
+		"series_id":   seriesID,
+		"reported_by": reporterID,
+		"$or": []bson.M{
+			{"player_a_id": reporterID},
+			{"player_b_id": reporterID},
+		},
+	}
+
+	reported, err = r.c.CountDocuments(ctx, filter)
+	if err != nil || reported == 0 {
+		return reported, 0, err
+	}
+
+	filter["$expr"] = bson.M{"$or": []bson.M{
+		{"$and": []bson.M{{"$eq": []interface{}{"$player_a_id", reporterID}}, {"$gt": []interface{}{"$score_a", "$score_b"}}}},
+		{"$and": []bson.M{{"$eq": []interface{}{"$player_b_id", reporterID}}, {"$gt": []interface{}{"$score_b", "$score_a"}}}},
+	}}
+	won, err = r.c.CountDocuments(ctx, filter)
+	return reported, won, err
+}
+
 // FindAllBySeriesChronological returns all matches for a series in chronological order (oldest first).
 // Used for recalculating standings from scratch.
 func (r *MatchRepo) FindAllBySeriesChronological(ctx context.Context, seriesID string) ([]*Match, error) {
-	filter := bson.M{"series_id": seriesID}
+	return r.findChronological(ctx, bson.M{"series_id": seriesID})
+}
+
+// FindBySeriesPlayedUntil returns the matches of a series played at or before until, oldest first
+func (r *MatchRepo) FindBySeriesPlayedUntil(ctx context.Context, seriesID string, until time.Time) ([]*Match, error) {
+	return r.findChronological(ctx, bson.M{"series_id": seriesID, "played_at": bson.M{"$lte": until}})
+}
+
+func (r *MatchRepo) findChronological(ctx context.Context, filter bson.M) ([]*Match, error) {
 	opts := options.Find().SetSort(bson.D{{Key: "played_at", Value: 1}, {Key: "_id", Value: 1}})
 
 	cursor, err := r.c.Find(ctx, filter, opts)
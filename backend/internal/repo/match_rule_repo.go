@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MatchRule is a CEL business rule applied to matches of a club or a single series.
+// Club rules have an empty SeriesID and apply to every series of the club.
+type MatchRule struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	ClubID     string             `bson:"club_id"`
+	SeriesID   string             `bson:"series_id,omitempty"`
+	Name       string             `bson:"name"`
+	Expression string             `bson:"expression"`
+	ErrorCode  string             `bson:"error_code"`         // Stable code returned when the rule is violated
+	Messages   map[string]string  `bson:"messages,omitempty"` // Localized violation messages keyed by locale
+	Active     bool               `bson:"active"`
+	CreatedBy  string             `bson:"created_by"`
+	CreatedAt  time.Time          `bson:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"`
+}
+
+// MatchRuleRepo manages stored match rules
+type MatchRuleRepo struct {
+	c *mongo.Collection
+}
+
+// NewMatchRuleRepo creates the repository and ensures required indexes exist.
+func NewMatchRuleRepo(db *mongo.Database) *MatchRuleRepo {
+	repo := &MatchRuleRepo{c: db.Collection("match_rules")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create match rule indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *MatchRuleRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "series_id", Value: 1}}},
+		{Keys: bson.D{{Key: "series_id", Value: 1}}},
+	})
+	return err
+}
+
+// Create stores a new rule
+func (r *MatchRuleRepo) Create(ctx context.Context, rule *MatchRule) (*MatchRule, error) {
+	rule.ID = primitive.NewObjectID()
+	if _, err := r.c.InsertOne(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// FindByID retrieves a rule by ID
+func (r *MatchRuleRepo) FindByID(ctx context.Context, id string) (*MatchRule, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var rule MatchRule
+	if err := r.c.FindOne(ctx, bson.M{"_id": objID}).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update applies field updates to a rule and returns the updated document
+func (r *MatchRuleRepo) Update(ctx context.Context, id string, updates map[string]interface{}) (*MatchRule, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var rule MatchRule
+	if err := r.c.FindOneAndUpdate(ctx, bson.M{"_id": objID}, bson.M{"$set": updates}, opts).Decode(&rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Delete removes a rule
+func (r *MatchRuleRepo) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.c.DeleteOne(ctx, bson.M{"_id": objID})
+	return err
+}
+
+// List returns the rules of a club, or of a single series when seriesID is set
+func (r *MatchRuleRepo) List(ctx context.Context, clubID, seriesID string) ([]*MatchRule, error) {
+	filter := bson.M{}
+	if clubID != "" {
+		filter["club_id"] = clubID
+	}
+	if seriesID != "" {
+		filter["series_id"] = seriesID
+	}
+
+	return r.find(ctx, filter)
+}
+
+// FindActiveForSeries returns the active club-wide rules and series rules that apply
+// to matches of the series, club rules first
+func (r *MatchRuleRepo) FindActiveForSeries(ctx context.Context, clubID, seriesID string) ([]*MatchRule, error) {
+	scopes := []bson.M{{"series_id": seriesID}}
+	if clubID != "" {
+		scopes = append(scopes, bson.M{
+			"club_id":   clubID,
+			"series_id": bson.M{"$in": []interface{}{nil, ""}},
+		})
+	}
+
+	return r.find(ctx, bson.M{"active": true, "$or": scopes})
+}
+
+func (r *MatchRuleRepo) find(ctx context.Context, filter bson.M) ([]*MatchRule, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "series_id", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := r.c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var rules []*MatchRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Notification types. The values double as email template names and as the
+// field names in NotificationPreferences.
+const (
+	NotificationMatchReported   = "match_reported"
+	NotificationLadderOvertaken = "ladder_overtaken"
+	NotificationSeriesEnding    = "series_ending"
+	NotificationSeriesSummary   = "series_summary"
+)
+
+// NotificationTypes lists every notification type
+var NotificationTypes = []string{NotificationMatchReported, NotificationLadderOvertaken, NotificationSeriesEnding, NotificationSeriesSummary}
+
+// notificationRetention is how long notification history is kept
+const notificationRetention = 90 * 24 * time.Hour
+
+// NotificationPreferences are a player's opt-ins. Everything is off by default.
+type NotificationPreferences struct {
+	MatchReported   bool `bson:"match_reported"`
+	LadderOvertaken bool `bson:"ladder_overtaken"`
+	SeriesEnding    bool `bson:"series_ending"`
+	SeriesSummary   bool `bson:"series_summary"`
+	DailyDigest     bool `bson:"daily_digest"` // Collect notifications into one email per day instead of sending each
+}
+
+// Enabled reports whether the player opted in to notificationType
+func (p *NotificationPreferences) Enabled(notificationType string) bool {
+	if p == nil {
+		return false
+	}
+	switch notificationType {
+	case NotificationMatchReported:
+		return p.MatchReported
+	case NotificationLadderOvertaken:
+		return p.LadderOvertaken
+	case NotificationSeriesEnding:
+		return p.SeriesEnding
+	case NotificationSeriesSummary:
+		return p.SeriesSummary
+	default:
+		return false
+	}
+}
+
+// Notification is one event a player was, or will be, told about
+type Notification struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	PlayerID    string             `bson:"player_id"`
+	Type        string             `bson:"type"`
+	URL         string             `bson:"url,omitempty"`
+	Data        map[string]string  `bson:"data,omitempty"`
+	DedupKey    string             `bson:"dedup_key,omitempty"` // Set for events that must only be sent once
+	Digest      bool               `bson:"digest"`              // Waiting for the daily digest rather than sent immediately
+	CreatedAt   time.Time          `bson:"created_at"`
+	DeliveredAt *time.Time         `bson:"delivered_at,omitempty"`
+	BatchID     string             `bson:"batch_id,omitempty"` // Digest run that picked the notification up
+}
+
+// NotificationRepo stores notification history and pending digest items
+type NotificationRepo struct {
+	c *mongo.Collection
+}
+
+// NewNotificationRepo creates the repository and ensures required indexes exist.
+func NewNotificationRepo(db *mongo.Database) *NotificationRepo {
+	repo := &NotificationRepo{c: db.Collection("notifications")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create notification indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *NotificationRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "dedup_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			// Digest runs look up undelivered digest items
+			Keys: bson.D{{Key: "digest", Value: 1}, {Key: "delivered_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "batch_id", Value: 1}},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"batch_id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+// Create stores a notification. A duplicate dedup key fails with a
+// duplicate key error, meaning the player was already notified.
+func (r *NotificationRepo) Create(ctx context.Context, notification *Notification) error {
+	notification.ID = primitive.NewObjectID()
+	_, err := r.c.InsertOne(ctx, notification)
+	return err
+}
+
+// ClaimDigest marks every undelivered digest item created up to now as
+// delivered and returns them ordered by player and time. Claiming with a batch
+// ID keeps concurrent runs from sending the same items twice.
+func (r *NotificationRepo) ClaimDigest(ctx context.Context, now time.Time) ([]*Notification, error) {
+	batchID := primitive.NewObjectID().Hex()
+	_, err := r.c.UpdateMany(ctx,
+		bson.M{"digest": true, "delivered_at": nil, "created_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"delivered_at": now, "batch_id": batchID}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.c.Find(ctx, bson.M{"batch_id": batchID},
+		options.Find().SetSort(bson.D{{Key: "player_id", Value: 1}, {Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var notifications []*Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
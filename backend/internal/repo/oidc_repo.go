@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OIDCLoginState is a pending OpenID Connect login between the redirect to the
+// provider and the callback
+type OIDCLoginState struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	State        string             `bson:"state"`
+	Provider     string             `bson:"provider"`
+	Nonce        string             `bson:"nonce"`
+	CodeVerifier string             `bson:"code_verifier"` // PKCE verifier, never sent to the browser
+	BindingHash  string             `bson:"binding_hash"`  // Hash of the cookie binding the login to the browser
+	ExpiresAt    time.Time          `bson:"expires_at"`
+	CreatedAt    time.Time          `bson:"created_at"`
+}
+
+// OIDCIdentity links an external provider account to a player
+type OIDCIdentity struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Provider    string             `bson:"provider"`
+	Subject     string             `bson:"subject"` // Provider's stable user ID (sub claim)
+	PlayerID    primitive.ObjectID `bson:"player_id"`
+	Email       string             `bson:"email"` // Verified email at link time
+	LinkedAt    time.Time          `bson:"linked_at"`
+	LastLoginAt time.Time          `bson:"last_login_at"`
+}
+
+// OIDCRepo handles OpenID Connect login states and linked identities
+type OIDCRepo struct {
+	states     *mongo.Collection
+	identities *mongo.Collection
+}
+
+// NewOIDCRepo creates a new OIDC repository
+func NewOIDCRepo(db *mongo.Database) *OIDCRepo {
+	repo := &OIDCRepo{
+		states:     db.Collection("oidc_login_states"),
+		identities: db.Collection("oidc_identities"),
+	}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create OIDC indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *OIDCRepo) createIndexes(ctx context.Context) error {
+	_, err := r.states.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "state", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0), // TTL index
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.identities.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "player_id", Value: 1}},
+		},
+	})
+	return err
+}
+
+// CreateState stores a pending login valid for ttl
+func (r *OIDCRepo) CreateState(ctx context.Context, state, provider, nonce, codeVerifier, bindingHash string, ttl time.Duration) (*OIDCLoginState, error) {
+	now := time.Now()
+	s := &OIDCLoginState{
+		ID:           primitive.NewObjectID(),
+		State:        state,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		BindingHash:  bindingHash,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+
+	_, err := r.states.InsertOne(ctx, s)
+	return s, err
+}
+
+// ConsumeState removes and returns an unexpired pending login.
+// Fails with mongo.ErrNoDocuments when it is unknown, expired or already used.
+func (r *OIDCRepo) ConsumeState(ctx context.Context, state string) (*OIDCLoginState, error) {
+	var s OIDCLoginState
+	err := r.states.FindOneAndDelete(ctx, bson.M{
+		"state":      state,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FindIdentity retrieves the player link for a provider account
+func (r *OIDCRepo) FindIdentity(ctx context.Context, provider, subject string) (*OIDCIdentity, error) {
+	var identity OIDCIdentity
+	if err := r.identities.FindOne(ctx, bson.M{"provider": provider, "subject": subject}).Decode(&identity); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// LinkIdentity links a provider account to a player, or records a new login
+// when the link already exists
+func (r *OIDCRepo) LinkIdentity(ctx context.Context, provider, subject string, playerID primitive.ObjectID, email string) error {
+	now := time.Now()
+	_, err := r.identities.UpdateOne(ctx,
+		bson.M{"provider": provider, "subject": subject},
+		bson.M{
+			"$set": bson.M{"last_login_at": now},
+			"$setOnInsert": bson.M{
+				"player_id": playerID,
+				"email":     email,
+				"linked_at": now,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
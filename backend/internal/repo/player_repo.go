@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
 	"time"
 
@@ -27,10 +28,20 @@ func safeInt32(value int64) (int32, error) {
 	return int32(value), nil
 }
 
+// Club roles stored in ClubMembership.Role
+const (
+	ClubRoleMember         = "member"
+	ClubRoleAdmin          = "admin"
+	ClubRoleMatchSecretary = "match_secretary" // Reports and edits any club match
+	ClubRoleSeriesManager  = "series_manager"  // Manages series, not members
+	ClubRoleTreasurer      = "treasurer"       // Billing
+	ClubRoleCoach          = "coach"           // Read-only access to admin views
+)
+
 // ClubMembership represents a player's membership in a club
 type ClubMembership struct {
 	ClubID    primitive.ObjectID `bson:"club_id"`
-	Role      string             `bson:"role"` // "member", "admin"
+	Role      string             `bson:"role"` // One of the ClubRole constants
 	JoinedAt  time.Time          `bson:"joined_at"`
 	InvitedBy primitive.ObjectID `bson:"invited_by,omitempty"` // Who invited this player
 }
@@ -44,11 +55,20 @@ type Player struct {
 	Email           string           `bson:"email"`
 	FirstName       string           `bson:"first_name"`
 	LastName        string           `bson:"last_name"`
+	Locale          string           `bson:"locale,omitempty"` // Preferred language, empty uses the platform default
 	ClubMemberships []ClubMembership `bson:"club_memberships"`
 	IsPlatformOwner bool             `bson:"is_platform_owner"`
 	CreatedAt       time.Time        `bson:"created_at"`
 	LastLoginAt     *time.Time       `bson:"last_login_at,omitempty"`
 
+	// Set by platform owners to block sign-in
+	Disabled       bool       `bson:"disabled,omitempty"`
+	DisabledAt     *time.Time `bson:"disabled_at,omitempty"`
+	DisabledReason string     `bson:"disabled_reason,omitempty"`
+
+	// Opt-in member notifications; nil means none enabled
+	Notifications *NotificationPreferences `bson:"notifications,omitempty"`
+
 	// Enhanced search functionality
 	SearchKeys *SearchKeys `bson:"search_keys,omitempty"`
 }
@@ -123,7 +143,7 @@ func (r *PlayerRepo) Create(ctx context.Context, name, clubID string) (*Player,
 
 		p.ClubMemberships = []ClubMembership{{
 			ClubID:   clubObjID,
-			Role:     "member",
+			Role:     ClubRoleMember,
 			JoinedAt: time.Now(),
 		}}
 	}
@@ -596,6 +616,106 @@ func (r *PlayerRepo) SetPlatformOwner(ctx context.Context, email string, isPlatf
 	return err
 }
 
+// SetDisabled disables or re-enables a player's account
+func (r *PlayerRepo) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool, reason string) (*Player, error) {
+	update := bson.M{"$unset": bson.M{"disabled": "", "disabled_at": "", "disabled_reason": ""}}
+	if disabled {
+		update = bson.M{"$set": bson.M{"disabled": true, "disabled_at": time.Now(), "disabled_reason": reason}}
+	}
+
+	var p Player
+	err := r.c.FindOneAndUpdate(ctx, bson.M{"_id": id}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SetNotificationPreferences replaces a player's notification preferences
+func (r *PlayerRepo) SetNotificationPreferences(ctx context.Context, id primitive.ObjectID, prefs NotificationPreferences) (*Player, error) {
+	var p Player
+	err := r.c.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"notifications": prefs}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DisableNotification turns off one notification type for a player, or all
+// of them when notificationType is empty
+func (r *PlayerRepo) DisableNotification(ctx context.Context, id primitive.ObjectID, notificationType string) error {
+	set := bson.M{}
+	for _, t := range NotificationTypes {
+		if notificationType == "" || notificationType == t {
+			set["notifications."+t] = false
+		}
+	}
+	if len(set) == 0 {
+		return fmt.Errorf("unknown notification type %s", notificationType)
+	}
+
+	result, err := r.c.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// SearchAccounts lists players that can log in, optionally matching query against
+// email or display name. Results are ordered by ID for cursor pagination.
+func (r *PlayerRepo) SearchAccounts(ctx context.Context, query string, pageSize int32, pageToken string) ([]*Player, string, error) {
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	filter := bson.M{
+		"email": bson.M{"$nin": bson.A{"", nil}, "$not": primitive.Regex{Pattern: `^noemail-.*@klubbspel\.internal$`}},
+	}
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"email": pattern},
+			bson.M{"display_name": pattern},
+		}
+	}
+	if pageToken != "" {
+		objID, err := primitive.ObjectIDFromHex(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		filter["_id"] = bson.M{"$gt": objID}
+	}
+
+	cursor, err := r.c.Find(ctx, filter, options.Find().
+		SetLimit(int64(pageSize+1)).
+		SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var players []*Player
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, "", err
+	}
+
+	nextPageToken := ""
+	if len(players) > int(pageSize) {
+		players = players[:pageSize]
+		nextPageToken = players[len(players)-1].ID.Hex()
+	}
+	return players, nextPageToken, nil
+}
+
 // AddClubMembership adds a club membership to a player
 func (r *PlayerRepo) AddClubMembership(ctx context.Context, email string, membership *ClubMembership) error {
 	// First, remove any existing membership for this club (to prevent duplicates)
@@ -682,7 +802,7 @@ func (r *PlayerRepo) IsClubAdmin(ctx context.Context, email, clubID string) (boo
 		"club_memberships": bson.M{
 			"$elemMatch": bson.M{
 				"club_id": clubObjID,
-				"role":    "admin",
+				"role":    ClubRoleAdmin,
 			},
 		},
 	})
@@ -731,20 +851,22 @@ func (r *PlayerRepo) GetPlayerMemberships(ctx context.Context, playerID string,
 	return player.ClubMemberships, nil
 }
 
-// UpdateProfile updates a player's first name and last name
-func (r *PlayerRepo) UpdateProfile(ctx context.Context, email, firstName, lastName string) error {
+// UpdateProfile updates a player's first name, last name and, when non-empty, preferred locale
+func (r *PlayerRepo) UpdateProfile(ctx context.Context, email, firstName, lastName, locale string) error {
 	filter := bson.M{"email": email}
 
 	// Create the display name from first and last name
 	displayName := fmt.Sprintf("%s %s", firstName, lastName)
 
-	update := bson.M{
-		"$set": bson.M{
-			"first_name":   firstName,
-			"last_name":    lastName,
-			"display_name": displayName,
-		},
+	set := bson.M{
+		"first_name":   firstName,
+		"last_name":    lastName,
+		"display_name": displayName,
+	}
+	if locale != "" {
+		set["locale"] = locale
 	}
+	update := bson.M{"$set": set}
 
 	result, err := r.c.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -0,0 +1,353 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxHeadToHeadMatches caps the matches listed in a head-to-head record
+const maxHeadToHeadMatches = 200
+
+// formMatches is the number of recent matches in a player's form
+const formMatches = 10
+
+// SportRecord is a match record, overall or in one sport
+type SportRecord struct {
+	Sport    int32 `bson:"sport"` // 0 for the overall record
+	Played   int32 `bson:"played"`
+	Won      int32 `bson:"won"`
+	SetsWon  int32 `bson:"sets_won"`
+	SetsLost int32 `bson:"sets_lost"`
+}
+
+// StreakRun is a run of consecutive wins or losses
+type StreakRun struct {
+	Length    int32     `bson:"length"`
+	StartedAt time.Time `bson:"started_at"`
+	EndedAt   time.Time `bson:"ended_at"`
+}
+
+// PlayerMatchRecord is a match seen from one player's side
+type PlayerMatchRecord struct {
+	MatchID               primitive.ObjectID `bson:"_id"`
+	SeriesID              string             `bson:"series_id"`
+	SeriesTitle           string             `bson:"series_title"`
+	Sport                 int32              `bson:"sport"`
+	OpponentID            string             `bson:"opponent_id"`
+	OpponentName          string             `bson:"opponent_name"`
+	SetsWon               int32              `bson:"sets_won"`
+	SetsLost              int32              `bson:"sets_lost"`
+	Won                   bool               `bson:"won"`
+	PlayedAt              time.Time          `bson:"played_at"`
+	OpponentCurrentRating int32              `bson:"opponent_current_rating"` // Opponent's ELO rating in the series today, not at match time
+}
+
+// PlayerStats is a player's record across the series visible to a viewer
+type PlayerStats struct {
+	Overall       SportRecord
+	BySport       []SportRecord
+	LongestWin    *StreakRun
+	LongestLoss   *StreakRun
+	CurrentStreak int32 // Positive for wins in a row, negative for losses
+	Form          []PlayerMatchRecord
+	BestWin       *PlayerMatchRecord
+}
+
+// HeadToHead is the record between two players, seen from the first one
+type HeadToHead struct {
+	Overall SportRecord
+	BySport []SportRecord
+	Matches []PlayerMatchRecord
+}
+
+// PlayerStatsRepo aggregates player statistics from the matches collection
+type PlayerStatsRepo struct {
+	c *mongo.Collection
+}
+
+func NewPlayerStatsRepo(db *mongo.Database) *PlayerStatsRepo {
+	r := &PlayerStatsRepo{
+		c: db.Collection("matches"),
+	}
+	if err := r.createIndexes(context.Background()); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *PlayerStatsRepo) createIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "player_a_id", Value: 1},
+				{Key: "played_at", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "player_b_id", Value: 1},
+				{Key: "played_at", Value: -1},
+			},
+		},
+	}
+
+	_, err := r.c.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// streakGroup holds the longest and latest run of wins or of losses
+type streakGroup struct {
+	Won     bool      `bson:"_id"`
+	Longest StreakRun `bson:"longest"`
+	Latest  struct {
+		Length int32 `bson:"length"`
+		Run    int64 `bson:"run"`
+	} `bson:"latest"`
+}
+
+// PlayerStats aggregates the record, streaks, form and best win of a player.
+// The best win is the win against the opponent with the highest current rating.
+func (r *PlayerStatsRepo) PlayerStats(ctx context.Context, playerID string, viewer *SeriesViewer) (*PlayerStats, error) {
+	match := bson.M{"$or": bson.A{
+		bson.M{"player_a_id": playerID},
+		bson.M{"player_b_id": playerID},
+	}}
+
+	pipeline := append(playerMatchStages(match, playerID, viewer), bson.D{{Key: "$facet", Value: bson.M{
+		"overall":  recordStages(nil),
+		"by_sport": recordStages("$sport"),
+		"streaks":  streakStages(),
+		"form": append(mongo.Pipeline{
+			{{Key: "$sort", Value: bson.D{{Key: "played_at", Value: -1}, {Key: "_id", Value: -1}}}},
+			{{Key: "$limit", Value: formMatches}},
+		}, matchRecordStages()...),
+		// Ratings at match time are not stored, so the best win ranks beaten
+		// opponents by their current leaderboard rating
+		"best_win": append(mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"won": true, "format": bson.M{"$ne": SeriesFormatLadder}}}},
+			{{Key: "$lookup", Value: bson.M{
+				"from": "leaderboard",
+				"let":  bson.M{"sid": "$series_id", "oid": "$opponent_id"},
+				"pipeline": mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{"$expr": bson.M{"$and": bson.A{
+						bson.M{"$eq": bson.A{"$series_id", "$$sid"}},
+						bson.M{"$eq": bson.A{"$player_id", "$$oid"}},
+					}}}}},
+					{{Key: "$project", Value: bson.M{"_id": 0, "rating": 1}}},
+				},
+				"as": "opponent_entry",
+			}}},
+			{{Key: "$unwind", Value: "$opponent_entry"}},
+			{{Key: "$set", Value: bson.M{"opponent_current_rating": "$opponent_entry.rating"}}},
+			{{Key: "$sort", Value: bson.D{{Key: "opponent_current_rating", Value: -1}, {Key: "played_at", Value: -1}}}},
+			{{Key: "$limit", Value: 1}},
+		}, matchRecordStages()...),
+	}}})
+
+	var facets struct {
+		Overall []SportRecord       `bson:"overall"`
+		BySport []SportRecord       `bson:"by_sport"`
+		Streaks []streakGroup       `bson:"streaks"`
+		Form    []PlayerMatchRecord `bson:"form"`
+		BestWin []PlayerMatchRecord `bson:"best_win"`
+	}
+	if err := r.aggregateOne(ctx, pipeline, &facets); err != nil {
+		return nil, err
+	}
+
+	stats := &PlayerStats{BySport: facets.BySport, Form: facets.Form}
+	if len(facets.Overall) > 0 {
+		stats.Overall = facets.Overall[0]
+	}
+	if len(facets.BestWin) > 0 {
+		stats.BestWin = &facets.BestWin[0]
+	}
+
+	// The current streak is the run that started last
+	var latestRun int64 = -1
+	for i := range facets.Streaks {
+		group := &facets.Streaks[i]
+		if group.Won {
+			stats.LongestWin = &group.Longest
+		} else {
+			stats.LongestLoss = &group.Longest
+		}
+		if group.Latest.Run > latestRun {
+			latestRun = group.Latest.Run
+			stats.CurrentStreak = group.Latest.Length
+			if !group.Won {
+				stats.CurrentStreak = -group.Latest.Length
+			}
+		}
+	}
+	return stats, nil
+}
+
+// HeadToHead aggregates the record between playerID and opponentID
+func (r *PlayerStatsRepo) HeadToHead(ctx context.Context, playerID, opponentID string, viewer *SeriesViewer) (*HeadToHead, error) {
+	match := bson.M{"$or": bson.A{
+		bson.M{"player_a_id": playerID, "player_b_id": opponentID},
+		bson.M{"player_a_id": opponentID, "player_b_id": playerID},
+	}}
+
+	pipeline := append(playerMatchStages(match, playerID, viewer), bson.D{{Key: "$facet", Value: bson.M{
+		"overall":  recordStages(nil),
+		"by_sport": recordStages("$sport"),
+		"matches": append(mongo.Pipeline{
+			{{Key: "$sort", Value: bson.D{{Key: "played_at", Value: -1}, {Key: "_id", Value: -1}}}},
+			{{Key: "$limit", Value: maxHeadToHeadMatches}},
+		}, matchRecordStages()...),
+	}}})
+
+	var facets struct {
+		Overall []SportRecord       `bson:"overall"`
+		BySport []SportRecord       `bson:"by_sport"`
+		Matches []PlayerMatchRecord `bson:"matches"`
+	}
+	if err := r.aggregateOne(ctx, pipeline, &facets); err != nil {
+		return nil, err
+	}
+
+	h2h := &HeadToHead{BySport: facets.BySport, Matches: facets.Matches}
+	if len(facets.Overall) > 0 {
+		h2h.Overall = facets.Overall[0]
+	}
+	return h2h, nil
+}
+
+func (r *PlayerStatsRepo) aggregateOne(ctx context.Context, pipeline mongo.Pipeline, v any) error {
+	cursor, err := r.c.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	if !cursor.Next(ctx) {
+		return cursor.Err()
+	}
+	return cursor.Decode(v)
+}
+
+// playerMatchStages selects the matches in series visible to viewer and
+// rewrites them from playerID's side: opponent_id, sets_won, sets_lost, won,
+// plus the series' title, sport and format. A nil viewer sees every series.
+func playerMatchStages(match bson.M, playerID string, viewer *SeriesViewer) mongo.Pipeline {
+	seriesMatch := bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$sid"}}}
+	if viewer != nil {
+		seriesMatch["$or"] = viewer.visible()
+	}
+
+	isA := bson.M{"$eq": bson.A{"$player_a_id", playerID}}
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "series",
+			"let":  bson.M{"sid": bson.M{"$convert": bson.M{"input": "$series_id", "to": "objectId", "onError": nil, "onNull": nil}}},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: seriesMatch}},
+				{{Key: "$project", Value: bson.M{"title": 1, "sport": 1, "format": 1}}},
+			},
+			"as": "series",
+		}}},
+		{{Key: "$unwind", Value: "$series"}},
+		{{Key: "$set", Value: bson.M{
+			"opponent_id":  bson.M{"$cond": bson.A{isA, "$player_b_id", "$player_a_id"}},
+			"sets_won":     bson.M{"$cond": bson.A{isA, "$score_a", "$score_b"}},
+			"sets_lost":    bson.M{"$cond": bson.A{isA, "$score_b", "$score_a"}},
+			"series_title": "$series.title",
+			"sport":        "$series.sport",
+			"format":       "$series.format",
+		}}},
+		{{Key: "$set", Value: bson.M{"won": bson.M{"$gt": bson.A{"$sets_won", "$sets_lost"}}}}},
+	}
+}
+
+// recordStages groups matches into SportRecords by groupID
+func recordStages(groupID any) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":       groupID,
+			"played":    bson.M{"$sum": 1},
+			"won":       bson.M{"$sum": bson.M{"$cond": bson.A{"$won", 1, 0}}},
+			"sets_won":  bson.M{"$sum": "$sets_won"},
+			"sets_lost": bson.M{"$sum": "$sets_lost"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"sport":     bson.M{"$ifNull": bson.A{"$_id", 0}},
+			"played":    1,
+			"won":       1,
+			"sets_won":  1,
+			"sets_lost": 1,
+		}}},
+	}
+}
+
+// streakStages numbers the runs of equal results in play order and returns,
+// for wins and for losses, the longest run and the latest run
+func streakStages() mongo.Pipeline {
+	playOrder := bson.D{{Key: "played_at", Value: 1}, {Key: "_id", Value: 1}}
+	return mongo.Pipeline{
+		{{Key: "$setWindowFields", Value: bson.M{
+			"sortBy": playOrder,
+			"output": bson.M{"prev_won": bson.M{"$shift": bson.M{"output": "$won", "by": -1}}},
+		}}},
+		{{Key: "$setWindowFields", Value: bson.M{
+			"sortBy": playOrder,
+			"output": bson.M{"run": bson.M{
+				"$sum":   bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$won", "$prev_won"}}, 0, 1}},
+				"window": bson.M{"documents": bson.A{"unbounded", "current"}},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        "$run",
+			"won":        bson.M{"$first": "$won"},
+			"length":     bson.M{"$sum": 1},
+			"started_at": bson.M{"$min": "$played_at"},
+			"ended_at":   bson.M{"$max": "$played_at"},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$won",
+			"longest": bson.M{"$top": bson.M{
+				"sortBy": bson.D{{Key: "length", Value: -1}, {Key: "_id", Value: -1}},
+				"output": bson.M{"length": "$length", "started_at": "$started_at", "ended_at": "$ended_at"},
+			}},
+			"latest": bson.M{"$top": bson.M{
+				"sortBy": bson.M{"_id": -1},
+				"output": bson.M{"length": "$length", "run": "$_id"},
+			}},
+		}}},
+	}
+}
+
+// matchRecordStages looks up the opponent's name and shapes PlayerMatchRecords
+func matchRecordStages() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from": "players",
+			"let":  bson.M{"oid": bson.M{"$convert": bson.M{"input": "$opponent_id", "to": "objectId", "onError": nil, "onNull": nil}}},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$oid"}}}}},
+				{{Key: "$project", Value: bson.M{"_id": 0, "display_name": 1}}},
+			},
+			"as": "opponent",
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"series_id":               1,
+			"series_title":            1,
+			"sport":                   1,
+			"opponent_id":             1,
+			"opponent_name":           bson.M{"$ifNull": bson.A{bson.M{"$first": "$opponent.display_name"}, ""}},
+			"sets_won":                1,
+			"sets_lost":               1,
+			"won":                     1,
+			"played_at":               1,
+			"opponent_current_rating": bson.M{"$ifNull": bson.A{"$opponent_current_rating", 0}},
+		}}},
+	}
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPlayerStats_DecodesFacetsAndPicksCurrentStreak(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("latest run is a loss streak", func(mt *mtest.T) {
+		repo := &PlayerStatsRepo{c: mt.Coll}
+		start := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+
+		namespace := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{
+			{Key: "overall", Value: bson.A{bson.M{"sport": 0, "played": 7, "won": 4, "sets_won": 15, "sets_lost": 12}}},
+			{Key: "by_sport", Value: bson.A{bson.M{"sport": 1, "played": 7, "won": 4, "sets_won": 15, "sets_lost": 12}}},
+			{Key: "streaks", Value: bson.A{
+				bson.M{"_id": true, "longest": bson.M{"length": 3, "started_at": start, "ended_at": start.AddDate(0, 0, 2)}, "latest": bson.M{"length": 1, "run": int64(3)}},
+				bson.M{"_id": false, "longest": bson.M{"length": 2, "started_at": start.AddDate(0, 0, 5), "ended_at": start.AddDate(0, 0, 6)}, "latest": bson.M{"length": 2, "run": int64(4)}},
+			}},
+			{Key: "form", Value: bson.A{}},
+			{Key: "best_win", Value: bson.A{bson.M{"_id": primitive.NewObjectID(), "opponent_id": "opp", "opponent_name": "Bo", "won": true, "opponent_current_rating": 1650}}},
+		}))
+
+		stats, err := repo.PlayerStats(context.Background(), "p1", &SeriesViewer{ClubIDs: []string{"club1"}})
+		require.NoError(mt, err)
+		require.Equal(mt, int32(7), stats.Overall.Played)
+		require.Len(mt, stats.BySport, 1)
+		require.Equal(mt, int32(3), stats.LongestWin.Length)
+		require.Equal(mt, int32(2), stats.LongestLoss.Length)
+		require.Equal(mt, int32(-2), stats.CurrentStreak)
+		require.Equal(mt, int32(1650), stats.BestWin.OpponentCurrentRating)
+
+		// The series lookup is restricted to series the viewer can see
+		started := mt.GetStartedEvent()
+		require.Equal(mt, "aggregate", started.CommandName)
+		lookup := started.Command.Lookup("pipeline", "1", "$lookup")
+		seriesMatch := lookup.Document().Lookup("pipeline", "0", "$match").Document()
+		visible, err := seriesMatch.LookupErr("$or")
+		require.NoError(mt, err)
+		values, err := visible.Array().Values()
+		require.NoError(mt, err)
+		require.Len(mt, values, 2, "open series plus the viewer's clubs")
+	})
+
+	mt.Run("no matches", func(mt *mtest.T) {
+		repo := &PlayerStatsRepo{c: mt.Coll}
+
+		namespace := mt.Coll.Database().Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{
+			{Key: "overall", Value: bson.A{}},
+			{Key: "by_sport", Value: bson.A{}},
+			{Key: "streaks", Value: bson.A{}},
+			{Key: "form", Value: bson.A{}},
+			{Key: "best_win", Value: bson.A{}},
+		}))
+
+		stats, err := repo.PlayerStats(context.Background(), "p1", nil)
+		require.NoError(mt, err)
+		require.Zero(mt, stats.Overall.Played)
+		require.Nil(mt, stats.LongestWin)
+		require.Nil(mt, stats.BestWin)
+		require.Zero(mt, stats.CurrentStreak)
+
+		// Without a viewer every series is included
+		started := mt.GetStartedEvent()
+		seriesMatch := started.Command.Lookup("pipeline", "1", "$lookup").Document().Lookup("pipeline", "0", "$match").Document()
+		_, err = seriesMatch.LookupErr("$or")
+		require.Error(mt, err)
+	})
+}
@@ -0,0 +1,208 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SeriesEntry status values
+const (
+	EntryStatusPendingApproval = "pending_approval"
+	EntryStatusRegistered      = "registered"
+	EntryStatusWaitlisted      = "waitlisted"
+	EntryStatusWithdrawn       = "withdrawn"
+	EntryStatusRejected        = "rejected"
+)
+
+// SeriesEntry records a player's registration for a series
+type SeriesEntry struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	SeriesID     string             `bson:"series_id"`
+	PlayerID     string             `bson:"player_id"`
+	Status       string             `bson:"status"`
+	RegisteredAt time.Time          `bson:"registered_at"`         // Also the waitlist ordering key
+	AdmittedAt   *time.Time         `bson:"admitted_at,omitempty"` // When the entry last became registered
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
+
+// SeriesEntryRepo manages series entry lists
+type SeriesEntryRepo struct {
+	c *mongo.Collection
+}
+
+// NewSeriesEntryRepo creates the repository and ensures required indexes exist.
+func NewSeriesEntryRepo(db *mongo.Database) *SeriesEntryRepo {
+	repo := &SeriesEntryRepo{c: db.Collection("series_entries")}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create series entry indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *SeriesEntryRepo) createIndexes(ctx context.Context) error {
+	_, err := r.c.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "series_id", Value: 1}, {Key: "player_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "status", Value: 1}, {Key: "registered_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "status", Value: 1}, {Key: "admitted_at", Value: 1}},
+		},
+	})
+	return err
+}
+
+// Upsert creates or re-activates an entry with the given status.
+// Re-registering after a withdrawal moves the player to the back of the queue.
+func (r *SeriesEntryRepo) Upsert(ctx context.Context, seriesID, playerID, status string, now time.Time) (*SeriesEntry, error) {
+	filter := bson.M{"series_id": seriesID, "player_id": playerID}
+	set := bson.M{
+		"status":        status,
+		"registered_at": now,
+		"updated_at":    now,
+	}
+	if status == EntryStatusRegistered {
+		set["admitted_at"] = now
+	}
+	update := bson.M{"$set": set}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var entry SeriesEntry
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateStatus changes the status of an existing entry, keeping its queue position.
+func (r *SeriesEntryRepo) UpdateStatus(ctx context.Context, seriesID, playerID, status string, now time.Time) (*SeriesEntry, error) {
+	filter := bson.M{"series_id": seriesID, "player_id": playerID}
+	set := bson.M{"status": status, "updated_at": now}
+	if status == EntryStatusRegistered {
+		set["admitted_at"] = now
+	}
+	update := bson.M{"$set": set}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var entry SeriesEntry
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindBySeriesAndPlayer retrieves a single entry.
+func (r *SeriesEntryRepo) FindBySeriesAndPlayer(ctx context.Context, seriesID, playerID string) (*SeriesEntry, error) {
+	var entry SeriesEntry
+	err := r.c.FindOne(ctx, bson.M{"series_id": seriesID, "player_id": playerID}).Decode(&entry)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListBySeries returns entries in registration order, optionally filtered by status.
+func (r *SeriesEntryRepo) ListBySeries(ctx context.Context, seriesID string, statuses []string) ([]*SeriesEntry, error) {
+	filter := bson.M{"series_id": seriesID}
+	if len(statuses) > 0 {
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "registered_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.c.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var entries []*SeriesEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CountByStatus counts the entries of a series with the given status.
+func (r *SeriesEntryRepo) CountByStatus(ctx context.Context, seriesID, status string) (int64, error) {
+	return r.c.CountDocuments(ctx, bson.M{"series_id": seriesID, "status": status})
+}
+
+// PromoteFirstWaitlisted atomically registers the longest-waiting waitlisted
+// entry, so concurrent withdrawals never promote the same entry twice.
+// Returns mongo.ErrNoDocuments when the waitlist is empty.
+func (r *SeriesEntryRepo) PromoteFirstWaitlisted(ctx context.Context, seriesID string, now time.Time) (*SeriesEntry, error) {
+	filter := bson.M{"series_id": seriesID, "status": EntryStatusWaitlisted}
+	update := bson.M{"$set": bson.M{"status": EntryStatusRegistered, "admitted_at": now, "updated_at": now}}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "registered_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+	var entry SeriesEntry
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CountAdmittedBefore counts the registered entries of a series admitted
+// before entry. Entries admitted at the same time are ordered by ID, and
+// entries from before admission times were recorded come first.
+func (r *SeriesEntryRepo) CountAdmittedBefore(ctx context.Context, entry *SeriesEntry) (int64, error) {
+	earlier := bson.A{
+		bson.M{"admitted_at": bson.M{"$exists": false}, "_id": bson.M{"$ne": entry.ID}},
+	}
+	if entry.AdmittedAt != nil {
+		earlier = append(earlier,
+			bson.M{"admitted_at": bson.M{"$lt": *entry.AdmittedAt}},
+			bson.M{"admitted_at": *entry.AdmittedAt, "_id": bson.M{"$lt": entry.ID}},
+		)
+	}
+	return r.c.CountDocuments(ctx, bson.M{
+		"series_id": entry.SeriesID,
+		"status":    EntryStatusRegistered,
+		"$or":       earlier,
+	})
+}
+
+// Demote moves a registered entry back to the waitlist, keeping its queue
+// position. Returns mongo.ErrNoDocuments if the entry is no longer registered.
+func (r *SeriesEntryRepo) Demote(ctx context.Context, seriesID, playerID string, now time.Time) (*SeriesEntry, error) {
+	filter := bson.M{"series_id": seriesID, "player_id": playerID, "status": EntryStatusRegistered}
+	update := bson.M{
+		"$set":   bson.M{"status": EntryStatusWaitlisted, "updated_at": now},
+		"$unset": bson.M{"admitted_at": ""},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var entry SeriesEntry
+	if err := r.c.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// IsRegistered reports whether a player holds a confirmed entry in the series.
+func (r *SeriesEntryRepo) IsRegistered(ctx context.Context, seriesID, playerID string) (bool, error) {
+	count, err := r.c.CountDocuments(ctx, bson.M{
+		"series_id": seriesID,
+		"player_id": playerID,
+		"status":    EntryStatusRegistered,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -19,6 +19,9 @@ type SeriesPlayer struct {
 	Position  int32              `bson:"position"`
 	JoinedAt  time.Time          `bson:"joined_at"`
 	UpdatedAt time.Time          `bson:"updated_at"`
+
+	// InitialRating is the ELO rating the player starts the series with (0 means default).
+	InitialRating int32 `bson:"initial_rating,omitempty"`
 }
 
 // SeriesPlayerRepo manages ladder player documents.
@@ -223,3 +226,26 @@ func (r *SeriesPlayerRepo) UpsertPlayer(ctx context.Context, seriesID, playerID
 	_, err := r.c.UpdateOne(ctx, filter, update, opts)
 	return err
 }
+
+// UpsertPlayerRating creates or updates a series player entry with a starting ELO rating.
+// Used when seeding an open play series from the final standings of a previous series.
+func (r *SeriesPlayerRepo) UpsertPlayerRating(ctx context.Context, seriesID, playerID string, position, rating int32, now time.Time) error {
+	filter := bson.M{
+		"series_id": seriesID,
+		"player_id": playerID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"position":       position,
+			"initial_rating": rating,
+			"updated_at":     now,
+		},
+		"$setOnInsert": bson.M{
+			"joined_at": now,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.c.UpdateOne(ctx, filter, update, opts)
+	return err
+}
@@ -23,6 +23,49 @@ type Series struct {
 	LadderRules    int32              `bson:"ladder_rules"`    // LadderRules enum value (only for LADDER format)
 	ScoringProfile int32              `bson:"scoring_profile"` // ScoringProfile enum value
 	SetsToPlay     int32              `bson:"sets_to_play"`    // For table tennis: 3 or 5
+
+	// Registration is nil for series where any club player can report matches
+	Registration *SeriesRegistration `bson:"registration,omitempty"`
+
+	// GuestPlayerIDs lists non-members allowed to take part in a CLUB_ONLY series
+	GuestPlayerIDs []string `bson:"guest_player_ids,omitempty"`
+
+	// Tiebreakers orders players with equal ELO rating (Tiebreaker enum values, empty = default chain)
+	Tiebreakers []int32 `bson:"tiebreakers,omitempty"`
+
+	// SnapshotGeneration is bumped whenever a match change invalidates leaderboard snapshots;
+	// only snapshots of the current generation are served
+	SnapshotGeneration int64 `bson:"snapshot_generation,omitempty"`
+}
+
+// SeriesVisibilityClubOnly mirrors SERIES_VISIBILITY_CLUB_ONLY
+const SeriesVisibilityClubOnly int32 = 1
+
+// SeriesFormatLadder mirrors SERIES_FORMAT_LADDER
+const SeriesFormatLadder int32 = 2
+
+// IsClubOnly reports whether the series is restricted to its club and guest players
+func (s *Series) IsClubOnly() bool {
+	return s.Visibility == SeriesVisibilityClubOnly
+}
+
+// IsGuest reports whether the player was explicitly allowed into the series
+func (s *Series) IsGuest(playerID string) bool {
+	for _, id := range s.GuestPlayerIDs {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// SeriesRegistration holds the entry list settings for a series
+type SeriesRegistration struct {
+	Required         bool       `bson:"required"`            // Only registered players can report matches
+	OpensAt          *time.Time `bson:"opens_at,omitempty"`  // Registration window start (nil = open immediately)
+	ClosesAt         *time.Time `bson:"closes_at,omitempty"` // Registration window end (nil = never closes)
+	MaxEntrants      int32      `bson:"max_entrants"`        // 0 = unlimited; further entrants are waitlisted
+	ApprovalRequired bool       `bson:"approval_required"`   // Entries must be approved by a club admin
 }
 
 type SeriesRepo struct{ c *mongo.Collection }
@@ -31,7 +74,7 @@ func NewSeriesRepo(db *mongo.Database) *SeriesRepo {
 	return &SeriesRepo{c: db.Collection("series")}
 }
 
-func (r *SeriesRepo) Create(ctx context.Context, clubID, title string, startsAt, endsAt time.Time, visibility int32, sport, format, ladderRules, scoringProfile, setsToPlay int32) (*Series, error) {
+func (r *SeriesRepo) Create(ctx context.Context, clubID, title string, startsAt, endsAt time.Time, visibility int32, sport, format, ladderRules, scoringProfile, setsToPlay int32, registration *SeriesRegistration, tiebreakers []int32) (*Series, error) {
 	s := &Series{
 		ID:             primitive.NewObjectID(),
 		ClubID:         clubID,
@@ -44,6 +87,8 @@ func (r *SeriesRepo) Create(ctx context.Context, clubID, title string, startsAt,
 		LadderRules:    ladderRules,
 		ScoringProfile: scoringProfile,
 		SetsToPlay:     setsToPlay,
+		Registration:   registration,
+		Tiebreakers:    tiebreakers,
 	}
 	_, err := r.c.InsertOne(ctx, s)
 	return s, err
@@ -115,6 +160,28 @@ type SeriesListFilters struct {
 	Sport       *int32
 	ClubIDs     []string // Club IDs to filter by
 	IncludeOpen bool     // Whether to include open series
+
+	// Viewer restricts CLUB_ONLY series to the viewer's clubs and guest entries.
+	// Nil means no restriction (platform owners).
+	Viewer *SeriesViewer
+}
+
+// SeriesViewer describes who is listing series for visibility filtering
+type SeriesViewer struct {
+	ClubIDs  []string // Clubs the viewer is a member of
+	PlayerID string   // Viewer's player ID (empty for anonymous)
+}
+
+// visible returns the conditions of which a series must match one to be visible
+func (v *SeriesViewer) visible() []bson.M {
+	visible := []bson.M{{"visibility": bson.M{"$ne": SeriesVisibilityClubOnly}}}
+	if len(v.ClubIDs) > 0 {
+		visible = append(visible, bson.M{"club_id": bson.M{"$in": v.ClubIDs}})
+	}
+	if v.PlayerID != "" {
+		visible = append(visible, bson.M{"guest_player_ids": v.PlayerID})
+	}
+	return visible
 }
 
 func (r *SeriesRepo) ListWithCursor(ctx context.Context, pageSize int32, cursor string, filters SeriesListFilters) ([]*Series, bool, bool, error) {
@@ -154,6 +221,18 @@ func (r *SeriesRepo) ListWithCursor(ctx context.Context, pageSize int32, cursor
 		}
 	}
 
+	// Hide CLUB_ONLY series the viewer has no access to
+	if filters.Viewer != nil {
+		visible := filters.Viewer.visible()
+
+		if existing, ok := filter["$or"]; ok {
+			delete(filter, "$or")
+			filter["$and"] = []bson.M{{"$or": existing}, {"$or": visible}}
+		} else {
+			filter["$or"] = visible
+		}
+	}
+
 	// Add cursor condition for pagination
 	if cursor != "" {
 		cursorObjID, err := primitive.ObjectIDFromHex(cursor)
@@ -203,6 +282,23 @@ func (r *SeriesRepo) FindByID(ctx context.Context, id string) (*Series, error) {
 	return &series, err
 }
 
+// ListEndingBetween returns series whose end falls in [from, to)
+func (r *SeriesRepo) ListEndingBetween(ctx context.Context, from, to time.Time) ([]*Series, error) {
+	cursor, err := r.c.Find(ctx, bson.M{"ends_at": bson.M{"$gte": from, "$lt": to}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var series []*Series
+	if err := cursor.All(ctx, &series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
 // Update applies partial updates to a series document and returns the updated series
 func (r *SeriesRepo) Update(ctx context.Context, id string, updates map[string]interface{}) (*Series, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
@@ -218,6 +314,25 @@ func (r *SeriesRepo) Update(ctx context.Context, id string, updates map[string]i
 	return r.FindByID(ctx, id)
 }
 
+// BumpSnapshotGeneration increments the series' snapshot generation and returns the new value
+func (r *SeriesRepo) BumpSnapshotGeneration(ctx context.Context, id string) (int64, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+
+	var series Series
+	err = r.c.FindOneAndUpdate(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$inc": bson.M{"snapshot_generation": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"snapshot_generation": 1}),
+	).Decode(&series)
+	if err != nil {
+		return 0, err
+	}
+	return series.SnapshotGeneration, nil
+}
+
 // Delete removes a series document by ID
 func (r *SeriesRepo) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
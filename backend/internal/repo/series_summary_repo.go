@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SeriesAward is one season award. Value depends on the category: the final
+// rating, a number of matches, a rating gap, rating points gained or the
+// length of a streak. Ladder series count positions instead of rating.
+type SeriesAward struct {
+	PlayerID   string `bson:"player_id"`
+	Value      int32  `bson:"value"`
+	OpponentID string `bson:"opponent_id,omitempty"` // Upset only
+	MatchID    string `bson:"match_id,omitempty"`    // Upset only
+}
+
+// SeriesSummary is the season summary stored when a series ends. Awards
+// nobody qualified for are nil.
+type SeriesSummary struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	SeriesID         string             `bson:"series_id"`
+	GeneratedAt      time.Time          `bson:"generated_at"`
+	MatchesPlayed    int32              `bson:"matches_played"`
+	Players          int32              `bson:"players"`
+	Champion         *SeriesAward       `bson:"champion,omitempty"`
+	MostMatches      *SeriesAward       `bson:"most_matches,omitempty"`
+	BiggestUpset     *SeriesAward       `bson:"biggest_upset,omitempty"`
+	MostImproved     *SeriesAward       `bson:"most_improved,omitempty"`
+	LongestWinStreak *SeriesAward       `bson:"longest_win_streak,omitempty"`
+}
+
+// SeriesSummaryRepo stores one season summary per series
+type SeriesSummaryRepo struct {
+	c *mongo.Collection
+}
+
+func NewSeriesSummaryRepo(db *mongo.Database) *SeriesSummaryRepo {
+	r := &SeriesSummaryRepo{
+		c: db.Collection("series_summaries"),
+	}
+	if err := r.createIndexes(context.Background()); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r *SeriesSummaryRepo) createIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "series_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err := r.c.Indexes().CreateMany(ctx, indexes)
+	return err
+}
+
+// Upsert stores the summary, replacing any earlier summary of the series
+func (r *SeriesSummaryRepo) Upsert(ctx context.Context, summary *SeriesSummary) error {
+	replacement := *summary
+	replacement.ID = primitive.NilObjectID
+
+	result := r.c.FindOneAndReplace(ctx,
+		bson.M{"series_id": summary.SeriesID},
+		replacement,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After),
+	)
+	return result.Decode(summary)
+}
+
+// FindBySeriesID returns the summary of a series, or mongo.ErrNoDocuments
+func (r *SeriesSummaryRepo) FindBySeriesID(ctx context.Context, seriesID string) (*SeriesSummary, error) {
+	var summary SeriesSummary
+	if err := r.c.FindOne(ctx, bson.M{"series_id": seriesID}).Decode(&summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Delete removes the summary of a series so it is generated again
+func (r *SeriesSummaryRepo) Delete(ctx context.Context, seriesID string) error {
+	_, err := r.c.DeleteOne(ctx, bson.M{"series_id": seriesID})
+	return err
+}
+
+// SummarizedSeriesIDs returns which of seriesIDs already have a summary
+func (r *SeriesSummaryRepo) SummarizedSeriesIDs(ctx context.Context, seriesIDs []string) (map[string]bool, error) {
+	summarized := make(map[string]bool)
+	if len(seriesIDs) == 0 {
+		return summarized, nil
+	}
+
+	cursor, err := r.c.Find(ctx, bson.M{"series_id": bson.M{"$in": seriesIDs}},
+		options.Find().SetProjection(bson.M{"series_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			SeriesID string `bson:"series_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		summarized[doc.SeriesID] = true
+	}
+	return summarized, cursor.Err()
+}
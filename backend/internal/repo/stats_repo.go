@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SystemStats holds platform-wide counters
+type SystemStats struct {
+	Clubs            int64
+	Players          int64
+	Accounts         int64 // Players with a real email address
+	Series           int64
+	Matches          int64
+	ActiveUsers7d    int64
+	ActiveUsers30d   int64
+	ActiveSessions   int64
+	DisabledAccounts int64
+}
+
+// StatsRepo counts documents across collections for the admin dashboard
+type StatsRepo struct {
+	db *mongo.Database
+}
+
+// NewStatsRepo creates a new stats repository
+func NewStatsRepo(db *mongo.Database) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+type statsCount struct {
+	collection string
+	filter     bson.M
+	target     *int64
+}
+
+// SystemStats counts clubs, players, series, matches and recent activity
+func (r *StatsRepo) SystemStats(ctx context.Context) (*SystemStats, error) {
+	now := time.Now()
+	realEmail := bson.M{"$nin": bson.A{"", nil}, "$not": primitive.Regex{Pattern: `^noemail-.*@klubbspel\.internal$`}}
+
+	stats := &SystemStats{}
+	counts := []statsCount{
+		{"clubs", bson.M{}, &stats.Clubs},
+		{"players", bson.M{}, &stats.Players},
+		{"players", bson.M{"email": realEmail}, &stats.Accounts},
+		{"series", bson.M{}, &stats.Series},
+		{"matches", bson.M{}, &stats.Matches},
+		{"players", bson.M{"last_login_at": bson.M{"$gte": now.AddDate(0, 0, -7)}}, &stats.ActiveUsers7d},
+		{"players", bson.M{"last_login_at": bson.M{"$gte": now.AddDate(0, 0, -30)}}, &stats.ActiveUsers30d},
+		{"api_tokens", bson.M{"revoked": false, "expires_at": bson.M{"$gt": now}}, &stats.ActiveSessions},
+		{"players", bson.M{"disabled": true}, &stats.DisabledAccounts},
+	}
+
+	for _, count := range counts {
+		n, err := r.db.Collection(count.collection).CountDocuments(ctx, count.filter)
+		if err != nil {
+			return nil, err
+		}
+		*count.target = n
+	}
+	return stats, nil
+}
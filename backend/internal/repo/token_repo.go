@@ -2,7 +2,11 @@ package repo
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,23 +17,87 @@ import (
 
 // APIToken represents an authentication token for a user
 type APIToken struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty"`
+	Token           string             `bson:"-"`                           // Plaintext token; only set when issued
+	TokenHash       string             `bson:"token_hash"`                  // HMAC-SHA256 of the token
+	Email           string             `bson:"email"`                       // User's email address
+	PlayerID        primitive.ObjectID `bson:"player_id"`                   // Associated player ID
+	IssuedAt        time.Time          `bson:"issued_at"`                   // When the token was issued
+	ExpiresAt       time.Time          `bson:"expires_at"`                  // When the session expires (slides on refresh)
+	AccessExpiresAt *time.Time         `bson:"access_expires_at,omitempty"` // When the current access token expires; unset for legacy tokens
+	LastUsedAt      *time.Time         `bson:"last_used_at,omitempty"`      // Last time token was used
+	UserAgent       string             `bson:"user_agent,omitempty"`        // User agent when token was created
+	IPAddress       string             `bson:"ip_address,omitempty"`        // IP address when token was created
+	Revoked         bool               `bson:"revoked"`                     // Whether the token has been revoked
+	RevokedAt       *time.Time         `bson:"revoked_at,omitempty"`        // When the token was revoked
+	ImpersonatedBy  string             `bson:"impersonated_by,omitempty"`   // Platform owner acting as the user, for impersonation tokens
+	ReadOnly        bool               `bson:"read_only,omitempty"`         // Only read methods may be called with the token
+}
+
+// RefreshToken is a single-use token that renews an API token. Each refresh
+// rotates it; all tokens issued for one session share a family, which is the
+// API token's ID.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Token     string             `bson:"-"`                 // Plaintext token; only set when issued
+	TokenHash string             `bson:"token_hash"`        // HMAC-SHA256 of the token
+	FamilyID  primitive.ObjectID `bson:"family_id"`         // API token (session) the refresh token renews
+	Email     string             `bson:"email"`             // User's email address
+	IssuedAt  time.Time          `bson:"issued_at"`         // When the token was issued
+	ExpiresAt time.Time          `bson:"expires_at"`        // When the token expires
+	UsedAt    *time.Time         `bson:"used_at,omitempty"` // When the token was rotated
+	Revoked   bool               `bson:"revoked"`           // Whether the family was revoked after reuse
+}
+
+// APIKeyPrefix starts every API key so keys can be told apart from session tokens
+const APIKeyPrefix = "kbs_"
+
+// APIKey is a long-lived, scoped key for scripts and integrations. Only a hash
+// of the key is stored; the key itself is shown once at creation.
+type APIKey struct {
 	ID         primitive.ObjectID `bson:"_id,omitempty"`
-	Token      string             `bson:"token"`                  // UUID v4 token
-	Email      string             `bson:"email"`                  // User's email address
-	PlayerID   primitive.ObjectID `bson:"player_id"`              // Associated player ID
-	IssuedAt   time.Time          `bson:"issued_at"`              // When the token was issued
-	ExpiresAt  time.Time          `bson:"expires_at"`             // When the token expires
-	LastUsedAt *time.Time         `bson:"last_used_at,omitempty"` // Last time token was used
-	UserAgent  string             `bson:"user_agent,omitempty"`   // User agent when token was created
-	IPAddress  string             `bson:"ip_address,omitempty"`   // IP address when token was created
-	Revoked    bool               `bson:"revoked"`                // Whether the token has been revoked
-	RevokedAt  *time.Time         `bson:"revoked_at,omitempty"`   // When the token was revoked
+	Name       string             `bson:"name"`                   // Label chosen by the creator
+	Prefix     string             `bson:"prefix"`                 // First characters of the key, to recognize it
+	KeyHash    string             `bson:"key_hash"`               // SHA-256 of the key
+	OwnerEmail string             `bson:"owner_email"`            // Creator; personal keys act as this player
+	PlayerID   primitive.ObjectID `bson:"player_id"`              // Creator's player ID
+	ClubID     string             `bson:"club_id,omitempty"`      // Restricts the key to one club
+	ClubLevel  bool               `bson:"club_level"`             // Club key: permissions come from scopes, not the creator's roles
+	Scopes     []string           `bson:"scopes"`                 // Granted scopes, e.g. "matches:write"
+	CreatedAt  time.Time          `bson:"created_at"`             // When the key was created
+	ExpiresAt  *time.Time         `bson:"expires_at,omitempty"`   // Optional expiry
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty"` // Last time the key was used
+	Revoked    bool               `bson:"revoked"`                // Whether the key has been revoked
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty"`   // When the key was revoked
+}
+
+// IsAPIKey reports whether a bearer token is an API key rather than a session token
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 of an API key as stored at rest
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashToken returns the hex-encoded digest of a token as stored at rest
+func HashToken(token string, pepper []byte) string {
+	if len(pepper) == 0 {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // MagicLinkToken represents a short-lived token for magic link authentication
 type MagicLinkToken struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Token     string             `bson:"token"`                // UUID v4 token
+	Token     string             `bson:"-"`                    // Plaintext token; only set when issued
+	TokenHash string             `bson:"token_hash"`           // HMAC-SHA256 of the token
 	Email     string             `bson:"email"`                // Email address to authenticate
 	ExpiresAt time.Time          `bson:"expires_at"`           // When the token expires (15 minutes)
 	UsedAt    *time.Time         `bson:"used_at,omitempty"`    // When the token was consumed
@@ -37,17 +105,31 @@ type MagicLinkToken struct {
 	IPAddress string             `bson:"ip_address,omitempty"` // IP address when created
 }
 
-// TokenRepo handles API token and magic link token operations
+// TokenRepo handles API token and magic link token operations. Session, refresh
+// and magic link tokens are stored as an HMAC-SHA256 keyed with a server pepper,
+// so a database dump does not contain usable tokens. Tokens are looked up by
+// their hash rather than compared in memory; without the pepper a caller cannot
+// choose the hash, so lookup timing reveals nothing about stored tokens.
 type TokenRepo struct {
-	apiTokens   *mongo.Collection
-	magicTokens *mongo.Collection
+	apiTokens     *mongo.Collection
+	magicTokens   *mongo.Collection
+	refreshTokens *mongo.Collection
+	apiKeys       *mongo.Collection
+	pepper        []byte
+	legacyTokens  bool // Also accept plaintext tokens not yet converted by the hash-tokens migration
 }
 
-// NewTokenRepo creates a new token repository
-func NewTokenRepo(db *mongo.Database) *TokenRepo {
+// NewTokenRepo creates a new token repository. An empty pepper hashes tokens
+// with plain SHA-256. legacyTokens keeps plaintext tokens written before
+// tokens were hashed valid; enable it only until the hash-tokens migration has run.
+func NewTokenRepo(db *mongo.Database, pepper string, legacyTokens bool) *TokenRepo {
 	repo := &TokenRepo{
-		apiTokens:   db.Collection("api_tokens"),
-		magicTokens: db.Collection("magic_link_tokens"),
+		apiTokens:     db.Collection("api_tokens"),
+		magicTokens:   db.Collection("magic_link_tokens"),
+		refreshTokens: db.Collection("refresh_tokens"),
+		apiKeys:       db.Collection("api_keys"),
+		pepper:        []byte(pepper),
+		legacyTokens:  legacyTokens,
 	}
 
 	// Create indexes for efficient lookups
@@ -58,13 +140,47 @@ func NewTokenRepo(db *mongo.Database) *TokenRepo {
 	return repo
 }
 
+// MatchesToken reports whether a stored hash belongs to a token, e.g. to
+// recognize the caller's own session in a list
+func (r *TokenRepo) MatchesToken(tokenHash, token string) bool {
+	return hmac.Equal([]byte(tokenHash), []byte(r.hash(token)))
+}
+
+func (r *TokenRepo) hash(token string) string {
+	return HashToken(token, r.pepper)
+}
+
+// tokenMatch is the $or clause finding a token by its hash, or by plaintext for
+// documents not yet migrated while legacy tokens are accepted
+func (r *TokenRepo) tokenMatch(token string) bson.A {
+	match := bson.A{bson.M{"token_hash": r.hash(token)}}
+	if r.legacyTokens {
+		match = append(match, bson.M{"token": token})
+	}
+	return match
+}
+
+// legacyTokenIndex indexes plaintext tokens written before tokens were hashed.
+// It is sparse so hashed documents without a token field do not collide.
+const legacyTokenIndex = "token_sparse"
+
 // createIndexes creates necessary database indexes
 func (r *TokenRepo) createIndexes(ctx context.Context) error {
+	// The original unique index on token is not sparse and would reject a
+	// second document without a plaintext token
+	for _, c := range []*mongo.Collection{r.apiTokens, r.magicTokens, r.refreshTokens} {
+		_, _ = c.Indexes().DropOne(ctx, "token_1")
+	}
+
 	// API token indexes
 	_, err := r.apiTokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
 		{
 			Keys:    bson.D{{Key: "token", Value: 1}},
-			Options: options.Index().SetUnique(true),
+			Options: options.Index().SetUnique(true).SetSparse(true).SetName(legacyTokenIndex),
 		},
 		{
 			Keys: bson.D{{Key: "email", Value: 1}},
@@ -83,9 +199,13 @@ func (r *TokenRepo) createIndexes(ctx context.Context) error {
 
 	// Magic link token indexes
 	_, err = r.magicTokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
 		{
 			Keys:    bson.D{{Key: "token", Value: 1}},
-			Options: options.Index().SetUnique(true),
+			Options: options.Index().SetUnique(true).SetSparse(true).SetName(legacyTokenIndex),
 		},
 		{
 			Keys: bson.D{{Key: "email", Value: 1}},
@@ -95,6 +215,45 @@ func (r *TokenRepo) createIndexes(ctx context.Context) error {
 			Options: options.Index().SetExpireAfterSeconds(0), // TTL index
 		},
 	})
+	if err != nil {
+		return err
+	}
+
+	// Refresh token indexes; used tokens are kept until expiry to detect reuse
+	_, err = r.refreshTokens.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true).SetName(legacyTokenIndex),
+		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0), // TTL index
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// API key indexes; expired keys are kept so they still show up as expired
+	_, err = r.apiKeys.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "owner_email", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "club_id", Value: 1}},
+		},
+	})
 
 	return err
 }
@@ -104,6 +263,7 @@ func (r *TokenRepo) CreateMagicLinkToken(ctx context.Context, token, email, ipAd
 	mlt := &MagicLinkToken{
 		ID:        primitive.NewObjectID(),
 		Token:     token,
+		TokenHash: r.hash(token),
 		Email:     email,
 		ExpiresAt: time.Now().Add(15 * time.Minute), // 15 minutes expiry
 		CreatedAt: time.Now(),
@@ -119,6 +279,7 @@ func (r *TokenRepo) CreateMagicLinkTokenWithExpiry(ctx context.Context, token, e
 	mlt := &MagicLinkToken{
 		ID:        primitive.NewObjectID(),
 		Token:     token,
+		TokenHash: r.hash(token),
 		Email:     email,
 		ExpiresAt: time.Now().Add(expiryDuration),
 		CreatedAt: time.Now(),
@@ -133,7 +294,7 @@ func (r *TokenRepo) CreateMagicLinkTokenWithExpiry(ctx context.Context, token, e
 func (r *TokenRepo) GetMagicLinkToken(ctx context.Context, token string) (*MagicLinkToken, error) {
 	var mlt MagicLinkToken
 	err := r.magicTokens.FindOne(ctx, bson.M{
-		"token":      token,
+		"$or":        r.tokenMatch(token),
 		"expires_at": bson.M{"$gt": time.Now()},
 		"used_at":    bson.M{"$exists": false},
 	}).Decode(&mlt)
@@ -149,24 +310,49 @@ func (r *TokenRepo) GetMagicLinkToken(ctx context.Context, token string) (*Magic
 func (r *TokenRepo) ConsumeMagicLinkToken(ctx context.Context, token string) error {
 	now := time.Now()
 	_, err := r.magicTokens.UpdateOne(ctx,
-		bson.M{"token": token},
+		bson.M{"$or": r.tokenMatch(token)},
 		bson.M{"$set": bson.M{"used_at": now}},
 	)
 	return err
 }
 
-// CreateAPIToken creates a new long-lived API token
-func (r *TokenRepo) CreateAPIToken(ctx context.Context, token, email string, playerID primitive.ObjectID, userAgent, ipAddress string) (*APIToken, error) {
+// CreateAPIToken creates a new session with a short-lived access token. The
+// session itself lives for sessionTTL and is extended on every refresh.
+func (r *TokenRepo) CreateAPIToken(ctx context.Context, token, email string, playerID primitive.ObjectID, userAgent, ipAddress string, accessTTL, sessionTTL time.Duration) (*APIToken, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(accessTTL)
 	at := &APIToken{
-		ID:        primitive.NewObjectID(),
-		Token:     token,
-		Email:     email,
-		PlayerID:  playerID,
-		IssuedAt:  time.Now(),
-		ExpiresAt: time.Now().Add(30 * 24 * time.Hour), // 30 days expiry
-		UserAgent: userAgent,
-		IPAddress: ipAddress,
-		Revoked:   false,
+		ID:              primitive.NewObjectID(),
+		Token:           token,
+		TokenHash:       r.hash(token),
+		Email:           email,
+		PlayerID:        playerID,
+		IssuedAt:        now,
+		ExpiresAt:       now.Add(sessionTTL),
+		AccessExpiresAt: &accessExpiresAt,
+		UserAgent:       userAgent,
+		IPAddress:       ipAddress,
+		Revoked:         false,
+	}
+
+	_, err := r.apiTokens.InsertOne(ctx, at)
+	return at, err
+}
+
+// CreateImpersonationToken creates a read-only session for a platform owner
+// acting as another user. It cannot be refreshed and expires after ttl.
+func (r *TokenRepo) CreateImpersonationToken(ctx context.Context, token string, player *Player, impersonatedBy string, ttl time.Duration) (*APIToken, error) {
+	now := time.Now()
+	at := &APIToken{
+		ID:             primitive.NewObjectID(),
+		Token:          token,
+		TokenHash:      r.hash(token),
+		Email:          player.Email,
+		PlayerID:       player.ID,
+		IssuedAt:       now,
+		ExpiresAt:      now.Add(ttl),
+		ImpersonatedBy: impersonatedBy,
+		ReadOnly:       true,
 	}
 
 	_, err := r.apiTokens.InsertOne(ctx, at)
@@ -176,10 +362,17 @@ func (r *TokenRepo) CreateAPIToken(ctx context.Context, token, email string, pla
 // GetAPIToken retrieves an API token by token string
 func (r *TokenRepo) GetAPIToken(ctx context.Context, token string) (*APIToken, error) {
 	var at APIToken
+	now := time.Now()
 	err := r.apiTokens.FindOne(ctx, bson.M{
-		"token":      token,
-		"expires_at": bson.M{"$gt": time.Now()},
+		"expires_at": bson.M{"$gt": now},
 		"revoked":    false,
+		"$and": bson.A{
+			bson.M{"$or": r.tokenMatch(token)},
+			bson.M{"$or": bson.A{
+				bson.M{"access_expires_at": bson.M{"$exists": false}},
+				bson.M{"access_expires_at": bson.M{"$gt": now}},
+			}},
+		},
 	}).Decode(&at)
 
 	if err != nil {
@@ -189,11 +382,198 @@ func (r *TokenRepo) GetAPIToken(ctx context.Context, token string) (*APIToken, e
 	return &at, nil
 }
 
+// RotateAPIToken replaces the access token of an unrevoked session and extends
+// the session. Fails with mongo.ErrNoDocuments when the session was revoked or
+// has expired.
+func (r *TokenRepo) RotateAPIToken(ctx context.Context, id primitive.ObjectID, token string, accessTTL, sessionTTL time.Duration) (*APIToken, error) {
+	now := time.Now()
+	var at APIToken
+	err := r.apiTokens.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "revoked": false, "expires_at": bson.M{"$gt": now}},
+		bson.M{
+			"$set": bson.M{
+				"token_hash":        r.hash(token),
+				"access_expires_at": now.Add(accessTTL),
+				"expires_at":        now.Add(sessionTTL),
+				"last_used_at":      now,
+			},
+			"$unset": bson.M{"token": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&at)
+	if err != nil {
+		return nil, err
+	}
+	at.Token = token
+	return &at, nil
+}
+
+// CreateRefreshToken issues a refresh token for a session
+func (r *TokenRepo) CreateRefreshToken(ctx context.Context, token string, session *APIToken, ttl time.Duration) (*RefreshToken, error) {
+	now := time.Now()
+	rt := &RefreshToken{
+		ID:        primitive.NewObjectID(),
+		Token:     token,
+		TokenHash: r.hash(token),
+		FamilyID:  session.ID,
+		Email:     session.Email,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	_, err := r.refreshTokens.InsertOne(ctx, rt)
+	return rt, err
+}
+
+// ConsumeRefreshToken marks an unused, unexpired refresh token as used and
+// returns it. Fails with mongo.ErrNoDocuments otherwise.
+func (r *TokenRepo) ConsumeRefreshToken(ctx context.Context, token string) (*RefreshToken, error) {
+	now := time.Now()
+	var rt RefreshToken
+	err := r.refreshTokens.FindOneAndUpdate(ctx,
+		bson.M{
+			"$or":        r.tokenMatch(token),
+			"expires_at": bson.M{"$gt": now},
+			"used_at":    bson.M{"$exists": false},
+			"revoked":    false,
+		},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&rt)
+	if err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+// FindRefreshToken retrieves a refresh token in any state, for reuse detection
+func (r *TokenRepo) FindRefreshToken(ctx context.Context, token string) (*RefreshToken, error) {
+	var rt RefreshToken
+	if err := r.refreshTokens.FindOne(ctx, bson.M{"$or": r.tokenMatch(token)}).Decode(&rt); err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+// RevokeTokenFamily revokes a session and every refresh token issued for it
+func (r *TokenRepo) RevokeTokenFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.refreshTokens.UpdateMany(ctx,
+		bson.M{"family_id": familyID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.apiTokens.UpdateOne(ctx,
+		bson.M{"_id": familyID, "revoked": false},
+		bson.M{"$set": bson.M{
+			"revoked":    true,
+			"revoked_at": now,
+		}},
+	)
+	return err
+}
+
+// CreateAPIKey stores a new API key
+func (r *TokenRepo) CreateAPIKey(ctx context.Context, key *APIKey) error {
+	key.ID = primitive.NewObjectID()
+	key.CreatedAt = time.Now()
+	_, err := r.apiKeys.InsertOne(ctx, key)
+	return err
+}
+
+// FindAPIKey retrieves an active (unrevoked, unexpired) API key by its plain value
+func (r *TokenRepo) FindAPIKey(ctx context.Context, key string) (*APIKey, error) {
+	var apiKey APIKey
+	err := r.apiKeys.FindOne(ctx, bson.M{
+		"key_hash": HashAPIKey(key),
+		"revoked":  false,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	}).Decode(&apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// GetAPIKeyByID retrieves an API key by ID in any state
+func (r *TokenRepo) GetAPIKeyByID(ctx context.Context, id string) (*APIKey, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKey APIKey
+	if err := r.apiKeys.FindOne(ctx, bson.M{"_id": objID}).Decode(&apiKey); err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// ListPersonalAPIKeys returns a user's unrevoked personal keys, newest first
+func (r *TokenRepo) ListPersonalAPIKeys(ctx context.Context, email string) ([]*APIKey, error) {
+	return r.findAPIKeys(ctx, bson.M{"owner_email": email, "club_level": false, "revoked": false})
+}
+
+// ListClubAPIKeys returns a club's unrevoked club keys, newest first
+func (r *TokenRepo) ListClubAPIKeys(ctx context.Context, clubID string) ([]*APIKey, error) {
+	return r.findAPIKeys(ctx, bson.M{"club_id": clubID, "club_level": true, "revoked": false})
+}
+
+func (r *TokenRepo) findAPIKeys(ctx context.Context, filter bson.M) ([]*APIKey, error) {
+	cursor, err := r.apiKeys.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var keys []*APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an API key. Fails with mongo.ErrNoDocuments when it is
+// unknown or already revoked.
+func (r *TokenRepo) RevokeAPIKey(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.apiKeys.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked": false},
+		bson.M{"$set": bson.M{
+			"revoked":    true,
+			"revoked_at": now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateAPIKeyLastUsed updates the last used timestamp for an API key
+func (r *TokenRepo) UpdateAPIKeyLastUsed(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.apiKeys.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"last_used_at": time.Now()}},
+	)
+	return err
+}
+
 // UpdateLastUsed updates the last used timestamp for an API token
 func (r *TokenRepo) UpdateLastUsed(ctx context.Context, token string) error {
 	now := time.Now()
 	_, err := r.apiTokens.UpdateOne(ctx,
-		bson.M{"token": token},
+		bson.M{"$or": r.tokenMatch(token)},
 		bson.M{"$set": bson.M{"last_used_at": now}},
 	)
 	return err
@@ -203,7 +583,7 @@ func (r *TokenRepo) UpdateLastUsed(ctx context.Context, token string) error {
 func (r *TokenRepo) RevokeAPIToken(ctx context.Context, token string) error {
 	now := time.Now()
 	_, err := r.apiTokens.UpdateOne(ctx,
-		bson.M{"token": token},
+		bson.M{"$or": r.tokenMatch(token)},
 		bson.M{"$set": bson.M{
 			"revoked":    true,
 			"revoked_at": now,
@@ -212,17 +592,102 @@ func (r *TokenRepo) RevokeAPIToken(ctx context.Context, token string) error {
 	return err
 }
 
-// RevokeAllUserTokens revokes all API tokens for a specific user
-func (r *TokenRepo) RevokeAllUserTokens(ctx context.Context, email string) error {
+// ListActiveAPITokens returns a user's unexpired, unrevoked API tokens, newest first
+func (r *TokenRepo) ListActiveAPITokens(ctx context.Context, email string) ([]*APIToken, error) {
+	cursor, err := r.apiTokens.Find(ctx, bson.M{
+		"email":      email,
+		"expires_at": bson.M{"$gt": time.Now()},
+		"revoked":    false,
+	}, options.Find().SetSort(bson.D{{Key: "issued_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var tokens []*APIToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeAPITokenByID revokes one of a user's API tokens. Fails with
+// mongo.ErrNoDocuments when the token does not exist, belongs to someone else
+// or is already revoked.
+func (r *TokenRepo) RevokeAPITokenByID(ctx context.Context, id, email string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result, err := r.apiTokens.UpdateOne(ctx,
+		bson.M{"_id": objID, "email": email, "revoked": false},
+		bson.M{"$set": bson.M{
+			"revoked":    true,
+			"revoked_at": now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// RevokeOtherUserTokens revokes all of a user's API tokens except keepToken
+// and returns how many were revoked
+func (r *TokenRepo) RevokeOtherUserTokens(ctx context.Context, email, keepToken string) (int64, error) {
+	now := time.Now()
+	result, err := r.apiTokens.UpdateMany(ctx,
+		bson.M{"email": email, "revoked": false, "$nor": r.tokenMatch(keepToken)},
+		bson.M{"$set": bson.M{
+			"revoked":    true,
+			"revoked_at": now,
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// RevokeAllUserTokens revokes all API tokens for a specific user and returns
+// how many were revoked
+func (r *TokenRepo) RevokeAllUserTokens(ctx context.Context, email string) (int64, error) {
 	now := time.Now()
-	_, err := r.apiTokens.UpdateMany(ctx,
+	result, err := r.apiTokens.UpdateMany(ctx,
 		bson.M{"email": email, "revoked": false},
 		bson.M{"$set": bson.M{
 			"revoked":    true,
 			"revoked_at": now,
 		}},
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// RevokeAllUserAPIKeys revokes every API key created by a user, personal and
+// club-level, and returns how many were revoked
+func (r *TokenRepo) RevokeAllUserAPIKeys(ctx context.Context, email string) (int64, error) {
+	now := time.Now()
+	result, err := r.apiKeys.UpdateMany(ctx,
+		bson.M{"owner_email": email, "revoked": false},
+		bson.M{"$set": bson.M{
+			"revoked":    true,
+			"revoked_at": now,
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
 }
 
 // CleanupExpiredTokens removes expired and used tokens (cleanup job)
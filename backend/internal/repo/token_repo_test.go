@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestHashToken(t *testing.T) {
+	const token = "2f1c6a3e-8d44-4c8e-9a57-0b3f1e2d7c90"
+
+	plain := sha256.Sum256([]byte(token))
+	require.Equal(t, hex.EncodeToString(plain[:]), HashToken(token, nil), "no pepper falls back to SHA-256")
+
+	peppered := HashToken(token, []byte("pepper"))
+	require.NotEqual(t, HashToken(token, nil), peppered)
+	require.Equal(t, peppered, HashToken(token, []byte("pepper")))
+	require.NotEqual(t, peppered, HashToken(token, []byte("other pepper")))
+
+	repo := &TokenRepo{pepper: []byte("pepper")}
+	require.True(t, repo.MatchesToken(peppered, token))
+	require.False(t, repo.MatchesToken(peppered, token+"x"))
+	require.False(t, repo.MatchesToken("", token))
+}
+
+func TestTokenMatchAcceptsPlaintextOnlyForLegacyTokens(t *testing.T) {
+	repo := &TokenRepo{pepper: []byte("pepper")}
+	require.Equal(t, bson.A{bson.M{"token_hash": HashToken("abc", []byte("pepper"))}}, repo.tokenMatch("abc"))
+
+	repo.legacyTokens = true
+	require.Contains(t, repo.tokenMatch("abc"), bson.M{"token": "abc"})
+}
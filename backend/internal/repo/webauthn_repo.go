@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebAuthn ceremony types
+const (
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login"
+)
+
+// WebAuthnCredential is a passkey registered by a player
+type WebAuthnCredential struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	CredentialID string             `bson:"credential_id"` // Base64url-encoded credential ID
+	PlayerID     primitive.ObjectID `bson:"player_id"`
+	Email        string             `bson:"email"`
+	PublicKey    []byte             `bson:"public_key"` // COSE_Key encoding
+	Algorithm    int64              `bson:"algorithm"`  // COSE algorithm identifier
+	SignCount    uint32             `bson:"sign_count"`
+	AAGUID       []byte             `bson:"aaguid,omitempty"`
+	Transports   []string           `bson:"transports,omitempty"`
+	Name         string             `bson:"name"` // User-chosen label, e.g. "iPhone"
+	CreatedAt    time.Time          `bson:"created_at"`
+	LastUsedAt   *time.Time         `bson:"last_used_at,omitempty"`
+}
+
+// WebAuthnChallenge is a single-use challenge issued for a registration or login ceremony
+type WebAuthnChallenge struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Challenge string             `bson:"challenge"` // Base64url-encoded challenge bytes
+	Ceremony  string             `bson:"ceremony"`
+	Email     string             `bson:"email,omitempty"` // Registering user; empty for discoverable logins
+	ExpiresAt time.Time          `bson:"expires_at"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// WebAuthnRepo handles passkey credentials and ceremony challenges
+type WebAuthnRepo struct {
+	credentials *mongo.Collection
+	challenges  *mongo.Collection
+}
+
+// NewWebAuthnRepo creates a new WebAuthn repository
+func NewWebAuthnRepo(db *mongo.Database) *WebAuthnRepo {
+	repo := &WebAuthnRepo{
+		credentials: db.Collection("webauthn_credentials"),
+		challenges:  db.Collection("webauthn_challenges"),
+	}
+
+	if err := repo.createIndexes(context.Background()); err != nil {
+		fmt.Printf("Failed to create webauthn indexes: %v\n", err)
+	}
+
+	return repo
+}
+
+func (r *WebAuthnRepo) createIndexes(ctx context.Context) error {
+	_, err := r.credentials.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "credential_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "player_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.challenges.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "challenge", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0), // TTL index
+		},
+	})
+	return err
+}
+
+// CreateChallenge stores a challenge valid for ttl
+func (r *WebAuthnRepo) CreateChallenge(ctx context.Context, challenge, ceremony, email string, ttl time.Duration) (*WebAuthnChallenge, error) {
+	now := time.Now()
+	c := &WebAuthnChallenge{
+		ID:        primitive.NewObjectID(),
+		Challenge: challenge,
+		Ceremony:  ceremony,
+		Email:     email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	_, err := r.challenges.InsertOne(ctx, c)
+	return c, err
+}
+
+// ConsumeChallenge removes and returns an unexpired challenge of the given ceremony.
+// Fails with mongo.ErrNoDocuments when it is unknown, expired or already used.
+func (r *WebAuthnRepo) ConsumeChallenge(ctx context.Context, challenge, ceremony string) (*WebAuthnChallenge, error) {
+	var c WebAuthnChallenge
+	err := r.challenges.FindOneAndDelete(ctx, bson.M{
+		"challenge":  challenge,
+		"ceremony":   ceremony,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CreateCredential stores a newly registered passkey
+func (r *WebAuthnRepo) CreateCredential(ctx context.Context, credential *WebAuthnCredential) (*WebAuthnCredential, error) {
+	credential.ID = primitive.NewObjectID()
+	credential.CreatedAt = time.Now()
+	if _, err := r.credentials.InsertOne(ctx, credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// FindByCredentialID retrieves a passkey by its base64url credential ID
+func (r *WebAuthnRepo) FindByCredentialID(ctx context.Context, credentialID string) (*WebAuthnCredential, error) {
+	var credential WebAuthnCredential
+	if err := r.credentials.FindOne(ctx, bson.M{"credential_id": credentialID}).Decode(&credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// ListByPlayer returns a player's passkeys, oldest first
+func (r *WebAuthnRepo) ListByPlayer(ctx context.Context, playerID primitive.ObjectID) ([]*WebAuthnCredential, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.credentials.Find(ctx, bson.M{"player_id": playerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	var credentials []*WebAuthnCredential
+	if err := cursor.All(ctx, &credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// UpdateSignCount records a successful login with the authenticator's new counter.
+// The update only applies while the stored counter is unchanged, so concurrent
+// replays of the same assertion cannot both succeed.
+func (r *WebAuthnRepo) UpdateSignCount(ctx context.Context, id primitive.ObjectID, previous, signCount uint32) error {
+	result, err := r.credentials.UpdateOne(ctx,
+		bson.M{"_id": id, "sign_count": previous},
+		bson.M{"$set": bson.M{"sign_count": signCount, "last_used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete removes a player's passkey. Fails with mongo.ErrNoDocuments when the
+// passkey does not exist or belongs to another player.
+func (r *WebAuthnRepo) Delete(ctx context.Context, id string, playerID primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.credentials.DeleteOne(ctx, bson.M{"_id": objID, "player_id": playerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	runtime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
 	"github.com/goencoder/klubbspel/backend/internal/audit"
@@ -24,10 +26,12 @@ import (
 	"github.com/goencoder/klubbspel/backend/internal/middleware"
 	"github.com/goencoder/klubbspel/backend/internal/mongo"
 	"github.com/goencoder/klubbspel/backend/internal/monitoring"
+	"github.com/goencoder/klubbspel/backend/internal/oidc"
 	"github.com/goencoder/klubbspel/backend/internal/repo"
 	"github.com/goencoder/klubbspel/backend/internal/service"
 	"github.com/goencoder/klubbspel/backend/internal/validate"
 	"github.com/goencoder/klubbspel/backend/internal/validation"
+	"github.com/goencoder/klubbspel/backend/internal/webauthn"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
 )
 
@@ -74,10 +78,27 @@ func Bootstrap(ctx context.Context, cfg config.Config, mc *mongo.Client) (*GRPCS
 	seriesRepo := repo.NewSeriesRepo(mc.DB)
 	matchRepo := repo.NewMatchRepo(mc.DB, playerRepo)
 	leaderboardRepo := repo.NewLeaderboardRepo(mc.DB)
-	tokenRepo := repo.NewTokenRepo(mc.DB)
+	seriesPlayerRepo := repo.NewSeriesPlayerRepo(mc.DB)
+	seriesEntryRepo := repo.NewSeriesEntryRepo(mc.DB)
+	matchRuleRepo := repo.NewMatchRuleRepo(mc.DB)
+	matchFlagRepo := repo.NewMatchFlagRepo(mc.DB)
+	invitationRepo := repo.NewClubInvitationRepo(mc.DB)
+	joinRequestRepo := repo.NewClubJoinRequestRepo(mc.DB)
+	tokenRepo := repo.NewTokenRepo(mc.DB, cfg.TokenPepper, cfg.AcceptLegacyTokens)
+	if cfg.AcceptLegacyTokens {
+		log.Warn().Msg("ACCEPT_LEGACY_TOKENS is set; plaintext tokens stay valid until it is unset after the hash-tokens migration")
+	}
+	webAuthnRepo := repo.NewWebAuthnRepo(mc.DB)
+	oidcRepo := repo.NewOIDCRepo(mc.DB)
+	statsRepo := repo.NewStatsRepo(mc.DB)
+	playerStatsRepo := repo.NewPlayerStatsRepo(mc.DB)
+	seriesSummaryRepo := repo.NewSeriesSummaryRepo(mc.DB)
+	leaderboardSnapshotRepo := repo.NewLeaderboardSnapshotRepo(mc.DB)
+	emailOutboxRepo := repo.NewEmailOutboxRepo(mc.DB)
+	notificationRepo := repo.NewNotificationRepo(mc.DB)
 
 	// Email service - use configuration from environment
-	var emailSvc email.Service
+	var emailConfig email.EmailConfig
 
 	// Convert SMTP port string to int
 	smtpPort, err := strconv.Atoi(cfg.SMTPPort)
@@ -85,67 +106,112 @@ func Bootstrap(ctx context.Context, cfg config.Config, mc *mongo.Client) (*GRPCS
 		panic(fmt.Sprintf("Invalid SMTP port: %v", err))
 	}
 
-	if cfg.EmailProvider == "mailhog" || (cfg.Environment == "development" && cfg.EmailProvider == "") {
-		// MailHog/SMTP configuration for development
-		emailConfig := email.EmailConfig{
-			Provider:     email.ProviderMailHog,
-			FromName:     cfg.EmailFromName,
-			FromEmail:    cfg.EmailFromAddress,
-			BaseURL:      cfg.EmailBaseURL,
-			SMTPHost:     cfg.SMTPHost,
-			SMTPPort:     smtpPort,
-			SMTPUsername: cfg.SMTPUsername,
-			SMTPPassword: cfg.SMTPPassword,
-			SMTPTLSMode:  cfg.SMTPTLSMode,
+	if cfg.EmailProvider == "smtp" {
+		// Own mail server for self-hosted production deployments
+		emailConfig = email.EmailConfig{
+			Provider:          email.ProviderSMTP,
+			FromName:          cfg.EmailFromName,
+			FromEmail:         cfg.EmailFromAddress,
+			BaseURL:           cfg.EmailBaseURL,
+			DefaultLocale:     cfg.DefaultLocale,
+			SMTPHost:          cfg.SMTPHost,
+			SMTPPort:          smtpPort,
+			SMTPUsername:      cfg.SMTPUsername,
+			SMTPPassword:      cfg.SMTPPassword,
+			SMTPTLSMode:       cfg.SMTPTLSMode,
+			SMTPBounceAddress: cfg.SMTPBounceAddress,
+			SMTPHeloName:      cfg.SMTPHeloName,
+			DKIMDomain:        cfg.DKIMDomain,
+			DKIMSelector:      cfg.DKIMSelector,
+			DKIMPrivateKey:    dkimPrivateKey(cfg),
 		}
-		emailAdapter, err := email.NewEmailAdapter(emailConfig)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to create MailHog email adapter: %v", err))
+	} else if cfg.EmailProvider == "mailhog" || (cfg.Environment == "development" && cfg.EmailProvider == "") {
+		// MailHog/SMTP configuration for development
+		emailConfig = email.EmailConfig{
+			Provider:      email.ProviderMailHog,
+			FromName:      cfg.EmailFromName,
+			FromEmail:     cfg.EmailFromAddress,
+			BaseURL:       cfg.EmailBaseURL,
+			DefaultLocale: cfg.DefaultLocale,
+			SMTPHost:      cfg.SMTPHost,
+			SMTPPort:      smtpPort,
+			SMTPUsername:  cfg.SMTPUsername,
+			SMTPPassword:  cfg.SMTPPassword,
+			SMTPTLSMode:   cfg.SMTPTLSMode,
 		}
-		emailSvc = emailAdapter
 	} else if cfg.EmailProvider == "sendgrid" || cfg.Environment == "production" {
 		// SendGrid configuration for production
-		emailConfig := email.EmailConfig{
+		emailConfig = email.EmailConfig{
 			Provider:       email.ProviderSendGrid,
 			FromName:       cfg.EmailFromName,
 			FromEmail:      cfg.EmailFromAddress,
 			BaseURL:        cfg.EmailBaseURL,
+			DefaultLocale:  cfg.DefaultLocale,
 			SendGridAPIKey: cfg.SendGridAPIKey,
 		}
-		emailAdapter, err := email.NewEmailAdapter(emailConfig)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to create SendGrid email adapter: %v", err))
-		}
-		emailSvc = emailAdapter
 	} else {
 		// Mock service for testing
-		emailConfig := email.EmailConfig{
-			Provider:  email.ProviderMock,
-			FromName:  cfg.EmailFromName,
-			FromEmail: cfg.EmailFromAddress,
-			BaseURL:   cfg.EmailBaseURL,
+		emailConfig = email.EmailConfig{
+			Provider:      email.ProviderMock,
+			FromName:      cfg.EmailFromName,
+			FromEmail:     cfg.EmailFromAddress,
+			BaseURL:       cfg.EmailBaseURL,
+			DefaultLocale: cfg.DefaultLocale,
 		}
-		emailAdapter, err := email.NewEmailAdapter(emailConfig)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to create mock email adapter: %v", err))
-		}
-		emailSvc = emailAdapter
+	}
+
+	emailConfig.APIBaseURL = cfg.PublicAPIURL
+
+	// RPCs only enqueue; the outbox worker delivers through the provider and retries failures
+	emailTransport, err := email.NewTransport(emailConfig)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create %s email transport: %v", emailConfig.Provider, err))
+	}
+	emailSvc, err := email.NewEmailAdapterWithTransport(emailConfig, &email.OutboxTransport{Store: emailOutboxRepo})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create email adapter: %v", err))
+	}
+	go email.NewOutboxWorker(emailOutboxRepo, emailTransport).Run(ctx)
+
+	// CEL rules engine for club/series match rules
+	ruleValidator, err := validation.NewCELValidator()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create rule validator: %v", err))
 	}
 
 	// Services with security enhancements
 	clubSvc := &service.ClubService{Clubs: clubRepo, Players: playerRepo, Series: seriesRepo}
-	playerSvc := &service.PlayerService{Players: playerRepo}
-	seriesSvc := &service.SeriesService{Series: seriesRepo, Matches: matchRepo, Players: playerRepo}
-	matchSvc := &service.MatchService{Matches: matchRepo, Players: playerRepo, Series: seriesRepo, Leaderboard: leaderboardRepo}
-	leaderboardSvc := &service.LeaderboardService{Leaderboard: leaderboardRepo, Players: playerRepo}
+	playerSvc := &service.PlayerService{Players: playerRepo, Stats: playerStatsRepo}
+	seriesSvc := &service.SeriesService{Series: seriesRepo, Matches: matchRepo, Players: playerRepo, Leaderboard: leaderboardRepo, SeriesPlayers: seriesPlayerRepo, Entries: seriesEntryRepo}
+	matchSvc := &service.MatchService{Matches: matchRepo, Players: playerRepo, Series: seriesRepo, Leaderboard: leaderboardRepo, SeriesPlayers: seriesPlayerRepo, Entries: seriesEntryRepo, Rules: matchRuleRepo, Validator: ruleValidator}
+	matchSvc.Abuse = &service.AbuseDetector{Matches: matchRepo, Flags: matchFlagRepo, Clubs: clubRepo, Audit: auditLogger}
+	notifier := &service.Notifier{Players: playerRepo, Series: seriesRepo, Leaderboard: leaderboardRepo, Notifications: notificationRepo, EmailSvc: emailSvc, BaseURL: cfg.EmailBaseURL, Secret: notificationSecret(cfg)}
+	matchSvc.Notifier = notifier
+	matchSvc.Snapshots = leaderboardSnapshotRepo
+	matchSvc.Summaries = seriesSummaryRepo
+	seriesSvc.Notifier = notifier
+	seriesSvc.Standings = matchSvc
+	go notifier.Run(ctx)
+	seriesSvc.Summarizer = &service.SeriesSummarizer{Series: seriesRepo, Matches: matchRepo, Leaderboard: leaderboardRepo, SeriesPlayers: seriesPlayerRepo, Summaries: seriesSummaryRepo, Notifier: notifier}
+	go seriesSvc.Summarizer.Run(ctx)
+	leaderboardSvc := &service.LeaderboardService{Leaderboard: leaderboardRepo, Players: playerRepo, Series: seriesRepo}
 	// Wire MatchService for fallback recalculation
 	leaderboardSvc.Matches = matchSvc
-	authSvc := &service.AuthService{TokenRepo: tokenRepo, PlayerRepo: playerRepo, EmailSvc: emailSvc}
-	clubMembershipSvc := &service.ClubMembershipService{PlayerRepo: playerRepo, ClubRepo: clubRepo, TokenRepo: tokenRepo, EmailSvc: emailSvc}
+	authSvc := &service.AuthService{TokenRepo: tokenRepo, PlayerRepo: playerRepo, EmailSvc: emailSvc, WebAuthn: webAuthnRepo, RelyingParty: relyingParty(cfg), OIDC: oidcRepo, Providers: oidcProviders(cfg), Audit: auditLogger}
+	authSvc.AccessTokenTTL = mustParseDuration("ACCESS_TOKEN_TTL", cfg.AccessTokenTTL)
+	authSvc.RefreshTokenTTL = mustParseDuration("REFRESH_TOKEN_TTL", cfg.RefreshTokenTTL)
+	clubMembershipSvc := &service.ClubMembershipService{PlayerRepo: playerRepo, ClubRepo: clubRepo, TokenRepo: tokenRepo, EmailSvc: emailSvc, Invitations: invitationRepo, JoinRequests: joinRequestRepo, Audit: auditLogger}
+	matchRuleSvc := &service.MatchRuleService{Rules: matchRuleRepo, Series: seriesRepo, Validator: ruleValidator}
+	matchReviewSvc := &service.MatchReviewService{Flags: matchFlagRepo, Matches: matchRepo, Players: playerRepo, Clubs: clubRepo, MatchSvc: matchSvc, Audit: auditLogger}
+	adminSvc := &service.AdminService{Players: playerRepo, Tokens: tokenRepo, Stats: statsRepo, Outbox: emailOutboxRepo, Audit: auditLogger}
+	notificationSvc := &service.NotificationService{Players: playerRepo, Notifier: notifier}
 
 	// Authentication interceptor with audit logging
 	authInterceptor := auth.NewAuthInterceptor(tokenRepo, playerRepo)
 
+	// Localizes errors from all interceptors and services
+	errorLocalizer := &service.ErrorLocalizer{Players: playerRepo, DefaultLocale: cfg.DefaultLocale}
+
 	// gRPC Server with security interceptors
 	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
@@ -154,6 +220,7 @@ func Bootstrap(ctx context.Context, cfg config.Config, mc *mongo.Client) (*GRPCS
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			errorLocalizer.UnaryInterceptor,
 			validate.ValidationInterceptor,
 			rateLimiter.UnaryInterceptor(),
 			authInterceptor.UnaryInterceptor,
@@ -167,15 +234,21 @@ func Bootstrap(ctx context.Context, cfg config.Config, mc *mongo.Client) (*GRPCS
 	pb.RegisterLeaderboardServiceServer(grpcServer, leaderboardSvc)
 	pb.RegisterAuthServiceServer(grpcServer, authSvc)
 	pb.RegisterClubMembershipServiceServer(grpcServer, clubMembershipSvc)
+	pb.RegisterMatchRuleServiceServer(grpcServer, matchRuleSvc)
+	pb.RegisterMatchReviewServiceServer(grpcServer, matchReviewSvc)
+	pb.RegisterAdminServiceServer(grpcServer, adminSvc)
+	pb.RegisterNotificationServiceServer(grpcServer, notificationSvc)
 
 	gs := &GRPCServer{s: grpcServer, lis: lis}
 
 	// gRPC Gateway with error handling and header matching
 	mux := runtime.NewServeMux(
-		runtime.WithErrorHandler(LocalizedErrorHandler()),
+		runtime.WithErrorHandler(LocalizedErrorHandler(cfg.DefaultLocale)),
 		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
 			return key, true
 		}),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithForwardResponseOption(oidcBindingCookie(cfg.Environment != "development")),
 	)
 
 	// Create gateway with allowCORS middleware applied to the mux
@@ -286,6 +359,83 @@ func Bootstrap(ctx context.Context, cfg config.Config, mc *mongo.Client) (*GRPCS
 	return gs, gateway, httpSrv
 }
 
+// relyingParty builds the passkey relying party, defaulting to the frontend base URL
+func relyingParty(cfg config.Config) *webauthn.RelyingParty {
+	rp := &webauthn.RelyingParty{ID: cfg.WebAuthnRPID, Name: cfg.WebAuthnRPName}
+
+	for _, origin := range strings.Split(cfg.WebAuthnOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			rp.Origins = append(rp.Origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	if len(rp.Origins) == 0 {
+		rp.Origins = []string{strings.TrimSuffix(cfg.EmailBaseURL, "/")}
+	}
+
+	if rp.ID == "" {
+		if base, err := url.Parse(rp.Origins[0]); err == nil {
+			rp.ID = base.Hostname()
+		}
+	}
+	return rp
+}
+
+// dkimPrivateKey returns the configured DKIM key, reading it from file if needed
+func dkimPrivateKey(cfg config.Config) string {
+	if cfg.DKIMPrivateKey != "" || cfg.DKIMPrivateKeyFile == "" {
+		return cfg.DKIMPrivateKey
+	}
+	key, err := os.ReadFile(cfg.DKIMPrivateKeyFile)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read DKIM private key: %v", err))
+	}
+	return string(key)
+}
+
+// notificationSecret returns the key for signing unsubscribe links, refusing
+// to start without one outside development
+func notificationSecret(cfg config.Config) []byte {
+	if cfg.NotificationSecret != "" {
+		return []byte(cfg.NotificationSecret)
+	}
+	if cfg.Environment != "development" {
+		panic("NOTIFICATION_SECRET must be set")
+	}
+	log.Warn().Msg("NOTIFICATION_SECRET is unset; using a development key, unsubscribe links can be forged")
+	return []byte("klubbspel-development-notification-secret")
+}
+
+// mustParseDuration parses a duration setting, refusing to start on a bad value
+func mustParseDuration(name, value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		panic(fmt.Sprintf("Invalid %s: %q", name, value))
+	}
+	return d
+}
+
+// oidcProviders builds the external identity providers from configuration
+func oidcProviders(cfg config.Config) []*oidc.Provider {
+	redirectURL := cfg.OIDCRedirectURL
+	if redirectURL == "" {
+		redirectURL = strings.TrimSuffix(cfg.EmailBaseURL, "/") + "/auth/oidc/callback"
+	}
+
+	var providers []*oidc.Provider
+	for _, p := range cfg.OIDCProviders {
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			ID:           p.ID,
+			DisplayName:  p.DisplayName,
+			IssuerURL:    p.IssuerURL,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       p.Scopes,
+		}))
+	}
+	return providers
+}
+
 // createAuditInterceptor creates a gRPC interceptor for audit logging
 func createAuditInterceptor(auditLogger *audit.AuditLogger) grpc.UnaryServerInterceptor {
 	return func(
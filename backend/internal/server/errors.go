@@ -2,24 +2,46 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	runtime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+	"github.com/goencoder/klubbspel/backend/internal/service"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
 )
 
-// Localized error handler for grpc-gateway
-func LocalizedErrorHandler() runtime.ErrorHandlerFunc {
+// LocalizedErrorHandler writes errors as an Error frame. Errors from the gRPC
+// services are already localized; errors raised by the gateway itself are
+// localized from the request's Accept-Language or defaultLocale.
+func LocalizedErrorHandler(defaultLocale string) runtime.ErrorHandlerFunc {
 	return func(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
-		st, _ := status.FromError(err)
-		code := httpStatus(st.Code())
-		w.WriteHeader(code)
-		_ = m.NewEncoder(w).Encode(map[string]any{
-			"code":    st.Message(), // we'll encode stable codes in Status message
-			"message": st.Message(), // replace with i18n lookup if you prefer
-		})
+		var httpErr *runtime.HTTPStatusError
+		httpStatus := 0
+		if errors.As(err, &httpErr) {
+			httpStatus = httpErr.HTTPStatus
+			err = httpErr.Err
+		}
+
+		locale := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		if locale == "" {
+			locale = defaultLocale
+		}
+		st := service.LocalizeError(err, locale)
+		if httpStatus == 0 {
+			httpStatus = runtime.HTTPStatusFromCode(st.Code())
+		}
+
+		frame := &pb.Error{Code: st.Message(), Message: st.Message()}
+		for _, detail := range st.Details() {
+			if e, ok := detail.(*pb.Error); ok {
+				frame = e
+			}
+		}
+
+		w.Header().Set("Content-Type", m.ContentType(frame))
+		w.WriteHeader(httpStatus)
+		_ = m.NewEncoder(w).Encode(frame)
 	}
 }
-
-func httpStatus(c codes.Code) int { return runtime.HTTPStatusFromCode(c) }
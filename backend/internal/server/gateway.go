@@ -11,6 +11,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/goencoder/klubbspel/backend/internal/service"
 
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
 )
@@ -65,7 +68,58 @@ func (g *Gateway) RegisterHandlers(ctx context.Context, grpcEndpoint string) err
 	if err := pb.RegisterClubMembershipServiceHandlerFromEndpoint(ctx, g.mux, grpcEndpoint, opts); err != nil {
 		return fmt.Errorf("failed to register ClubMembershipService: %w", err)
 	}
+	if err := pb.RegisterMatchRuleServiceHandlerFromEndpoint(ctx, g.mux, grpcEndpoint, opts); err != nil {
+		return fmt.Errorf("failed to register MatchRuleService: %w", err)
+	}
+	if err := pb.RegisterMatchReviewServiceHandlerFromEndpoint(ctx, g.mux, grpcEndpoint, opts); err != nil {
+		return fmt.Errorf("failed to register MatchReviewService: %w", err)
+	}
+	if err := pb.RegisterAdminServiceHandlerFromEndpoint(ctx, g.mux, grpcEndpoint, opts); err != nil {
+		return fmt.Errorf("failed to register AdminService: %w", err)
+	}
+	if err := pb.RegisterNotificationServiceHandlerFromEndpoint(ctx, g.mux, grpcEndpoint, opts); err != nil {
+		return fmt.Errorf("failed to register NotificationService: %w", err)
+	}
 
 	log.Info().Msg("gRPC Gateway handlers registered successfully")
 	return nil
 }
+
+// outgoingHeaderMatcher forwards response metadata as Grpc-Metadata- headers
+// like the gateway's default, except the OIDC binding which only goes out as
+// an HttpOnly cookie
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == service.OIDCBindingHeader {
+		return "", false
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// oidcBindingCookie sets or clears the OIDC binding cookie when a handler
+// returned the binding in its response metadata
+func oidcBindingCookie(secure bool) func(context.Context, http.ResponseWriter, proto.Message) error {
+	return func(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+		md, ok := runtime.ServerMetadataFromContext(ctx)
+		if !ok {
+			return nil
+		}
+		values := md.HeaderMD.Get(service.OIDCBindingHeader)
+		if len(values) == 0 {
+			return nil
+		}
+
+		cookie := &http.Cookie{
+			Name:     service.OIDCBindingCookie,
+			Value:    values[0],
+			Path:     "/v1/auth/oidc",
+			HttpOnly: true,
+			Secure:   secure,
+			SameSite: http.SameSiteLaxMode,
+		}
+		if cookie.Value == "" {
+			cookie.MaxAge = -1
+		}
+		http.SetCookie(w, cookie)
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goencoder/klubbspel/backend/internal/audit"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// defaultAbuseThresholds apply to open series and to clubs without own settings
+var defaultAbuseThresholds = repo.AbuseThresholds{
+	PairingWindowHours:  72,
+	MaxPairingsInWindow: 3,
+	LateReportHours:     7 * 24,
+	SelfWinMinReports:   5,
+	SelfWinRatio:        0.9,
+}
+
+// AbuseDetector flags suspicious match reports for admin review
+type AbuseDetector struct {
+	Matches *repo.MatchRepo
+	Flags   *repo.MatchFlagRepo
+	Clubs   *repo.ClubRepo
+	Audit   *audit.AuditLogger
+}
+
+// Inspect checks a newly reported match and queues it for review when it looks suspicious.
+// Returns nil when nothing was flagged.
+func (d *AbuseDetector) Inspect(ctx context.Context, series *repo.Series, match *repo.Match) (*repo.MatchFlag, error) {
+	thresholds := d.thresholdsForClub(ctx, series.ClubID)
+
+	window := time.Duration(thresholds.PairingWindowHours) * time.Hour
+	pairings, err := d.Matches.CountBetweenPlayers(ctx, match.SeriesID, match.PlayerAID, match.PlayerBID, match.PlayedAt.Add(-window), match.PlayedAt, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var reported, won int64
+	if reporterWon(match) {
+		reported, won, err = d.Matches.CountReportedWins(ctx, match.SeriesID, match.ReportedBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reasons := detectSuspiciousMatch(match, thresholds, pairings, reported, won)
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	flag, err := d.Flags.Create(ctx, &repo.MatchFlag{
+		MatchID:    match.ID.Hex(),
+		SeriesID:   match.SeriesID,
+		ClubID:     series.ClubID,
+		ReportedBy: match.ReportedBy,
+		Reasons:    reasons,
+		Status:     repo.FlagStatusPending,
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Audit != nil {
+		d.Audit.LogEvent(ctx, audit.AuditEvent{
+			Type:       audit.EventSecuritySuspiciousActivity,
+			Action:     "match.flagged",
+			ActorID:    match.ReportedBy,
+			ActorType:  "player",
+			TargetID:   match.ID.Hex(),
+			TargetType: "match",
+			ClubID:     series.ClubID,
+			Result:     "FLAGGED",
+			Message:    fmt.Sprintf("Match flagged for review: %s", strings.Join(reasons, ", ")),
+			Details: map[string]interface{}{
+				"series_id": match.SeriesID,
+				"flag_id":   flag.ID.Hex(),
+				"reasons":   reasons,
+			},
+		})
+	}
+
+	return flag, nil
+}
+
+// thresholdsForClub returns the club's thresholds with defaults for unset values
+func (d *AbuseDetector) thresholdsForClub(ctx context.Context, clubID string) repo.AbuseThresholds {
+	if clubID == "" || d.Clubs == nil {
+		return defaultAbuseThresholds
+	}
+
+	club, err := d.Clubs.FindByID(ctx, clubID)
+	if err != nil {
+		return defaultAbuseThresholds
+	}
+	return effectiveAbuseThresholds(club.AbuseThresholds)
+}
+
+func effectiveAbuseThresholds(custom *repo.AbuseThresholds) repo.AbuseThresholds {
+	thresholds := defaultAbuseThresholds
+	if custom == nil {
+		return thresholds
+	}
+
+	if custom.PairingWindowHours > 0 {
+		thresholds.PairingWindowHours = custom.PairingWindowHours
+	}
+	if custom.MaxPairingsInWindow > 0 {
+		thresholds.MaxPairingsInWindow = custom.MaxPairingsInWindow
+	}
+	if custom.LateReportHours > 0 {
+		thresholds.LateReportHours = custom.LateReportHours
+	}
+	if custom.SelfWinMinReports > 0 {
+		thresholds.SelfWinMinReports = custom.SelfWinMinReports
+	}
+	if custom.SelfWinRatio > 0 {
+		thresholds.SelfWinRatio = custom.SelfWinRatio
+	}
+	return thresholds
+}
+
+// detectSuspiciousMatch returns the flag reasons for a match. pairings counts matches
+// between the two players within the pairing window (including this one); reported and
+// won are the reporter's own-match reports and own wins in the series.
+func detectSuspiciousMatch(match *repo.Match, thresholds repo.AbuseThresholds, pairings, reported, won int64) []string {
+	var reasons []string
+
+	if pairings > int64(thresholds.MaxPairingsInWindow) {
+		reasons = append(reasons, repo.FlagReasonRepeatedPairing)
+	}
+
+	if !match.ReportedAt.IsZero() && match.ReportedAt.Sub(match.PlayedAt) > time.Duration(thresholds.LateReportHours)*time.Hour {
+		reasons = append(reasons, repo.FlagReasonLateReport)
+	}
+
+	if reporterWon(match) && reported >= int64(thresholds.SelfWinMinReports) &&
+		float64(won)/float64(reported) >= thresholds.SelfWinRatio {
+		reasons = append(reasons, repo.FlagReasonSelfWinReporter)
+	}
+
+	return reasons
+}
+
+// reporterWon reports whether the match was reported by its winner
+func reporterWon(match *repo.Match) bool {
+	switch match.ReportedBy {
+	case "":
+		return false
+	case match.PlayerAID:
+		return match.ScoreA > match.ScoreB
+	case match.PlayerBID:
+		return match.ScoreB > match.ScoreA
+	}
+	return false
+}
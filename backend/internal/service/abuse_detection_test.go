@@ -0,0 +1,56 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+func TestDetectSuspiciousMatch(t *testing.T) {
+	playedAt := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	match := &repo.Match{
+		PlayerAID:  "a",
+		PlayerBID:  "b",
+		ScoreA:     3,
+		ScoreB:     0,
+		PlayedAt:   playedAt,
+		ReportedBy: "a",
+		ReportedAt: playedAt.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name       string
+		reportedAt time.Time
+		pairings   int64
+		reported   int64
+		won        int64
+		expected   []string
+	}{
+		{"clean report", playedAt.Add(time.Hour), 1, 4, 4, nil},
+		{"repeated pairing", playedAt.Add(time.Hour), 4, 0, 0, []string{repo.FlagReasonRepeatedPairing}},
+		{"late report", playedAt.Add(8 * 24 * time.Hour), 1, 0, 0, []string{repo.FlagReasonLateReport}},
+		{"self-win reporter", playedAt.Add(time.Hour), 1, 10, 9, []string{repo.FlagReasonSelfWinReporter}},
+		{"self-win below ratio", playedAt.Add(time.Hour), 1, 10, 8, nil},
+	}
+
+	for _, test := range tests {
+		m := *match
+		m.ReportedAt = test.reportedAt
+		reasons := detectSuspiciousMatch(&m, defaultAbuseThresholds, test.pairings, test.reported, test.won)
+		if !reflect.DeepEqual(reasons, test.expected) {
+			t.Errorf("%s: reasons = %v, expected %v", test.name, reasons, test.expected)
+		}
+	}
+}
+
+func TestEffectiveAbuseThresholdsKeepsDefaultsForUnsetValues(t *testing.T) {
+	thresholds := effectiveAbuseThresholds(&repo.AbuseThresholds{MaxPairingsInWindow: 5})
+	if thresholds.MaxPairingsInWindow != 5 {
+		t.Errorf("MaxPairingsInWindow = %d, expected 5", thresholds.MaxPairingsInWindow)
+	}
+	if thresholds.PairingWindowHours != defaultAbuseThresholds.PairingWindowHours {
+		t.Errorf("PairingWindowHours = %d, expected default", thresholds.PairingWindowHours)
+	}
+}
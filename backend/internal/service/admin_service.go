@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/audit"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// impersonationTTL is how long a support session acting as another user lasts
+const impersonationTTL = 30 * time.Minute
+
+// AdminService provides platform owner tools. Every call is written to the audit log.
+type AdminService struct {
+	pb.UnimplementedAdminServiceServer
+	Players *repo.PlayerRepo
+	Tokens  *repo.TokenRepo
+	Stats   *repo.StatsRepo
+	Outbox  *repo.EmailOutboxRepo
+	Audit   *audit.AuditLogger
+}
+
+var emailDeliveryStatusToProto = map[string]pb.EmailDeliveryStatus{
+	repo.OutboxStatusPending: pb.EmailDeliveryStatus_EMAIL_DELIVERY_STATUS_PENDING,
+	repo.OutboxStatusSending: pb.EmailDeliveryStatus_EMAIL_DELIVERY_STATUS_SENDING,
+	repo.OutboxStatusSent:    pb.EmailDeliveryStatus_EMAIL_DELIVERY_STATUS_SENT,
+	repo.OutboxStatusDead:    pb.EmailDeliveryStatus_EMAIL_DELIVERY_STATUS_DEAD,
+}
+
+// GetSystemStats returns platform-wide counters
+func (s *AdminService) GetSystemStats(ctx context.Context, req *pb.GetSystemStatsRequest) (resp *pb.GetSystemStatsResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { s.audit(ctx, owner, audit.EventAdminStatsRead, "get_system_stats", "", err, nil) }()
+
+	stats, err := s.Stats.SystemStats(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LOAD_STATS")
+	}
+
+	return &pb.GetSystemStatsResponse{Stats: &pb.SystemStats{
+		Clubs:              stats.Clubs,
+		Players:            stats.Players,
+		Accounts:           stats.Accounts,
+		Series:             stats.Series,
+		Matches:            stats.Matches,
+		WeeklyActiveUsers:  stats.ActiveUsers7d,
+		MonthlyActiveUsers: stats.ActiveUsers30d,
+		ActiveSessions:     stats.ActiveSessions,
+		DisabledAccounts:   stats.DisabledAccounts,
+	}}, nil
+}
+
+// SearchUsers finds accounts by email or display name
+func (s *AdminService) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (resp *pb.SearchUsersResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{"query": req.Query}
+	defer func() { s.audit(ctx, owner, audit.EventAdminUserSearch, "search_users", "", err, details) }()
+
+	players, nextPageToken, err := s.Players.SearchAccounts(ctx, req.Query, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_SEARCH_USERS")
+	}
+	details["results"] = len(players)
+
+	resp = &pb.SearchUsersResponse{NextPageToken: nextPageToken}
+	for _, player := range players {
+		resp.Users = append(resp.Users, adminUserToProto(player))
+	}
+	return resp, nil
+}
+
+// ForceLogout revokes every session of a user
+func (s *AdminService) ForceLogout(ctx context.Context, req *pb.ForceLogoutRequest) (resp *pb.ForceLogoutResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{}
+	defer func() { s.audit(ctx, owner, audit.EventAdminUserLogout, "force_logout", req.PlayerId, err, details) }()
+
+	target, err := s.findAccount(ctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.Tokens.RevokeAllUserTokens(ctx, target.Email)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_FORCE_LOGOUT")
+	}
+	details["revoked_sessions"] = revoked
+
+	return &pb.ForceLogoutResponse{RevokedSessions: revoked}, nil
+}
+
+// TransferPlatformOwnership makes another account platform owner and removes
+// ownership from the caller
+func (s *AdminService) TransferPlatformOwnership(ctx context.Context, req *pb.TransferPlatformOwnershipRequest) (resp *pb.TransferPlatformOwnershipResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.audit(ctx, owner, audit.EventAdminOwnershipTransfer, "transfer_platform_ownership", req.NewOwnerId, err, nil)
+	}()
+
+	target, err := s.findAccount(ctx, req.NewOwnerId)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == owner.ID {
+		return nil, status.Error(codes.InvalidArgument, "CANNOT_TRANSFER_TO_SELF")
+	}
+	if target.Disabled {
+		return nil, status.Error(codes.FailedPrecondition, "ACCOUNT_DISABLED")
+	}
+
+	// Grant first so the platform never ends up without an owner
+	if err := s.Players.SetPlatformOwner(ctx, target.Email, true); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_TRANSFER_OWNERSHIP")
+	}
+	if err := s.Players.SetPlatformOwner(ctx, owner.Email, false); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_TRANSFER_OWNERSHIP")
+	}
+
+	target.IsPlatformOwner = true
+	return &pb.TransferPlatformOwnershipResponse{NewOwner: adminUserToProto(target)}, nil
+}
+
+// DisableUser blocks or unblocks an account. Disabling also ends the user's
+// sessions and revokes every API key they created, including club keys, which
+// the club's admins have to issue again.
+func (s *AdminService) DisableUser(ctx context.Context, req *pb.DisableUserRequest) (resp *pb.DisableUserResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{"disabled": req.Disabled, "reason": req.Reason}
+	defer func() { s.audit(ctx, owner, audit.EventAdminUserDisable, "disable_user", req.PlayerId, err, details) }()
+
+	target, err := s.findAccount(ctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Disabled {
+		if target.ID == owner.ID {
+			return nil, status.Error(codes.InvalidArgument, "CANNOT_DISABLE_SELF")
+		}
+		if target.IsPlatformOwner {
+			return nil, status.Error(codes.FailedPrecondition, "CANNOT_DISABLE_PLATFORM_OWNER")
+		}
+	}
+
+	updated, err := s.Players.SetDisabled(ctx, target.ID, req.Disabled, req.Reason)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_UPDATE_USER")
+	}
+
+	if req.Disabled {
+		revoked, err := s.Tokens.RevokeAllUserTokens(ctx, target.Email)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "FAILED_TO_FORCE_LOGOUT")
+		}
+		details["revoked_sessions"] = revoked
+		revokedKeys, err := s.Tokens.RevokeAllUserAPIKeys(ctx, target.Email)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "FAILED_TO_REVOKE_API_KEYS")
+		}
+		details["revoked_api_keys"] = revokedKeys
+	}
+
+	return &pb.DisableUserResponse{User: adminUserToProto(updated)}, nil
+}
+
+// ImpersonateUser issues a short-lived read-only token acting as another user
+func (s *AdminService) ImpersonateUser(ctx context.Context, req *pb.ImpersonateUserRequest) (resp *pb.ImpersonateUserResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	details := map[string]interface{}{"reason": req.Reason}
+	defer func() {
+		s.audit(ctx, owner, audit.EventAdminUserImpersonate, "impersonate_user", req.PlayerId, err, details)
+	}()
+
+	target, err := s.findAccount(ctx, req.PlayerId)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case target.IsPlatformOwner:
+		return nil, status.Error(codes.FailedPrecondition, "CANNOT_IMPERSONATE_PLATFORM_OWNER")
+	case target.Disabled:
+		return nil, status.Error(codes.FailedPrecondition, "ACCOUNT_DISABLED")
+	}
+
+	session, err := s.Tokens.CreateImpersonationToken(ctx, uuid.New().String(), target, owner.Email, impersonationTTL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_TOKEN")
+	}
+	details["session_id"] = session.ID.Hex()
+
+	return &pb.ImpersonateUserResponse{
+		ApiToken:  session.Token,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		User:      adminUserToProto(target),
+	}, nil
+}
+
+// ListEmailDeliveries shows the email outbox without message bodies
+func (s *AdminService) ListEmailDeliveries(ctx context.Context, req *pb.ListEmailDeliveriesRequest) (resp *pb.ListEmailDeliveriesResponse, err error) {
+	owner, err := s.requirePlatformOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { s.audit(ctx, owner, audit.EventAdminEmailsRead, "list_email_deliveries", "", err, nil) }()
+
+	statusFilter := ""
+	for repoStatus, protoStatus := range emailDeliveryStatusToProto {
+		if protoStatus == req.Status {
+			statusFilter = repoStatus
+		}
+	}
+
+	emails, nextPageToken, err := s.Outbox.List(ctx, statusFilter, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_EMAILS")
+	}
+	counts, err := s.Outbox.CountByStatus(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_EMAILS")
+	}
+
+	resp = &pb.ListEmailDeliveriesResponse{
+		NextPageToken: nextPageToken,
+		Counts: &pb.EmailDeliveryCounts{
+			Pending: counts[repo.OutboxStatusPending],
+			Sending: counts[repo.OutboxStatusSending],
+			Sent:    counts[repo.OutboxStatusSent],
+			Dead:    counts[repo.OutboxStatusDead],
+		},
+	}
+	for _, email := range emails {
+		delivery := &pb.EmailDelivery{
+			Id:        email.ID.Hex(),
+			To:        email.To,
+			Subject:   email.Subject,
+			Template:  email.Template,
+			Status:    emailDeliveryStatusToProto[email.Status],
+			Attempts:  int32(email.Attempts), // #nosec G115 - bounded by the worker's max attempts
+			LastError: email.LastError,
+			CreatedAt: timestamppb.New(email.CreatedAt),
+			SentAt:    timestampFromTimePtr(email.SentAt),
+		}
+		if email.Status == repo.OutboxStatusPending {
+			delivery.NextAttemptAt = timestamppb.New(email.NextAttemptAt)
+		}
+		resp.Deliveries = append(resp.Deliveries, delivery)
+	}
+	return resp, nil
+}
+
+// requirePlatformOwner returns the calling platform owner's player record
+func (s *AdminService) requirePlatformOwner(ctx context.Context) (*repo.Player, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+	isOwner, err := subject.IsPlatformOwner(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !isOwner {
+		return nil, status.Error(codes.PermissionDenied, "PLATFORM_OWNER_REQUIRED")
+	}
+
+	player, err := s.Players.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	return player, nil
+}
+
+// findAccount loads a player that can log in
+func (s *AdminService) findAccount(ctx context.Context, playerID string) (*repo.Player, error) {
+	player, err := s.Players.FindByID(ctx, playerID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+	if !player.CanLogin() {
+		return nil, status.Error(codes.FailedPrecondition, "PLAYER_HAS_NO_ACCOUNT")
+	}
+	return player, nil
+}
+
+// audit records an admin call and its outcome
+func (s *AdminService) audit(ctx context.Context, owner *repo.Player, eventType audit.EventType, action, targetID string, err error, details map[string]interface{}) {
+	if s.Audit == nil {
+		return
+	}
+
+	result, message := "SUCCESS", action
+	if err != nil {
+		result, message = "FAILURE", status.Convert(err).Message()
+	}
+
+	targetType := ""
+	if targetID != "" {
+		targetType = "player"
+	}
+
+	s.Audit.LogEvent(ctx, audit.AuditEvent{
+		Type:       eventType,
+		Action:     action,
+		ActorID:    owner.Email,
+		ActorType:  "platform_owner",
+		TargetID:   targetID,
+		TargetType: targetType,
+		Result:     result,
+		Message:    message,
+		Details:    details,
+	})
+}
+
+func adminUserToProto(player *repo.Player) *pb.AdminUser {
+	clubCount := len(player.ClubMemberships)
+	return &pb.AdminUser{
+		Id:              player.ID.Hex(),
+		Email:           player.Email,
+		DisplayName:     player.DisplayName,
+		FirstName:       player.FirstName,
+		LastName:        player.LastName,
+		IsPlatformOwner: player.IsPlatformOwner,
+		Disabled:        player.Disabled,
+		DisabledReason:  player.DisabledReason,
+		DisabledAt:      timestampFromTimePtr(player.DisabledAt),
+		LastLoginAt:     timestampFromTimePtr(player.LastLoginAt),
+		CreatedAt:       timestamppb.New(player.CreatedAt),
+		ClubCount:       int32(clubCount), // #nosec G115 - membership count is small
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// newMockTokenRepo creates a token repo on the mock database, answering its
+// index setup (three legacy index drops and four index builds)
+func newMockTokenRepo(mt *mtest.T) *repo.TokenRepo {
+	for i := 0; i < 7; i++ {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+	}
+	return repo.NewTokenRepo(mt.DB, "pepper", false)
+}
+
+func newAdminService(mt *mtest.T) *AdminService {
+	mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+	svc := &AdminService{Players: repo.NewPlayerRepo(mt.DB), Tokens: newMockTokenRepo(mt)}
+	mt.ClearEvents()
+	return svc
+}
+
+// accountDoc is a player document of an account that can log in
+func accountDoc(id primitive.ObjectID, email string, extra ...bson.E) bson.D {
+	return append(bson.D{{Key: "_id", Value: id}, {Key: "email", Value: email}}, extra...)
+}
+
+// updatesTo returns the started update commands sent to a collection
+func updatesTo(mt *mtest.T, collection string) []bson.Raw {
+	var updates []bson.Raw
+	for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+		if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == collection {
+			updates = append(updates, event.Command)
+		}
+	}
+	return updates
+}
+
+func TestDisableUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ownerID, targetID := primitive.NewObjectID(), primitive.NewObjectID()
+	owner := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+
+	mt.Run("revokes sessions and every API key", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com")),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: accountDoc(targetID, "anna@example.com", bson.E{Key: "disabled", Value: true})}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 3}),
+		)
+
+		resp, err := svc.DisableUser(owner, &pb.DisableUserRequest{PlayerId: targetID.Hex(), Disabled: true, Reason: "spam"})
+		require.NoError(mt, err)
+		require.True(mt, resp.GetUser().GetDisabled())
+
+		keys := updatesTo(mt, "api_keys")
+		require.Len(mt, keys, 1)
+		filter := keys[0].Lookup("updates", "0", "q").Document()
+		require.Equal(mt, "anna@example.com", filter.Lookup("owner_email").StringValue())
+		_, err = filter.LookupErr("club_level")
+		require.Error(mt, err, "club keys must be revoked too")
+	})
+
+	mt.Run("re-enabling leaves sessions alone", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com", bson.E{Key: "disabled", Value: true})),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: accountDoc(targetID, "anna@example.com")}),
+		)
+
+		resp, err := svc.DisableUser(owner, &pb.DisableUserRequest{PlayerId: targetID.Hex(), Disabled: false})
+		require.NoError(mt, err)
+		require.False(mt, resp.GetUser().GetDisabled())
+		require.Empty(mt, updatesTo(mt, "api_tokens"))
+	})
+
+	mt.Run("cannot disable self", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+		)
+
+		_, err := svc.DisableUser(owner, &pb.DisableUserRequest{PlayerId: ownerID.Hex(), Disabled: true})
+		require.Equal(mt, codes.InvalidArgument, status.Code(err))
+	})
+
+	mt.Run("cannot disable a platform owner", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "other@example.com", bson.E{Key: "is_platform_owner", Value: true})),
+		)
+
+		_, err := svc.DisableUser(owner, &pb.DisableUserRequest{PlayerId: targetID.Hex(), Disabled: true})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "CANNOT_DISABLE_PLATFORM_OWNER", status.Convert(err).Message())
+	})
+
+	mt.Run("requires a platform owner", func(mt *mtest.T) {
+		svc := newAdminService(mt)
+
+		ctx := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+		_, err := svc.DisableUser(ctx, &pb.DisableUserRequest{PlayerId: targetID.Hex(), Disabled: true})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+		require.Nil(mt, mt.GetStartedEvent())
+	})
+}
+
+func TestForceLogout(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	owner := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+
+	mt.Run("revokes every session", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		targetID := primitive.NewObjectID()
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(primitive.NewObjectID(), "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com")),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+		)
+
+		resp, err := svc.ForceLogout(owner, &pb.ForceLogoutRequest{PlayerId: targetID.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, int64(2), resp.GetRevokedSessions())
+
+		sessions := updatesTo(mt, "api_tokens")
+		require.Len(mt, sessions, 1)
+		require.Equal(mt, "anna@example.com", sessions[0].Lookup("updates", "0", "q", "email").StringValue())
+		require.True(mt, sessions[0].Lookup("updates", "0", "u", "$set", "revoked").Boolean())
+	})
+
+	mt.Run("emailless players have no sessions", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		targetID := primitive.NewObjectID()
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(primitive.NewObjectID(), "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "noemail-"+targetID.Hex()+"@klubbspel.internal")),
+		)
+
+		_, err := svc.ForceLogout(owner, &pb.ForceLogoutRequest{PlayerId: targetID.Hex()})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "PLAYER_HAS_NO_ACCOUNT", status.Convert(err).Message())
+	})
+}
+
+func TestTransferPlatformOwnership(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ownerID, targetID := primitive.NewObjectID(), primitive.NewObjectID()
+	owner := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+
+	mt.Run("grants before revoking", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com")),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		resp, err := svc.TransferPlatformOwnership(owner, &pb.TransferPlatformOwnershipRequest{NewOwnerId: targetID.Hex()})
+		require.NoError(mt, err)
+		require.True(mt, resp.GetNewOwner().GetIsPlatformOwner())
+
+		updates := updatesTo(mt, "players")
+		require.Len(mt, updates, 2)
+		require.Equal(mt, "anna@example.com", updates[0].Lookup("updates", "0", "q", "email").StringValue())
+		require.True(mt, updates[0].Lookup("updates", "0", "u", "$set", "is_platform_owner").Boolean())
+		require.Equal(mt, "owner@example.com", updates[1].Lookup("updates", "0", "q", "email").StringValue())
+		require.False(mt, updates[1].Lookup("updates", "0", "u", "$set", "is_platform_owner").Boolean())
+	})
+
+	mt.Run("not to self", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+		)
+
+		_, err := svc.TransferPlatformOwnership(owner, &pb.TransferPlatformOwnershipRequest{NewOwnerId: ownerID.Hex()})
+		require.Equal(mt, codes.InvalidArgument, status.Code(err))
+		require.Equal(mt, "CANNOT_TRANSFER_TO_SELF", status.Convert(err).Message())
+	})
+
+	mt.Run("not to a disabled account", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com", bson.E{Key: "disabled", Value: true})),
+		)
+
+		_, err := svc.TransferPlatformOwnership(owner, &pb.TransferPlatformOwnershipRequest{NewOwnerId: targetID.Hex()})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Empty(mt, updatesTo(mt, "players"))
+	})
+}
+
+func TestImpersonateUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ownerID, targetID := primitive.NewObjectID(), primitive.NewObjectID()
+	owner := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+
+	mt.Run("issues a short read-only session", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "anna@example.com")),
+			mtest.CreateSuccessResponse(),
+		)
+
+		start := time.Now()
+		resp, err := svc.ImpersonateUser(owner, &pb.ImpersonateUserRequest{PlayerId: targetID.Hex(), Reason: "support ticket"})
+		require.NoError(mt, err)
+		require.NotEmpty(mt, resp.GetApiToken())
+		require.WithinDuration(mt, start.Add(impersonationTTL), resp.GetExpiresAt().AsTime(), time.Minute)
+
+		mt.GetStartedEvent() // Owner lookup
+		mt.GetStartedEvent() // Target lookup
+		session := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		require.True(mt, session.Lookup("read_only").Boolean())
+		require.Equal(mt, "owner@example.com", session.Lookup("impersonated_by").StringValue())
+		require.Equal(mt, "anna@example.com", session.Lookup("email").StringValue())
+		require.Equal(mt, repo.HashToken(resp.GetApiToken(), []byte("pepper")), session.Lookup("token_hash").StringValue())
+	})
+
+	mt.Run("not a platform owner", func(mt *mtest.T) {
+		players := mt.DB.Name() + ".players"
+		svc := newAdminService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(ownerID, "owner@example.com")),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch, accountDoc(targetID, "other@example.com", bson.E{Key: "is_platform_owner", Value: true})),
+		)
+
+		_, err := svc.ImpersonateUser(owner, &pb.ImpersonateUserRequest{PlayerId: targetID.Hex(), Reason: "support ticket"})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "CANNOT_IMPERSONATE_PLATFORM_OWNER", status.Convert(err).Message())
+	})
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/audit"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// apiKeyPrefixLength is how much of a key is kept in clear text to recognize it
+const apiKeyPrefixLength = 12
+
+// CreateAPIKey creates a personal or club API key and returns it once
+func (s *AuthService) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := ParseScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	clubID := strings.TrimSpace(req.ClubId)
+	switch {
+	case req.ClubLevel && clubID == "":
+		return nil, status.Error(codes.InvalidArgument, "CLUB_ID_REQUIRED")
+	case req.ClubLevel:
+		if err := requireClubPermission(ctx, clubID, PermManageClub); err != nil {
+			return nil, err
+		}
+	case clubID != "":
+		if !isClubMember(player, clubID) {
+			return nil, status.Error(codes.PermissionDenied, "CLUB_MEMBERSHIP_REQUIRED")
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_KEY")
+	}
+	plainKey := repo.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &repo.APIKey{
+		Name:       strings.TrimSpace(req.Name),
+		Prefix:     plainKey[:apiKeyPrefixLength],
+		KeyHash:    repo.HashAPIKey(plainKey),
+		OwnerEmail: player.Email,
+		PlayerID:   player.ID,
+		ClubID:     clubID,
+		ClubLevel:  req.ClubLevel,
+		Scopes:     scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, int(req.ExpiresInDays))
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.TokenRepo.CreateAPIKey(ctx, key); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_KEY")
+	}
+
+	s.auditAPIKey(ctx, audit.EventAuthAPIKeyCreated, "auth.apikey.create", key, fmt.Sprintf("API key %q created", key.Name))
+
+	return &pb.CreateAPIKeyResponse{ApiKey: apiKeyToProto(key), Key: plainKey}, nil
+}
+
+// ListAPIKeys returns the caller's personal keys, or a club's club keys
+func (s *AuthService) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	var keys []*repo.APIKey
+	var err error
+	if req.ClubId != "" {
+		if err := requireClubPermission(ctx, req.ClubId, PermManageClub); err != nil {
+			return nil, err
+		}
+		keys, err = s.TokenRepo.ListClubAPIKeys(ctx, req.ClubId)
+	} else {
+		keys, err = s.TokenRepo.ListPersonalAPIKeys(ctx, subject.GetEmail())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_API_KEYS")
+	}
+
+	if s.Audit != nil {
+		s.Audit.LogEvent(ctx, audit.AuditEvent{
+			Type:       audit.EventAuthAPIKeyListed,
+			Action:     "auth.apikey.list",
+			ActorID:    subject.GetEmail(),
+			ActorType:  "user",
+			TargetType: "api_key",
+			ClubID:     req.ClubId,
+			Result:     "SUCCESS",
+			Message:    fmt.Sprintf("Listed %d API keys", len(keys)),
+		})
+	}
+
+	resp := &pb.ListAPIKeysResponse{}
+	for _, key := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, apiKeyToProto(key))
+	}
+	return resp, nil
+}
+
+// RevokeAPIKey revokes a personal key of the caller, or a club key of a club
+// the caller manages
+func (s *AuthService) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	key, err := s.TokenRepo.GetAPIKeyByID(ctx, req.ApiKeyId)
+	if err != nil || key.Revoked {
+		return nil, status.Error(codes.NotFound, "API_KEY_NOT_FOUND")
+	}
+
+	if key.ClubLevel {
+		if err := requireClubPermission(ctx, key.ClubID, PermManageClub); err != nil {
+			return nil, err
+		}
+	} else if key.OwnerEmail != subject.GetEmail() {
+		// Do not reveal other users' keys
+		return nil, status.Error(codes.NotFound, "API_KEY_NOT_FOUND")
+	}
+
+	if err := s.TokenRepo.RevokeAPIKey(ctx, key.ID); err != nil {
+		return nil, status.Error(codes.NotFound, "API_KEY_NOT_FOUND")
+	}
+
+	s.auditAPIKey(ctx, audit.EventAuthAPIKeyRevoked, "auth.apikey.revoke", key, fmt.Sprintf("API key %q revoked", key.Name))
+
+	return &pb.RevokeAPIKeyResponse{Revoked: true}, nil
+}
+
+func (s *AuthService) auditAPIKey(ctx context.Context, eventType audit.EventType, action string, key *repo.APIKey, message string) {
+	if s.Audit == nil {
+		return
+	}
+
+	actor := ""
+	if subject := GetSubjectFromContext(ctx); subject != nil {
+		actor = subject.GetEmail()
+	}
+
+	s.Audit.LogEvent(ctx, audit.AuditEvent{
+		Type:       eventType,
+		Action:     action,
+		ActorID:    actor,
+		ActorType:  "user",
+		TargetID:   key.ID.Hex(),
+		TargetType: "api_key",
+		ClubID:     key.ClubID,
+		Result:     "SUCCESS",
+		Message:    message,
+		Details: map[string]interface{}{
+			"key_prefix": key.Prefix,
+			"scopes":     key.Scopes,
+			"club_level": key.ClubLevel,
+		},
+	})
+}
+
+func apiKeyToProto(key *repo.APIKey) *pb.APIKey {
+	return &pb.APIKey{
+		Id:         key.ID.Hex(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ClubId:     key.ClubID,
+		ClubLevel:  key.ClubLevel,
+		CreatedBy:  key.OwnerEmail,
+		CreatedAt:  timestamppb.New(key.CreatedAt),
+		ExpiresAt:  timestampFromTimePtr(key.ExpiresAt),
+		LastUsedAt: timestampFromTimePtr(key.LastUsedAt),
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/oidc"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// oidcLoginTTL is how long the user has to finish signing in at the provider
+const oidcLoginTTL = 10 * time.Minute
+
+// OIDCBindingCookie holds a secret that ties a pending login to the browser
+// that started it, so a victim cannot be made to complete an attacker's login.
+// The gateway turns the OIDCBindingHeader response metadata into this cookie;
+// an empty value clears it.
+const (
+	OIDCBindingCookie = "klubbspel_oidc_binding"
+	OIDCBindingHeader = "x-oidc-binding"
+)
+
+// ListOIDCProviders returns the configured external identity providers
+func (s *AuthService) ListOIDCProviders(ctx context.Context, req *pb.ListOIDCProvidersRequest) (*pb.ListOIDCProvidersResponse, error) {
+	resp := &pb.ListOIDCProvidersResponse{}
+	for _, provider := range s.Providers {
+		resp.Providers = append(resp.Providers, &pb.OIDCProvider{
+			Id:          provider.ID,
+			DisplayName: provider.DisplayName,
+		})
+	}
+	return resp, nil
+}
+
+// StartOIDCLogin creates a pending login and returns the provider's authorization URL
+func (s *AuthService) StartOIDCLogin(ctx context.Context, req *pb.StartOIDCLoginRequest) (*pb.StartOIDCLoginResponse, error) {
+	provider := s.oidcProvider(req.Provider)
+	if provider == nil {
+		return nil, status.Error(codes.NotFound, "OIDC_PROVIDER_NOT_FOUND")
+	}
+
+	state, errState := oidc.RandomString()
+	nonce, errNonce := oidc.RandomString()
+	verifier, errVerifier := oidc.RandomString()
+	binding, errBinding := oidc.RandomString()
+	if errState != nil || errNonce != nil || errVerifier != nil || errBinding != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_START_OIDC_LOGIN")
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Error().Err(err).Str("provider", provider.ID).Msg("OIDC provider discovery failed")
+		return nil, status.Error(codes.Unavailable, "OIDC_PROVIDER_UNAVAILABLE")
+	}
+
+	if _, err := s.OIDC.CreateState(ctx, state, provider.ID, nonce, verifier, hashOIDCBinding(binding), oidcLoginTTL); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_START_OIDC_LOGIN")
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(OIDCBindingHeader, binding)); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_START_OIDC_LOGIN")
+	}
+
+	return &pb.StartOIDCLoginResponse{AuthorizationUrl: authURL, State: state}, nil
+}
+
+// CompleteOIDCLogin redeems the authorization code and returns an API token,
+// exactly like ValidateToken does for magic links
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, req *pb.CompleteOIDCLoginRequest) (*pb.ValidateTokenResponse, error) {
+	pending, err := s.OIDC.ConsumeState(ctx, req.State)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "OIDC_STATE_INVALID")
+	}
+	// The state is single use either way, so the cookie is no longer needed
+	_ = grpc.SetHeader(ctx, metadata.Pairs(OIDCBindingHeader, ""))
+	binding := oidcBinding(ctx)
+	if binding == "" || subtle.ConstantTimeCompare([]byte(hashOIDCBinding(binding)), []byte(pending.BindingHash)) != 1 {
+		log.Warn().Str("provider", pending.Provider).Msg("OIDC login completed by a different browser")
+		return nil, status.Error(codes.InvalidArgument, "OIDC_STATE_INVALID")
+	}
+
+	provider := s.oidcProvider(pending.Provider)
+	if provider == nil {
+		return nil, status.Error(codes.NotFound, "OIDC_PROVIDER_NOT_FOUND")
+	}
+
+	claims, err := provider.Exchange(ctx, req.Code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		log.Warn().Err(err).Str("provider", provider.ID).Msg("OIDC login rejected")
+		if errors.Is(err, oidc.ErrDiscovery) {
+			return nil, status.Error(codes.Unavailable, "OIDC_PROVIDER_UNAVAILABLE")
+		}
+		return nil, status.Error(codes.Unauthenticated, "OIDC_LOGIN_FAILED")
+	}
+
+	player, err := s.oidcPlayer(ctx, provider.ID, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.OIDC.LinkIdentity(ctx, provider.ID, claims.Subject, player.ID, player.Email); err != nil {
+		log.Warn().Err(err).
+			Str("provider", provider.ID).
+			Str("email", player.Email).
+			Msg("Failed to link OIDC identity")
+	}
+
+	if err := s.PlayerRepo.UpdateLastLogin(ctx, player.Email); err != nil {
+		log.Warn().Err(err).
+			Str("email", player.Email).
+			Msg("Failed to update last login timestamp")
+	}
+
+	return s.issueAPIToken(ctx, player)
+}
+
+// oidcBinding reads the binding cookie the gateway forwarded as metadata
+func oidcBinding(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	header := http.Header{"Cookie": append(md.Get("cookie"), md.Get("grpcgateway-cookie")...)}
+	cookie, err := (&http.Request{Header: header}).Cookie(OIDCBindingCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func hashOIDCBinding(binding string) string {
+	sum := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(sum[:])
+}
+
+// oidcPlayer resolves the player for a provider account: an existing link wins,
+// otherwise the verified email is matched or a new player is created
+func (s *AuthService) oidcPlayer(ctx context.Context, providerID string, claims *oidc.Claims) (*repo.Player, error) {
+	identity, err := s.OIDC.FindIdentity(ctx, providerID, claims.Subject)
+	if err == nil {
+		player, err := s.PlayerRepo.FindByID(ctx, identity.PlayerID.Hex())
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "OIDC_LOGIN_FAILED")
+		}
+		return player, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, status.Error(codes.Internal, "OIDC_LOGIN_FAILED")
+	}
+
+	// Linking by email is only safe when the provider vouches for the address
+	email, err := claims.VerifiedEmail()
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, "OIDC_EMAIL_NOT_VERIFIED")
+	}
+
+	return s.findOrCreatePlayer(ctx, email, claims.GivenName, claims.FamilyName)
+}
+
+func (s *AuthService) oidcProvider(id string) *oidc.Provider {
+	for _, provider := range s.Providers {
+		if provider.ID == id {
+			return provider
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func TestCompleteOIDCLoginRequiresBindingCookie(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name   string
+		cookie string
+	}{
+		{"no cookie", ""},
+		{"another browser's cookie", OIDCBindingCookie + "=attacker"},
+	}
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			mt.AddMockResponses(
+				mtest.CreateSuccessResponse(), // State indexes
+				mtest.CreateSuccessResponse(), // Identity indexes
+				mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+					{Key: "_id", Value: primitive.NewObjectID()},
+					{Key: "state", Value: "s1"},
+					{Key: "provider", Value: "google"},
+					{Key: "binding_hash", Value: hashOIDCBinding("victim")},
+					{Key: "expires_at", Value: time.Now().Add(oidcLoginTTL)},
+				}}),
+			)
+			svc := &AuthService{OIDC: repo.NewOIDCRepo(mt.DB)}
+
+			ctx := context.Background()
+			if test.cookie != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("cookie", test.cookie))
+			}
+			_, err := svc.CompleteOIDCLogin(ctx, &pb.CompleteOIDCLoginRequest{State: "s1", Code: "code"})
+			require.Equal(mt, codes.InvalidArgument, status.Code(err))
+			require.Equal(mt, "OIDC_STATE_INVALID", status.Convert(err).Message())
+		})
+	}
+}
+
+func TestOIDCBindingFromCookie(t *testing.T) {
+	for _, key := range []string{"cookie", "grpcgateway-cookie"} {
+		md := metadata.Pairs(key, "lang=sv; "+OIDCBindingCookie+"=b1")
+		require.Equal(t, "b1", oidcBinding(metadata.NewIncomingContext(context.Background(), md)), key)
+	}
+	require.Empty(t, oidcBinding(context.Background()))
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/webauthn"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// passkeyChallengeTTL is how long a registration or login ceremony may take
+const passkeyChallengeTTL = 5 * time.Minute
+
+// BeginPasskeyRegistration issues WebAuthn creation options for the current user
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, req *pb.BeginPasskeyRegistrationRequest) (*pb.BeginPasskeyRegistrationResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.newPasskeyChallenge(ctx, repo.WebAuthnCeremonyRegistration, player.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.WebAuthn.ListByPlayer(ctx, player.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_PASSKEYS")
+	}
+
+	displayName := player.DisplayName
+	if displayName == "" {
+		displayName = player.Email
+	}
+
+	return &pb.BeginPasskeyRegistrationResponse{
+		Challenge:            challenge,
+		RpId:                 s.RelyingParty.ID,
+		RpName:               s.RelyingParty.Name,
+		UserId:               player.ID[:],
+		UserName:             player.Email,
+		UserDisplayName:      displayName,
+		Algorithms:           webauthn.SupportedAlgorithms,
+		ExcludeCredentialIds: credentialIDs(credentials),
+		TimeoutMs:            int32(passkeyChallengeTTL / time.Millisecond),
+		UserVerification:     webauthn.UserVerificationRequired,
+	}, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's attestation and stores the passkey
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, req *pb.FinishPasskeyRegistrationRequest) (*pb.FinishPasskeyRegistrationResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.consumePasskeyChallenge(ctx, req.ClientDataJson, repo.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Email != player.Email {
+		return nil, status.Error(codes.InvalidArgument, "PASSKEY_CHALLENGE_INVALID")
+	}
+
+	challengeBytes, _ := base64.RawURLEncoding.DecodeString(challenge.Challenge)
+	credential, err := s.RelyingParty.VerifyRegistration(challengeBytes, req.ClientDataJson, req.AttestationObject)
+	if err != nil {
+		log.Warn().Err(err).Str("email", player.Email).Msg("Passkey registration rejected")
+		return nil, status.Error(codes.InvalidArgument, "PASSKEY_REGISTRATION_FAILED")
+	}
+	if !bytes.Equal(credential.ID, req.CredentialId) {
+		return nil, status.Error(codes.InvalidArgument, "PASSKEY_REGISTRATION_FAILED")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	stored, err := s.WebAuthn.CreateCredential(ctx, &repo.WebAuthnCredential{
+		CredentialID: base64.RawURLEncoding.EncodeToString(credential.ID),
+		PlayerID:     player.ID,
+		Email:        player.Email,
+		PublicKey:    credential.PublicKey,
+		Algorithm:    credential.Algorithm,
+		SignCount:    credential.SignCount,
+		AAGUID:       credential.AAGUID,
+		Transports:   req.Transports,
+		Name:         name,
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Error(codes.AlreadyExists, "PASSKEY_ALREADY_REGISTERED")
+		}
+		return nil, status.Error(codes.Internal, "FAILED_TO_STORE_PASSKEY")
+	}
+
+	return &pb.FinishPasskeyRegistrationResponse{Passkey: passkeyToProto(stored)}, nil
+}
+
+// BeginPasskeyLogin issues WebAuthn request options. With an email the allowed
+// credentials are limited to that user's passkeys; an unknown email gets an empty
+// list so the response does not reveal which addresses have accounts.
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context, req *pb.BeginPasskeyLoginRequest) (*pb.BeginPasskeyLoginResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var allowed [][]byte
+	if email != "" {
+		if player, err := s.PlayerRepo.FindByEmail(ctx, email); err == nil {
+			credentials, err := s.WebAuthn.ListByPlayer(ctx, player.ID)
+			if err != nil {
+				return nil, status.Error(codes.Internal, "FAILED_TO_LIST_PASSKEYS")
+			}
+			allowed = credentialIDs(credentials)
+		}
+	}
+
+	challenge, err := s.newPasskeyChallenge(ctx, repo.WebAuthnCeremonyLogin, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BeginPasskeyLoginResponse{
+		Challenge:          challenge,
+		RpId:               s.RelyingParty.ID,
+		AllowCredentialIds: allowed,
+		TimeoutMs:          int32(passkeyChallengeTTL / time.Millisecond),
+		UserVerification:   webauthn.UserVerificationRequired,
+	}, nil
+}
+
+// FinishPasskeyLogin verifies a passkey assertion and returns an API token,
+// exactly like ValidateToken does for magic links
+func (s *AuthService) FinishPasskeyLogin(ctx context.Context, req *pb.FinishPasskeyLoginRequest) (*pb.ValidateTokenResponse, error) {
+	challenge, err := s.consumePasskeyChallenge(ctx, req.ClientDataJson, repo.WebAuthnCeremonyLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.WebAuthn.FindByCredentialID(ctx, base64.RawURLEncoding.EncodeToString(req.CredentialId))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_NOT_RECOGNIZED")
+	}
+	if challenge.Email != "" && challenge.Email != credential.Email {
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_VERIFICATION_FAILED")
+	}
+	if len(req.UserHandle) > 0 && !bytes.Equal(req.UserHandle, credential.PlayerID[:]) {
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_VERIFICATION_FAILED")
+	}
+
+	challengeBytes, _ := base64.RawURLEncoding.DecodeString(challenge.Challenge)
+	signCount, err := s.RelyingParty.VerifyAssertion(challengeBytes, credential.PublicKey, credential.SignCount, req.ClientDataJson, req.AuthenticatorData, req.Signature)
+	if err != nil {
+		log.Warn().Err(err).Str("email", credential.Email).Str("passkeyID", credential.ID.Hex()).Msg("Passkey login rejected")
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_VERIFICATION_FAILED")
+	}
+
+	if err := s.WebAuthn.UpdateSignCount(ctx, credential.ID, credential.SignCount, signCount); err != nil {
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_VERIFICATION_FAILED")
+	}
+
+	player, err := s.PlayerRepo.FindByID(ctx, credential.PlayerID.Hex())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "PASSKEY_NOT_RECOGNIZED")
+	}
+
+	if err := s.PlayerRepo.UpdateLastLogin(ctx, player.Email); err != nil {
+		log.Warn().Err(err).
+			Str("email", player.Email).
+			Msg("Failed to update last login timestamp")
+	}
+
+	return s.issueAPIToken(ctx, player)
+}
+
+// ListPasskeys returns the current user's passkeys
+func (s *AuthService) ListPasskeys(ctx context.Context, req *pb.ListPasskeysRequest) (*pb.ListPasskeysResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := s.WebAuthn.ListByPlayer(ctx, player.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_PASSKEYS")
+	}
+
+	resp := &pb.ListPasskeysResponse{}
+	for _, credential := range credentials {
+		resp.Passkeys = append(resp.Passkeys, passkeyToProto(credential))
+	}
+	return resp, nil
+}
+
+// DeletePasskey removes one of the current user's passkeys
+func (s *AuthService) DeletePasskey(ctx context.Context, req *pb.DeletePasskeyRequest) (*pb.DeletePasskeyResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.WebAuthn.Delete(ctx, req.PasskeyId, player.ID); err != nil {
+		return nil, status.Error(codes.NotFound, "PASSKEY_NOT_FOUND")
+	}
+
+	return &pb.DeletePasskeyResponse{Deleted: true}, nil
+}
+
+// currentPlayer loads the player behind the authenticated subject
+func (s *AuthService) currentPlayer(ctx context.Context) (*repo.Player, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	player, err := s.PlayerRepo.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+	return player, nil
+}
+
+func (s *AuthService) newPasskeyChallenge(ctx context.Context, ceremony, email string) ([]byte, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PASSKEY_CHALLENGE")
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(challenge)
+	if _, err := s.WebAuthn.CreateChallenge(ctx, encoded, ceremony, email, passkeyChallengeTTL); err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PASSKEY_CHALLENGE")
+	}
+	return challenge, nil
+}
+
+// consumePasskeyChallenge finds and invalidates the challenge echoed in clientDataJSON
+func (s *AuthService) consumePasskeyChallenge(ctx context.Context, clientDataJSON []byte, ceremony string) (*repo.WebAuthnChallenge, error) {
+	challenge, err := webauthn.ChallengeFromClientData(clientDataJSON)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "PASSKEY_CHALLENGE_INVALID")
+	}
+
+	stored, err := s.WebAuthn.ConsumeChallenge(ctx, base64.RawURLEncoding.EncodeToString(challenge), ceremony)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "PASSKEY_CHALLENGE_INVALID")
+	}
+	return stored, nil
+}
+
+func credentialIDs(credentials []*repo.WebAuthnCredential) [][]byte {
+	var ids [][]byte
+	for _, credential := range credentials {
+		if id, err := base64.RawURLEncoding.DecodeString(credential.CredentialID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func passkeyToProto(credential *repo.WebAuthnCredential) *pb.Passkey {
+	return &pb.Passkey{
+		Id:         credential.ID.Hex(),
+		Name:       credential.Name,
+		CreatedAt:  timestamppb.New(credential.CreatedAt),
+		LastUsedAt: timestampFromTimePtr(credential.LastUsedAt),
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// Default token expiry policy
+const (
+	defaultAccessTokenTTL  = time.Hour
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// RefreshToken rotates a refresh token and returns a new API token for the same
+// session. A refresh token that was already used means it leaked, so the whole
+// session is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.ValidateTokenResponse, error) {
+	refresh, err := s.TokenRepo.ConsumeRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		if previous, findErr := s.TokenRepo.FindRefreshToken(ctx, req.RefreshToken); findErr == nil && previous.UsedAt != nil {
+			log.Warn().
+				Str("email", previous.Email).
+				Str("sessionID", previous.FamilyID.Hex()).
+				Msg("Refresh token reuse detected, revoking session")
+			if err := s.TokenRepo.RevokeTokenFamily(ctx, previous.FamilyID); err != nil {
+				log.Error().Err(err).Str("sessionID", previous.FamilyID.Hex()).Msg("Failed to revoke session after refresh token reuse")
+			}
+			return nil, status.Error(codes.Unauthenticated, "REFRESH_TOKEN_REUSED")
+		}
+		return nil, status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_REFRESH_TOKEN")
+	}
+
+	session, err := s.TokenRepo.RotateAPIToken(ctx, refresh.FamilyID, uuid.New().String(), s.accessTokenTTL(), s.refreshTokenTTL())
+	if err != nil {
+		// The session was revoked or expired in the meantime
+		return nil, status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_REFRESH_TOKEN")
+	}
+
+	player, err := s.PlayerRepo.FindByEmail(ctx, session.Email)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "INVALID_OR_EXPIRED_REFRESH_TOKEN")
+	}
+	if player.Disabled {
+		return nil, status.Error(codes.PermissionDenied, "ACCOUNT_DISABLED")
+	}
+
+	return s.tokenResponse(ctx, session, player)
+}
+
+// tokenResponse issues a fresh refresh token for a session and builds the login response
+func (s *AuthService) tokenResponse(ctx context.Context, session *repo.APIToken, player *repo.Player) (*pb.ValidateTokenResponse, error) {
+	refresh, err := s.TokenRepo.CreateRefreshToken(ctx, uuid.New().String(), session, s.refreshTokenTTL())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_TOKEN")
+	}
+
+	expiresAt := session.ExpiresAt
+	if session.AccessExpiresAt != nil {
+		expiresAt = *session.AccessExpiresAt
+	}
+
+	return &pb.ValidateTokenResponse{
+		ApiToken:         session.Token,
+		User:             authUserToProto(player),
+		ExpiresAt:        timestamppb.New(expiresAt),
+		RefreshToken:     refresh.Token,
+		RefreshExpiresAt: timestamppb.New(refresh.ExpiresAt),
+	}, nil
+}
+
+func (s *AuthService) accessTokenTTL() time.Duration {
+	if s.AccessTokenTTL > 0 {
+		return s.AccessTokenTTL
+	}
+	return defaultAccessTokenTTL
+}
+
+func (s *AuthService) refreshTokenTTL() time.Duration {
+	if s.RefreshTokenTTL > 0 {
+		return s.RefreshTokenTTL
+	}
+	return defaultRefreshTokenTTL
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// refreshDoc is a refresh token document of a session
+func refreshDoc(familyID primitive.ObjectID, extra ...bson.E) bson.D {
+	return append(bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "family_id", Value: familyID},
+		{Key: "email", Value: "anna@example.com"},
+		{Key: "expires_at", Value: time.Now().Add(time.Hour)},
+		{Key: "revoked", Value: false},
+	}, extra...)
+}
+
+// noDocument is the findAndModify reply when the filter matches nothing
+func noDocument() bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+}
+
+func TestRefreshToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("rotates the session and the refresh token", func(mt *mtest.T) {
+		sessionID := primitive.NewObjectID()
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: refreshDoc(sessionID)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: sessionDoc(sessionID, "anna@example.com", "rotated")}),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch, accountDoc(primitive.NewObjectID(), "anna@example.com")),
+			mtest.CreateSuccessResponse(),
+		)
+
+		resp, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "old-refresh"})
+		require.NoError(mt, err)
+		require.NotEmpty(mt, resp.GetApiToken())
+		require.NotEmpty(mt, resp.GetRefreshToken())
+		require.NotEqual(mt, "old-refresh", resp.GetRefreshToken())
+		require.Equal(mt, "anna@example.com", resp.GetUser().GetEmail())
+
+		consume := mt.GetStartedEvent().Command
+		require.Equal(mt, "refresh_tokens", consume.Lookup("findAndModify").StringValue())
+		require.Equal(mt, repo.HashToken("old-refresh", []byte("pepper")), consume.Lookup("query", "$or", "0", "token_hash").StringValue())
+		rotate := mt.GetStartedEvent().Command
+		require.Equal(mt, "api_tokens", rotate.Lookup("findAndModify").StringValue())
+		require.Equal(mt, sessionID, rotate.Lookup("query", "_id").ObjectID())
+		require.Equal(mt, repo.HashToken(resp.GetApiToken(), []byte("pepper")), rotate.Lookup("update", "$set", "token_hash").StringValue())
+		require.Equal(mt, "find", mt.GetStartedEvent().CommandName)
+		issued := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		require.Equal(mt, sessionID, issued.Lookup("family_id").ObjectID())
+		require.Equal(mt, repo.HashToken(resp.GetRefreshToken(), []byte("pepper")), issued.Lookup("token_hash").StringValue())
+	})
+
+	mt.Run("reuse revokes the whole session", func(mt *mtest.T) {
+		sessionID := primitive.NewObjectID()
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			noDocument(),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".refresh_tokens", mtest.FirstBatch,
+				refreshDoc(sessionID, bson.E{Key: "used_at", Value: time.Now().Add(-time.Minute)})),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		_, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "stolen"})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+		require.Equal(mt, "REFRESH_TOKEN_REUSED", status.Convert(err).Message())
+
+		revoked := map[string]bson.Raw{}
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "update" {
+				revoked[event.Command.Lookup("update").StringValue()] = event.Command.Lookup("updates", "0").Document()
+			}
+		}
+		require.Equal(mt, sessionID, revoked["refresh_tokens"].Lookup("q", "family_id").ObjectID())
+		require.True(mt, revoked["refresh_tokens"].Lookup("u", "$set", "revoked").Boolean())
+		require.Equal(mt, sessionID, revoked["api_tokens"].Lookup("q", "_id").ObjectID())
+		require.True(mt, revoked["api_tokens"].Lookup("u", "$set", "revoked").Boolean())
+	})
+
+	mt.Run("unknown token", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			noDocument(),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".refresh_tokens", mtest.FirstBatch),
+		)
+
+		_, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "unknown"})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+		require.Equal(mt, "INVALID_OR_EXPIRED_REFRESH_TOKEN", status.Convert(err).Message())
+	})
+
+	mt.Run("token of a revoked family is not reuse", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			noDocument(),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".refresh_tokens", mtest.FirstBatch,
+				refreshDoc(primitive.NewObjectID(), bson.E{Key: "revoked", Value: true})),
+		)
+
+		_, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "revoked"})
+		require.Equal(mt, "INVALID_OR_EXPIRED_REFRESH_TOKEN", status.Convert(err).Message())
+		require.Empty(mt, updatesTo(mt, "api_tokens"))
+	})
+
+	mt.Run("revoked session cannot be refreshed", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: refreshDoc(primitive.NewObjectID())}),
+			noDocument(),
+		)
+
+		_, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "valid"})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+		require.Equal(mt, "INVALID_OR_EXPIRED_REFRESH_TOKEN", status.Convert(err).Message())
+
+		mt.GetStartedEvent() // Consume
+		rotate := mt.GetStartedEvent().Command
+		require.False(mt, rotate.Lookup("query", "revoked").Boolean())
+	})
+
+	mt.Run("disabled account cannot refresh", func(mt *mtest.T) {
+		sessionID := primitive.NewObjectID()
+		svc := newAuthService(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: refreshDoc(sessionID)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: sessionDoc(sessionID, "anna@example.com", "rotated")}),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch,
+				accountDoc(primitive.NewObjectID(), "anna@example.com", bson.E{Key: "disabled", Value: true})),
+		)
+
+		_, err := svc.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: "valid"})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+		require.Equal(mt, "ACCOUNT_DISABLED", status.Convert(err).Message())
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			require.NotEqual(mt, "insert", event.CommandName, "no refresh token may be issued")
+		}
+	})
+}
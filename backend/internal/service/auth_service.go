@@ -12,16 +12,29 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/goencoder/klubbspel/backend/internal/audit"
 	"github.com/goencoder/klubbspel/backend/internal/email"
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+	"github.com/goencoder/klubbspel/backend/internal/oidc"
 	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/webauthn"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
 )
 
 // AuthService handles authentication operations
 type AuthService struct {
-	TokenRepo  *repo.TokenRepo
-	PlayerRepo *repo.PlayerRepo
-	EmailSvc   email.Service
+	TokenRepo    *repo.TokenRepo
+	PlayerRepo   *repo.PlayerRepo
+	EmailSvc     email.Service
+	WebAuthn     *repo.WebAuthnRepo
+	RelyingParty *webauthn.RelyingParty
+	OIDC         *repo.OIDCRepo
+	Providers    []*oidc.Provider // External identity providers, in display order
+	Audit        *audit.AuditLogger
+
+	// Token expiry policy; zero values use the defaults in auth_refresh.go
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 // SendMagicLink sends a magic link to the provided email address
@@ -42,8 +55,8 @@ func (s *AuthService) SendMagicLink(ctx context.Context, req *pb.SendMagicLinkRe
 		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_MAGIC_LINK")
 	}
 
-	// Send magic link via email
-	err = s.EmailSvc.SendMagicLink(ctx, email, magicToken, req.ReturnUrl)
+	// Send magic link via email, in the account's language or the browser's for new users
+	err = s.EmailSvc.SendMagicLink(ctx, email, s.recipientLocale(ctx, email), magicToken, req.ReturnUrl)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "FAILED_TO_SEND_EMAIL")
 	}
@@ -73,25 +86,9 @@ func (s *AuthService) ValidateToken(ctx context.Context, req *pb.ValidateTokenRe
 		return nil, status.Error(codes.Internal, "FAILED_TO_CONSUME_TOKEN")
 	}
 
-	// Find or create player
-	player, err := s.PlayerRepo.FindByEmail(ctx, magicToken.Email)
+	player, err := s.findOrCreatePlayer(ctx, magicToken.Email, "", "")
 	if err != nil {
-		// Player doesn't exist, create one
-		player, err = s.PlayerRepo.CreateWithEmail(ctx, magicToken.Email, "", "", "")
-		if err != nil {
-			return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PLAYER")
-		}
-
-		// First user becomes platform owner
-		count, _, err := s.PlayerRepo.List(ctx, "", "", 1, "")
-		if err == nil && len(count) <= 1 {
-			err = s.PlayerRepo.SetPlatformOwner(ctx, magicToken.Email, true)
-			if err != nil {
-				log.Warn().Err(err).
-					Str("email", magicToken.Email).
-					Msg("Failed to set first user as platform owner")
-			}
-		}
+		return nil, err
 	}
 
 	// Update last login
@@ -102,45 +99,54 @@ func (s *AuthService) ValidateToken(ctx context.Context, req *pb.ValidateTokenRe
 			Msg("Failed to update last login timestamp")
 	}
 
-	// Generate API token
-	apiToken := uuid.New().String()
+	return s.issueAPIToken(ctx, player)
+}
+
+// findOrCreatePlayer returns the player with the given email, creating one on
+// first login. The very first player becomes platform owner.
+func (s *AuthService) findOrCreatePlayer(ctx context.Context, email, firstName, lastName string) (*repo.Player, error) {
+	player, err := s.PlayerRepo.FindByEmail(ctx, email)
+	if err == nil {
+		return player, nil
+	}
 
-	// Store API token
-	apiTokenObj, err := s.TokenRepo.CreateAPIToken(ctx, apiToken, magicToken.Email, player.ID, "", "")
+	// Player doesn't exist, create one
+	player, err = s.PlayerRepo.CreateWithEmail(ctx, email, firstName, lastName, "")
 	if err != nil {
-		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_TOKEN")
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PLAYER")
 	}
 
-	// Convert club memberships
-	var clubMemberships []*pb.ClubMembership
-	for _, membership := range player.ClubMemberships {
-		role := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-		if membership.Role == "admin" {
-			role = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
+	// First user becomes platform owner
+	count, _, err := s.PlayerRepo.List(ctx, "", "", 1, "")
+	if err == nil && len(count) <= 1 {
+		err = s.PlayerRepo.SetPlatformOwner(ctx, email, true)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("email", email).
+				Msg("Failed to set first user as platform owner")
 		}
+	}
 
-		clubMemberships = append(clubMemberships, &pb.ClubMembership{
-			ClubId:   membership.ClubID.Hex(),
-			Role:     role,
-			JoinedAt: timestamppb.New(membership.JoinedAt),
-		})
+	return player, nil
+}
+
+// issueAPIToken creates a new API token for an authenticated player
+func (s *AuthService) issueAPIToken(ctx context.Context, player *repo.Player) (*pb.ValidateTokenResponse, error) {
+	if player.Disabled {
+		return nil, status.Error(codes.PermissionDenied, "ACCOUNT_DISABLED")
 	}
 
-	authUser := &pb.AuthUser{
-		Id:              player.ID.Hex(),
-		Email:           player.Email,
-		FirstName:       player.FirstName,
-		LastName:        player.LastName,
-		ClubMemberships: clubMemberships,
-		IsPlatformOwner: player.IsPlatformOwner,
-		LastLoginAt:     timestampFromTimePtr(player.LastLoginAt),
+	// Generate API token
+	apiToken := uuid.New().String()
+
+	// Store API token with the device it was issued to
+	userAgent, ipAddress := clientInfo(ctx)
+	session, err := s.TokenRepo.CreateAPIToken(ctx, apiToken, player.Email, player.ID, userAgent, ipAddress, s.accessTokenTTL(), s.refreshTokenTTL())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_API_TOKEN")
 	}
 
-	return &pb.ValidateTokenResponse{
-		ApiToken:  apiToken,
-		User:      authUser,
-		ExpiresAt: timestamppb.New(apiTokenObj.ExpiresAt),
-	}, nil
+	return s.tokenResponse(ctx, session, player)
 }
 
 // GetCurrentUser returns information about the current authenticated user
@@ -164,30 +170,7 @@ func (s *AuthService) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUser
 	// Get player details from the loaded subject
 	player := lazySubject.player
 
-	// Convert club memberships
-	var clubMemberships []*pb.ClubMembership
-	for _, membership := range player.ClubMemberships {
-		role := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-		if membership.Role == "admin" {
-			role = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-		}
-
-		clubMemberships = append(clubMemberships, &pb.ClubMembership{
-			ClubId:   membership.ClubID.Hex(),
-			Role:     role,
-			JoinedAt: timestamppb.New(membership.JoinedAt),
-		})
-	}
-
-	authUser := &pb.AuthUser{
-		Id:              player.ID.Hex(),
-		Email:           player.Email,
-		FirstName:       player.FirstName,
-		LastName:        player.LastName,
-		ClubMemberships: clubMemberships,
-		IsPlatformOwner: player.IsPlatformOwner,
-		LastLoginAt:     timestampFromTimePtr(player.LastLoginAt),
-	}
+	authUser := authUserToProto(player)
 
 	return &pb.GetCurrentUserResponse{
 		User: authUser,
@@ -225,6 +208,9 @@ func (s *AuthService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRe
 	if req.FirstName == "" || req.LastName == "" {
 		return nil, status.Error(codes.InvalidArgument, "FIRST_AND_LAST_NAME_REQUIRED")
 	}
+	if req.Locale != "" && !i18n.IsSupported(req.Locale) {
+		return nil, status.Error(codes.InvalidArgument, "UNSUPPORTED_LOCALE")
+	}
 
 	// Ensure subject data is loaded
 	lazySubject, ok := subject.(*LazySubject)
@@ -236,8 +222,8 @@ func (s *AuthService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRe
 		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
 	}
 
-	// Update the player's first and last name
-	err := s.PlayerRepo.UpdateProfile(ctx, lazySubject.player.Email, req.FirstName, req.LastName)
+	// Update the player's name and preferred locale
+	err := s.PlayerRepo.UpdateProfile(ctx, lazySubject.player.Email, req.FirstName, req.LastName, req.Locale)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "FAILED_TO_UPDATE_PROFILE")
 	}
@@ -248,34 +234,34 @@ func (s *AuthService) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRe
 		return nil, status.Error(codes.Internal, "FAILED_TO_RELOAD_PLAYER")
 	}
 
-	// Convert club memberships
-	var clubMemberships []*pb.ClubMembership
-	for _, membership := range updatedPlayer.ClubMemberships {
-		role := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-		if membership.Role == "admin" {
-			role = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-		}
+	authUser := authUserToProto(updatedPlayer)
+
+	return &pb.UpdateProfileResponse{
+		User: authUser,
+	}, nil
+}
 
+// authUserToProto converts a player to the authenticated user representation
+func authUserToProto(player *repo.Player) *pb.AuthUser {
+	var clubMemberships []*pb.ClubMembership
+	for _, membership := range player.ClubMemberships {
 		clubMemberships = append(clubMemberships, &pb.ClubMembership{
 			ClubId:   membership.ClubID.Hex(),
-			Role:     role,
+			Role:     membershipRoleToProto(membership.Role),
 			JoinedAt: timestamppb.New(membership.JoinedAt),
 		})
 	}
 
-	authUser := &pb.AuthUser{
-		Id:              updatedPlayer.ID.Hex(),
-		Email:           updatedPlayer.Email,
-		FirstName:       updatedPlayer.FirstName,
-		LastName:        updatedPlayer.LastName,
+	return &pb.AuthUser{
+		Id:              player.ID.Hex(),
+		Email:           player.Email,
+		FirstName:       player.FirstName,
+		LastName:        player.LastName,
 		ClubMemberships: clubMemberships,
-		IsPlatformOwner: updatedPlayer.IsPlatformOwner,
-		LastLoginAt:     timestampFromTimePtr(updatedPlayer.LastLoginAt),
+		IsPlatformOwner: player.IsPlatformOwner,
+		LastLoginAt:     timestampFromTimePtr(player.LastLoginAt),
+		Locale:          player.Locale,
 	}
-
-	return &pb.UpdateProfileResponse{
-		User: authUser,
-	}, nil
 }
 
 // Helper function to convert *time.Time to *timestamppb.Timestamp
@@ -312,6 +298,9 @@ type Subject interface {
 	CanAccessClub(ctx context.Context, clubID string) (bool, error)
 	CanManageClub(ctx context.Context, clubID string) (bool, error)
 	CanReportMatch(ctx context.Context, playerAEmail, playerBEmail string) bool
+
+	// HasClubPermission evaluates the role permission matrix for a club
+	HasClubPermission(ctx context.Context, clubID string, permission Permission) (bool, error)
 }
 
 // LazySubject provides late binding for subject data
@@ -355,28 +344,34 @@ func (ls *LazySubject) ensureLoaded(ctx context.Context) error {
 		return nil
 	}
 
-	// Get API token
-	apiToken, err := ls.tokenRepo.GetAPIToken(ctx, ls.token)
-	if err != nil {
-		return err
+	// Subjects created for an API key already know their email
+	if ls.email == nil {
+		// Get API token
+		apiToken, err := ls.tokenRepo.GetAPIToken(ctx, ls.token)
+		if err != nil {
+			return err
+		}
+		ls.email = &apiToken.Email
+
+		// Update last used timestamp (async, don't block)
+		go func() {
+			_ = ls.tokenRepo.UpdateLastUsed(context.Background(), ls.token)
+		}()
 	}
 
 	// Get player
-	player, err := ls.playerRepo.FindByEmail(ctx, apiToken.Email)
+	player, err := ls.playerRepo.FindByEmail(ctx, *ls.email)
 	if err != nil {
 		return err
 	}
+	if player.Disabled {
+		return status.Error(codes.PermissionDenied, "ACCOUNT_DISABLED")
+	}
 
 	// Cache the data
-	ls.email = &apiToken.Email
 	ls.player = player
 	ls.loaded = true
 
-	// Update last used timestamp (async, don't block)
-	go func() {
-		_ = ls.tokenRepo.UpdateLastUsed(context.Background(), ls.token)
-	}()
-
 	return nil
 }
 
@@ -470,7 +465,7 @@ func (ls *LazySubject) GetAdminClubIDs(ctx context.Context) ([]string, error) {
 
 	var adminClubs []string
 	for _, membership := range ls.player.ClubMemberships {
-		if membership.Role == "admin" {
+		if membership.Role == repo.ClubRoleAdmin {
 			adminClubs = append(adminClubs, membership.ClubID.Hex())
 		}
 	}
@@ -507,6 +502,23 @@ func (ls *LazySubject) CanManageClub(ctx context.Context, clubID string) (bool,
 	return ls.IsClubAdmin(ctx, clubID)
 }
 
+// HasClubPermission checks if the subject's role in a club grants a permission
+func (ls *LazySubject) HasClubPermission(ctx context.Context, clubID string, permission Permission) (bool, error) {
+	isPlatformOwner, err := ls.IsPlatformOwner(ctx)
+	if err != nil {
+		return false, err
+	}
+	if isPlatformOwner {
+		return true, nil
+	}
+
+	role, err := ls.GetClubRole(ctx, clubID)
+	if err != nil {
+		return false, err
+	}
+	return RoleHasPermission(role, permission), nil
+}
+
 // CanReportMatch checks if the subject can report a match
 func (ls *LazySubject) CanReportMatch(ctx context.Context, playerAEmail, playerBEmail string) bool {
 	// Players can only report matches they participated in
@@ -516,6 +528,9 @@ func (ls *LazySubject) CanReportMatch(ctx context.Context, playerAEmail, playerB
 
 // WithSubject adds subject to context
 func WithSubject(ctx context.Context, subject Subject) context.Context {
+	if hint, ok := ctx.Value(localeHintContextKey).(*localeHint); ok {
+		hint.subject = subject
+	}
 	return context.WithValue(ctx, subjectContextKey, subject)
 }
 
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// maxUserAgentLength caps stored user agents; some clients send very long ones
+const maxUserAgentLength = 512
+
+// ListSessions returns the current user's active API tokens as devices
+func (s *AuthService) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	tokens, err := s.TokenRepo.ListActiveAPITokens(ctx, subject.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_SESSIONS")
+	}
+
+	current := GetTokenFromContext(ctx)
+	resp := &pb.ListSessionsResponse{}
+	for _, token := range tokens {
+		resp.Sessions = append(resp.Sessions, sessionToProto(token, s.TokenRepo.MatchesToken(token.TokenHash, current)))
+	}
+	return resp, nil
+}
+
+// RevokeSession signs out one of the current user's sessions
+func (s *AuthService) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	if err := s.TokenRepo.RevokeAPITokenByID(ctx, req.SessionId, subject.GetEmail()); err != nil {
+		return nil, status.Error(codes.NotFound, "SESSION_NOT_FOUND")
+	}
+
+	return &pb.RevokeSessionResponse{Revoked: true}, nil
+}
+
+// RevokeAllOtherSessions signs out every session except the one making the request
+func (s *AuthService) RevokeAllOtherSessions(ctx context.Context, req *pb.RevokeAllOtherSessionsRequest) (*pb.RevokeAllOtherSessionsResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	token := GetTokenFromContext(ctx)
+	if subject == nil || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	revoked, err := s.TokenRepo.RevokeOtherUserTokens(ctx, subject.GetEmail(), token)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_REVOKE_SESSIONS")
+	}
+
+	return &pb.RevokeAllOtherSessionsResponse{RevokedCount: int32(revoked)}, nil
+}
+
+// clientInfo extracts the caller's user agent and IP address. Requests through
+// the REST gateway carry the browser's headers as metadata; direct gRPC calls
+// fall back to the peer address.
+func clientInfo(ctx context.Context) (userAgent, ipAddress string) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range []string{"grpcgateway-user-agent", "user-agent"} {
+			if values := md.Get(key); len(values) > 0 && values[0] != "" {
+				userAgent = values[0]
+				break
+			}
+		}
+
+		// X-Forwarded-For can contain multiple IPs, the first is the client
+		if xff := md.Get("x-forwarded-for"); len(xff) > 0 {
+			ip := strings.TrimSpace(strings.Split(xff[0], ",")[0])
+			if net.ParseIP(ip) != nil {
+				ipAddress = ip
+			}
+		}
+		if xri := md.Get("x-real-ip"); ipAddress == "" && len(xri) > 0 && net.ParseIP(xri[0]) != nil {
+			ipAddress = xri[0]
+		}
+	}
+
+	if ipAddress == "" {
+		if p, ok := peer.FromContext(ctx); ok {
+			if tcpAddr, ok := p.Addr.(*net.TCPAddr); ok {
+				ipAddress = tcpAddr.IP.String()
+			}
+		}
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return userAgent, ipAddress
+}
+
+func sessionToProto(token *repo.APIToken, current bool) *pb.Session {
+	return &pb.Session{
+		Id:         token.ID.Hex(),
+		UserAgent:  token.UserAgent,
+		IpAddress:  token.IPAddress,
+		CreatedAt:  timestamppb.New(token.IssuedAt),
+		LastUsedAt: timestampFromTimePtr(token.LastUsedAt),
+		ExpiresAt:  timestamppb.New(token.ExpiresAt),
+		Current:    current,
+	}
+}
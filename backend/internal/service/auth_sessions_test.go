@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func newAuthService(mt *mtest.T) *AuthService {
+	mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+	svc := &AuthService{PlayerRepo: repo.NewPlayerRepo(mt.DB), TokenRepo: newMockTokenRepo(mt)}
+	mt.ClearEvents()
+	return svc
+}
+
+// sessionDoc is an active API token document for a session token
+func sessionDoc(id primitive.ObjectID, email, token string) bson.D {
+	now := time.Now()
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "token_hash", Value: repo.HashToken(token, []byte("pepper"))},
+		{Key: "email", Value: email},
+		{Key: "issued_at", Value: now.Add(-time.Hour)},
+		{Key: "expires_at", Value: now.Add(time.Hour)},
+		{Key: "user_agent", Value: "Firefox"},
+		{Key: "revoked", Value: false},
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	anna := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+
+	mt.Run("marks the current session", func(mt *mtest.T) {
+		currentID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+		svc := newAuthService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".api_tokens", mtest.FirstBatch,
+			sessionDoc(otherID, "anna@example.com", "phone"),
+			sessionDoc(currentID, "anna@example.com", "laptop"),
+		))
+
+		resp, err := svc.ListSessions(WithToken(anna, "laptop"), &pb.ListSessionsRequest{})
+		require.NoError(mt, err)
+		require.Len(mt, resp.GetSessions(), 2)
+		require.Equal(mt, otherID.Hex(), resp.GetSessions()[0].GetId())
+		require.False(mt, resp.GetSessions()[0].GetCurrent())
+		require.Equal(mt, currentID.Hex(), resp.GetSessions()[1].GetId())
+		require.True(mt, resp.GetSessions()[1].GetCurrent())
+		require.Equal(mt, "Firefox", resp.GetSessions()[1].GetUserAgent())
+
+		find := mt.GetStartedEvent().Command
+		require.Equal(mt, "anna@example.com", find.Lookup("filter", "email").StringValue())
+		require.False(mt, find.Lookup("filter", "revoked").Boolean())
+	})
+
+	mt.Run("requires authentication", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+
+		_, err := svc.ListSessions(context.Background(), &pb.ListSessionsRequest{})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	anna := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+
+	mt.Run("revokes own session", func(mt *mtest.T) {
+		sessionID := primitive.NewObjectID()
+		svc := newAuthService(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		resp, err := svc.RevokeSession(anna, &pb.RevokeSessionRequest{SessionId: sessionID.Hex()})
+		require.NoError(mt, err)
+		require.True(mt, resp.GetRevoked())
+
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		require.Equal(mt, sessionID, update.Lookup("q", "_id").ObjectID())
+		require.Equal(mt, "anna@example.com", update.Lookup("q", "email").StringValue())
+		require.True(mt, update.Lookup("u", "$set", "revoked").Boolean())
+	})
+
+	mt.Run("someone else's session is not found", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		// The filter includes the caller's email, so another user's session matches nothing
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		_, err := svc.RevokeSession(anna, &pb.RevokeSessionRequest{SessionId: primitive.NewObjectID().Hex()})
+		require.Equal(mt, codes.NotFound, status.Code(err))
+		require.Equal(mt, "SESSION_NOT_FOUND", status.Convert(err).Message())
+	})
+
+	mt.Run("invalid session ID is not found", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+
+		_, err := svc.RevokeSession(anna, &pb.RevokeSessionRequest{SessionId: "not-an-id"})
+		require.Equal(mt, codes.NotFound, status.Code(err))
+		require.Nil(mt, mt.GetStartedEvent())
+	})
+
+	mt.Run("requires authentication", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+
+		_, err := svc.RevokeSession(context.Background(), &pb.RevokeSessionRequest{SessionId: primitive.NewObjectID().Hex()})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestRevokeAllOtherSessions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	anna := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+
+	mt.Run("keeps the current session", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 3}))
+
+		resp, err := svc.RevokeAllOtherSessions(WithToken(anna, "laptop"), &pb.RevokeAllOtherSessionsRequest{})
+		require.NoError(mt, err)
+		require.Equal(mt, int32(3), resp.GetRevokedCount())
+
+		filter := mt.GetStartedEvent().Command.Lookup("updates", "0", "q").Document()
+		require.Equal(mt, "anna@example.com", filter.Lookup("email").StringValue())
+		require.Equal(mt, repo.HashToken("laptop", []byte("pepper")), filter.Lookup("$nor", "0", "token_hash").StringValue())
+	})
+
+	mt.Run("requires the current token", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+
+		_, err := svc.RevokeAllOtherSessions(anna, &pb.RevokeAllOtherSessionsRequest{})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+		require.Nil(mt, mt.GetStartedEvent())
+	})
+}
+
+func TestClientInfo(t *testing.T) {
+	tcpPeer := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 50051}}
+
+	tests := []struct {
+		name      string
+		md        metadata.MD
+		peer      *peer.Peer
+		userAgent string
+		ipAddress string
+	}{
+		{
+			name:      "gateway headers",
+			md:        metadata.Pairs("grpcgateway-user-agent", "Firefox", "user-agent", "grpc-go", "x-forwarded-for", "203.0.113.5, 10.0.0.1"),
+			peer:      tcpPeer,
+			userAgent: "Firefox",
+			ipAddress: "203.0.113.5",
+		},
+		{
+			name:      "real IP when forwarded-for is invalid",
+			md:        metadata.Pairs("user-agent", "grpc-go", "x-forwarded-for", "unknown", "x-real-ip", "198.51.100.9"),
+			userAgent: "grpc-go",
+			ipAddress: "198.51.100.9",
+		},
+		{
+			name:      "peer address for direct calls",
+			md:        metadata.Pairs("user-agent", "grpc-go"),
+			peer:      tcpPeer,
+			userAgent: "grpc-go",
+			ipAddress: "10.0.0.7",
+		},
+		{
+			name:      "long user agent is truncated",
+			md:        metadata.Pairs("user-agent", strings.Repeat("a", maxUserAgentLength+100)),
+			userAgent: strings.Repeat("a", maxUserAgentLength),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), test.md)
+			if test.peer != nil {
+				ctx = peer.NewContext(ctx, test.peer)
+			}
+
+			userAgent, ipAddress := clientInfo(ctx)
+			require.Equal(t, test.userAgent, userAgent)
+			require.Equal(t, test.ipAddress, ipAddress)
+		})
+	}
+}
+
+func TestIssueAPITokenRecordsDevice(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("stores user agent and IP address", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("grpcgateway-user-agent", "Firefox", "x-forwarded-for", "203.0.113.5"))
+		player := &repo.Player{ID: primitive.NewObjectID(), Email: "anna@example.com"}
+		resp, err := svc.issueAPIToken(ctx, player)
+		require.NoError(mt, err)
+		require.NotEmpty(mt, resp.GetApiToken())
+		require.NotEmpty(mt, resp.GetRefreshToken())
+
+		insert := mt.GetStartedEvent().Command
+		require.Equal(mt, "api_tokens", insert.Lookup("insert").StringValue())
+		session := insert.Lookup("documents", "0").Document()
+		require.Equal(mt, "Firefox", session.Lookup("user_agent").StringValue())
+		require.Equal(mt, "203.0.113.5", session.Lookup("ip_address").StringValue())
+	})
+
+	mt.Run("disabled accounts get no session", func(mt *mtest.T) {
+		svc := newAuthService(mt)
+
+		_, err := svc.issueAPIToken(context.Background(), &repo.Player{Email: "anna@example.com", Disabled: true})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+		require.Nil(mt, mt.GetStartedEvent())
+	})
+}
@@ -12,17 +12,39 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/goencoder/klubbspel/backend/internal/audit"
 	"github.com/goencoder/klubbspel/backend/internal/email"
 	"github.com/goencoder/klubbspel/backend/internal/repo"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"github.com/rs/zerolog/log"
 )
 
+// invitationTTL is how long an invitation can be answered after it was (re)sent
+const invitationTTL = 7 * 24 * time.Hour
+
+var joinRequestStatusToProto = map[string]pb.JoinRequestStatus{
+	repo.JoinRequestStatusPending:  pb.JoinRequestStatus_JOIN_REQUEST_STATUS_PENDING,
+	repo.JoinRequestStatusApproved: pb.JoinRequestStatus_JOIN_REQUEST_STATUS_APPROVED,
+	repo.JoinRequestStatusRejected: pb.JoinRequestStatus_JOIN_REQUEST_STATUS_REJECTED,
+}
+
+var invitationStatusToProto = map[string]pb.InvitationStatus{
+	repo.InvitationStatusPending:  pb.InvitationStatus_INVITATION_STATUS_PENDING,
+	repo.InvitationStatusAccepted: pb.InvitationStatus_INVITATION_STATUS_ACCEPTED,
+	repo.InvitationStatusDeclined: pb.InvitationStatus_INVITATION_STATUS_DECLINED,
+	repo.InvitationStatusRevoked:  pb.InvitationStatus_INVITATION_STATUS_REVOKED,
+	repo.InvitationStatusExpired:  pb.InvitationStatus_INVITATION_STATUS_EXPIRED,
+}
+
 // ClubMembershipService handles club membership operations
 type ClubMembershipService struct {
-	PlayerRepo *repo.PlayerRepo
-	ClubRepo   *repo.ClubRepo
-	TokenRepo  *repo.TokenRepo // Add token repo for magic link generation
-	EmailSvc   email.Service   // Add email service for invitations
+	PlayerRepo   *repo.PlayerRepo
+	ClubRepo     *repo.ClubRepo
+	TokenRepo    *repo.TokenRepo // Add token repo for magic link generation
+	EmailSvc     email.Service   // Add email service for invitations
+	Invitations  *repo.ClubInvitationRepo
+	JoinRequests *repo.ClubJoinRequestRepo
+	Audit        *audit.AuditLogger
 }
 
 // JoinClub allows a user to join a club (self-registration)
@@ -39,7 +61,7 @@ func (s *ClubMembershipService) JoinClub(ctx context.Context, req *pb.JoinClubRe
 	}
 
 	// Validate club exists
-	_, err := s.ClubRepo.FindByID(ctx, req.ClubId)
+	club, err := s.ClubRepo.FindByID(ctx, req.ClubId)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "CLUB_NOT_FOUND")
 	}
@@ -53,6 +75,17 @@ func (s *ClubMembershipService) JoinClub(ctx context.Context, req *pb.JoinClubRe
 		return nil, status.Error(codes.AlreadyExists, "ALREADY_MEMBER")
 	}
 
+	switch club.EffectiveJoinPolicy() {
+	case repo.ClubJoinPolicyInviteOnly:
+		return nil, status.Error(codes.FailedPrecondition, "CLUB_INVITE_ONLY")
+	case repo.ClubJoinPolicyRequest:
+		joinRequest, err := s.requestToJoin(ctx, subject, club, req.Message)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.JoinClubResponse{Success: true, JoinRequest: joinRequest}, nil
+	}
+
 	// Create membership
 	clubObjID, err := primitive.ObjectIDFromHex(req.ClubId)
 	if err != nil {
@@ -61,7 +94,7 @@ func (s *ClubMembershipService) JoinClub(ctx context.Context, req *pb.JoinClubRe
 
 	membership := &repo.ClubMembership{
 		ClubID:   clubObjID,
-		Role:     "member",
+		Role:     repo.ClubRoleMember,
 		JoinedAt: time.Now(),
 	}
 
@@ -83,6 +116,117 @@ func (s *ClubMembershipService) JoinClub(ctx context.Context, req *pb.JoinClubRe
 	}, nil
 }
 
+// ListJoinRequests lists the join requests of a club for its admins
+func (s *ClubMembershipService) ListJoinRequests(ctx context.Context, req *pb.ListJoinRequestsRequest) (*pb.ListJoinRequestsResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	if err := s.checkClubPermission(ctx, subject, req.ClubId, PermViewAdminData); err != nil {
+		return nil, err
+	}
+
+	statusFilter := ""
+	for value, pbStatus := range joinRequestStatusToProto {
+		if pbStatus == req.Status {
+			statusFilter = value
+		}
+	}
+
+	requests, err := s.JoinRequests.ListByClub(ctx, req.ClubId, statusFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_JOIN_REQUESTS")
+	}
+
+	playerIDs := make([]string, 0, len(requests))
+	for _, request := range requests {
+		playerIDs = append(playerIDs, request.PlayerID)
+	}
+	players, err := s.PlayerRepo.FindByIDs(ctx, playerIDs)
+	if err != nil {
+		players = map[string]*repo.Player{}
+	}
+
+	resp := &pb.ListJoinRequestsResponse{}
+	for _, request := range requests {
+		resp.JoinRequests = append(resp.JoinRequests, joinRequestToProto(request, players[request.PlayerID]))
+	}
+	return resp, nil
+}
+
+// ReviewJoinRequest approves or rejects a pending join request
+func (s *ClubMembershipService) ReviewJoinRequest(ctx context.Context, req *pb.ReviewJoinRequestRequest) (*pb.ReviewJoinRequestResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	joinRequest, err := s.JoinRequests.FindByID(ctx, req.RequestId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "JOIN_REQUEST_NOT_FOUND")
+	}
+
+	if err := s.checkClubPermission(ctx, subject, joinRequest.ClubID, PermManageMembers); err != nil {
+		return nil, err
+	}
+
+	if joinRequest.Status != repo.JoinRequestStatusPending {
+		return nil, status.Error(codes.FailedPrecondition, "JOIN_REQUEST_NOT_PENDING")
+	}
+
+	newStatus := repo.JoinRequestStatusRejected
+	if req.Approve {
+		newStatus = repo.JoinRequestStatusApproved
+	}
+
+	now := time.Now().UTC()
+	reviewed, err := s.JoinRequests.Review(ctx, req.RequestId, newStatus, subject.GetEmail(), req.Note, now)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, "JOIN_REQUEST_NOT_PENDING")
+	}
+
+	resp := &pb.ReviewJoinRequestResponse{}
+	if req.Approve {
+		clubObjID, err := primitive.ObjectIDFromHex(reviewed.ClubID)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "INVALID_CLUB_ID")
+		}
+
+		isMember, err := s.PlayerRepo.IsClubMember(ctx, reviewed.Email, reviewed.ClubID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "MEMBERSHIP_CHECK_FAILED")
+		}
+
+		membership := &repo.ClubMembership{
+			ClubID:   clubObjID,
+			Role:     repo.ClubRoleMember,
+			JoinedAt: now,
+		}
+		if !isMember {
+			if err := s.PlayerRepo.AddClubMembership(ctx, reviewed.Email, membership); err != nil {
+				// Put the request back in the queue so the approval can be retried
+				if err := s.JoinRequests.Reopen(ctx, req.RequestId); err != nil {
+					log.Error().Err(err).Str("requestID", req.RequestId).Msg("Failed to reopen join request")
+				}
+				return nil, status.Error(codes.Internal, "FAILED_TO_ADD_MEMBERSHIP")
+			}
+		}
+
+		resp.Membership = &pb.ClubMembership{
+			ClubId:   reviewed.ClubID,
+			Role:     pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER,
+			JoinedAt: timestamppb.New(membership.JoinedAt),
+		}
+	}
+
+	s.auditJoinRequest(ctx, reviewed, "club.join_request."+newStatus, subject.GetEmail(), "admin")
+
+	player, _ := s.PlayerRepo.FindByID(ctx, reviewed.PlayerID)
+	resp.JoinRequest = joinRequestToProto(reviewed, player)
+	return resp, nil
+}
+
 // LeaveClub allows a user to leave a club
 func (s *ClubMembershipService) LeaveClub(ctx context.Context, req *pb.LeaveClubRequest) (*pb.LeaveClubResponse, error) {
 	// Get authenticated user
@@ -113,12 +257,12 @@ func (s *ClubMembershipService) LeaveClub(ctx context.Context, req *pb.LeaveClub
 	targetEmail := strings.ToLower(strings.TrimSpace(targetPlayer.Email))
 
 	if userEmail != targetEmail {
-		// Check if user is club admin
-		isAdmin, err := subject.IsClubAdmin(ctx, req.ClubId)
+		// Check if user may manage members of the club
+		canManage, err := subject.HasClubPermission(ctx, req.ClubId, PermManageMembers)
 		if err != nil {
 			return nil, status.Error(codes.Internal, "ADMIN_CHECK_FAILED")
 		}
-		if !isAdmin {
+		if !canManage {
 			return nil, status.Error(codes.PermissionDenied, "CAN_ONLY_REMOVE_SELF_OR_AS_ADMIN")
 		}
 	}
@@ -147,20 +291,8 @@ func (s *ClubMembershipService) InvitePlayer(ctx context.Context, req *pb.Invite
 		return nil, status.Error(codes.InvalidArgument, "CLUB_ID_AND_EMAIL_REQUIRED")
 	}
 
-	// Check if user is club admin or platform owner
-	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Internal, "PLATFORM_OWNER_CHECK_FAILED")
-	}
-
-	if !isPlatformOwner {
-		isAdmin, err := subject.IsClubAdmin(ctx, req.ClubId)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "ADMIN_CHECK_FAILED")
-		}
-		if !isAdmin {
-			return nil, status.Error(codes.PermissionDenied, "ADMIN_REQUIRED")
-		}
+	if err := s.checkClubPermission(ctx, subject, req.ClubId, PermManageMembers); err != nil {
+		return nil, err
 	}
 
 	// Check if club exists
@@ -169,16 +301,6 @@ func (s *ClubMembershipService) InvitePlayer(ctx context.Context, req *pb.Invite
 		return nil, status.Error(codes.NotFound, "CLUB_NOT_FOUND")
 	}
 
-	// Find or create target player
-	_, err = s.PlayerRepo.FindByEmail(ctx, req.Email)
-	if err != nil {
-		// Player doesn't exist, create them
-		_, err = s.PlayerRepo.CreateWithEmail(ctx, req.Email, "", "", "")
-		if err != nil {
-			return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PLAYER")
-		}
-	}
-
 	// Check if already a member
 	isMember, err := s.PlayerRepo.IsClubMember(ctx, req.Email, req.ClubId)
 	if err != nil {
@@ -188,45 +310,218 @@ func (s *ClubMembershipService) InvitePlayer(ctx context.Context, req *pb.Invite
 		return nil, status.Error(codes.AlreadyExists, "ALREADY_MEMBER")
 	}
 
-	// Create membership
-	clubObjID, err := primitive.ObjectIDFromHex(req.ClubId)
+	now := time.Now().UTC()
+
+	// Only one open invitation per address; an expired one is superseded
+	if existing, err := s.Invitations.FindPending(ctx, req.ClubId, req.Email); err == nil {
+		if !existing.IsExpired(now) {
+			return nil, status.Error(codes.AlreadyExists, "INVITATION_ALREADY_PENDING")
+		}
+		_, _ = s.Invitations.Respond(ctx, existing.ID.Hex(), repo.InvitationStatusExpired, now)
+	}
+
+	role := membershipRoleFromProto(req.Role)
+
+	invitation, err := s.Invitations.Create(ctx, &repo.ClubInvitation{
+		ClubID:     req.ClubId,
+		Email:      req.Email,
+		Role:       role,
+		InvitedBy:  subject.GetEmail(),
+		Status:     repo.InvitationStatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(invitationTTL),
+		LastSentAt: now,
+		SendCount:  1,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_INVITATION")
+	}
+
+	invitationSent := s.sendInvitation(ctx, invitation, club.Name) == nil
+
+	return &pb.InvitePlayerResponse{
+		Success:        true,
+		InvitationSent: invitationSent,
+		Invitation:     invitationToProto(invitation, club.Name, now),
+	}, nil
+}
+
+// AcceptInvitation turns a pending invitation into a membership
+func (s *ClubMembershipService) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (*pb.AcceptInvitationResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	invitation, err := s.findInviteeInvitation(ctx, subject, req.InvitationId)
+	if err != nil {
+		return nil, err
+	}
+
+	clubObjID, err := primitive.ObjectIDFromHex(invitation.ClubID)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "INVALID_CLUB_ID")
 	}
 
-	role := "member"
-	if req.Role == pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN {
-		role = "admin"
+	// Invitees without a profile get one on acceptance
+	if _, err := s.PlayerRepo.FindByEmail(ctx, invitation.Email); err != nil {
+		if _, err := s.PlayerRepo.CreateWithEmail(ctx, invitation.Email, "", "", ""); err != nil {
+			return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_PLAYER")
+		}
+	}
+
+	isMember, err := s.PlayerRepo.IsClubMember(ctx, invitation.Email, invitation.ClubID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "MEMBERSHIP_CHECK_FAILED")
+	}
+
+	now := time.Now().UTC()
+	accepted, err := s.Invitations.Respond(ctx, req.InvitationId, repo.InvitationStatusAccepted, now)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_NOT_PENDING")
 	}
 
 	membership := &repo.ClubMembership{
 		ClubID:   clubObjID,
-		Role:     role,
-		JoinedAt: time.Now(),
+		Role:     invitation.Role,
+		JoinedAt: now,
+	}
+	if !isMember {
+		if err := s.PlayerRepo.AddClubMembership(ctx, invitation.Email, membership); err != nil {
+			// Leave the invitation open so the invitee can accept it again
+			if err := s.Invitations.Reopen(ctx, req.InvitationId); err != nil {
+				log.Error().Err(err).Str("invitationID", req.InvitationId).Msg("Failed to reopen invitation")
+			}
+			return nil, status.Error(codes.Internal, "FAILED_TO_ADD_MEMBERSHIP")
+		}
+	}
+
+	return &pb.AcceptInvitationResponse{
+		Invitation: invitationToProto(accepted, s.clubName(ctx, accepted.ClubID), now),
+		Membership: &pb.ClubMembership{
+			ClubId:   invitation.ClubID,
+			Role:     membershipRoleToProto(invitation.Role),
+			JoinedAt: timestamppb.New(membership.JoinedAt),
+		},
+	}, nil
+}
+
+// DeclineInvitation lets the invitee turn down a pending invitation
+func (s *ClubMembershipService) DeclineInvitation(ctx context.Context, req *pb.DeclineInvitationRequest) (*pb.DeclineInvitationResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
 	}
 
-	err = s.PlayerRepo.AddClubMembership(ctx, req.Email, membership)
+	if _, err := s.findInviteeInvitation(ctx, subject, req.InvitationId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	declined, err := s.Invitations.Respond(ctx, req.InvitationId, repo.InvitationStatusDeclined, now)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "FAILED_TO_ADD_MEMBERSHIP")
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_NOT_PENDING")
 	}
 
-	// Send invitation email
-	invitationSent := false
-	if s.EmailSvc != nil {
-		err = s.EmailSvc.SendInvitation(ctx, req.Email, club.Name, subject.GetDisplayName())
-		if err != nil {
-			// Email sending failed but don't fail the invitation - membership was already created
-		} else {
-			invitationSent = true
-		}
+	return &pb.DeclineInvitationResponse{
+		Invitation: invitationToProto(declined, s.clubName(ctx, declined.ClubID), now),
+	}, nil
+}
+
+// ResendInvitation sends a fresh magic link and extends the invitation's expiry
+func (s *ClubMembershipService) ResendInvitation(ctx context.Context, req *pb.ResendInvitationRequest) (*pb.ResendInvitationResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
 	}
 
-	return &pb.InvitePlayerResponse{
-		Success:        true,
+	invitation, err := s.Invitations.FindByID(ctx, req.InvitationId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "INVITATION_NOT_FOUND")
+	}
+
+	if err := s.checkClubPermission(ctx, subject, invitation.ClubID, PermManageMembers); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	renewed, err := s.Invitations.Renew(ctx, req.InvitationId, now, now.Add(invitationTTL))
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_NOT_PENDING")
+	}
+
+	clubName := s.clubName(ctx, renewed.ClubID)
+	invitationSent := s.sendInvitation(ctx, renewed, clubName) == nil
+
+	return &pb.ResendInvitationResponse{
+		Invitation:     invitationToProto(renewed, clubName, now),
 		InvitationSent: invitationSent,
 	}, nil
 }
 
+// RevokeInvitation withdraws a pending invitation
+func (s *ClubMembershipService) RevokeInvitation(ctx context.Context, req *pb.RevokeInvitationRequest) (*pb.RevokeInvitationResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	invitation, err := s.Invitations.FindByID(ctx, req.InvitationId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "INVITATION_NOT_FOUND")
+	}
+
+	if err := s.checkClubPermission(ctx, subject, invitation.ClubID, PermManageMembers); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	revoked, err := s.Invitations.Respond(ctx, req.InvitationId, repo.InvitationStatusRevoked, now)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_NOT_PENDING")
+	}
+
+	return &pb.RevokeInvitationResponse{
+		Invitation: invitationToProto(revoked, s.clubName(ctx, revoked.ClubID), now),
+	}, nil
+}
+
+// ListPendingInvitations lists a club's open invitations, or the caller's own when no club is given
+func (s *ClubMembershipService) ListPendingInvitations(ctx context.Context, req *pb.ListPendingInvitationsRequest) (*pb.ListPendingInvitationsResponse, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	now := time.Now().UTC()
+
+	var invitations []*repo.ClubInvitation
+	var err error
+	if req.ClubId != "" {
+		if err := s.checkClubPermission(ctx, subject, req.ClubId, PermViewAdminData); err != nil {
+			return nil, err
+		}
+		invitations, err = s.Invitations.ListPendingByClub(ctx, req.ClubId, now)
+	} else {
+		invitations, err = s.Invitations.ListPendingByEmail(ctx, subject.GetEmail(), now)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_LIST_INVITATIONS")
+	}
+
+	clubNames := map[string]string{}
+	resp := &pb.ListPendingInvitationsResponse{}
+	for _, invitation := range invitations {
+		name, ok := clubNames[invitation.ClubID]
+		if !ok {
+			name = s.clubName(ctx, invitation.ClubID)
+			clubNames[invitation.ClubID] = name
+		}
+		resp.Invitations = append(resp.Invitations, invitationToProto(invitation, name, now))
+	}
+	return resp, nil
+}
+
 // AddPlayerToClub allows a club admin to add a player to their club
 func (s *ClubMembershipService) AddPlayerToClub(ctx context.Context, req *pb.AddPlayerToClubRequest) (*pb.AddPlayerToClubResponse, error) {
 	// Get authenticated user
@@ -240,24 +535,13 @@ func (s *ClubMembershipService) AddPlayerToClub(ctx context.Context, req *pb.Add
 		return nil, status.Error(codes.InvalidArgument, "CLUB_ID_FIRST_NAME_AND_LAST_NAME_REQUIRED")
 	}
 
-	// Check if user is club admin or platform owner
-	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Internal, "PLATFORM_OWNER_CHECK_FAILED")
-	}
-
-	if !isPlatformOwner {
-		isAdmin, err := subject.IsClubAdmin(ctx, req.ClubId)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "ADMIN_CHECK_FAILED")
-		}
-		if !isAdmin {
-			return nil, status.Error(codes.PermissionDenied, "ADMIN_REQUIRED")
-		}
+	// Check if user may manage members of the club
+	if err := s.checkClubPermission(ctx, subject, req.ClubId, PermManageMembers); err != nil {
+		return nil, err
 	}
 
 	// Check if club exists
-	_, err = s.ClubRepo.FindByID(ctx, req.ClubId)
+	_, err := s.ClubRepo.FindByID(ctx, req.ClubId)
 	if err != nil {
 		return nil, status.Error(codes.NotFound, "CLUB_NOT_FOUND")
 	}
@@ -353,7 +637,7 @@ func (s *ClubMembershipService) AddPlayerToClub(ctx context.Context, req *pb.Add
 
 		membership := &repo.ClubMembership{
 			ClubID:   clubObjID,
-			Role:     "member", // Always add as member initially
+			Role:     repo.ClubRoleMember, // Always add as member initially
 			JoinedAt: time.Now(),
 		}
 
@@ -403,7 +687,7 @@ func (s *ClubMembershipService) AddPlayerToClub(ctx context.Context, req *pb.Add
 		_, err = s.TokenRepo.CreateMagicLinkTokenWithExpiry(ctx, magicToken, req.Email, "", 24*time.Hour)
 		if err == nil {
 			// Send club invitation magic link email with inviter and club context
-			err = s.EmailSvc.SendClubInvitationMagicLink(ctx, req.Email, magicToken, returnURL, clubName, inviterName, inviterEmail)
+			err = s.EmailSvc.SendClubInvitationMagicLink(ctx, req.Email, playerLocale(ctx, s.PlayerRepo, req.Email), magicToken, returnURL, clubName, inviterName, inviterEmail)
 		}
 
 		if err != nil {
@@ -438,20 +722,9 @@ func (s *ClubMembershipService) UpdateMemberRole(ctx context.Context, req *pb.Up
 		return nil, status.Error(codes.InvalidArgument, "CLUB_ID_AND_PLAYER_ID_REQUIRED")
 	}
 
-	// Check if user is club admin or platform owner
-	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
-	if err != nil {
-		return nil, status.Error(codes.Internal, "PLATFORM_OWNER_CHECK_FAILED")
-	}
-
-	if !isPlatformOwner {
-		isAdmin, err := subject.IsClubAdmin(ctx, req.ClubId)
-		if err != nil {
-			return nil, status.Error(codes.Internal, "ADMIN_CHECK_FAILED")
-		}
-		if !isAdmin {
-			return nil, status.Error(codes.PermissionDenied, "ADMIN_REQUIRED")
-		}
+	// Check if user may manage members of the club
+	if err := s.checkClubPermission(ctx, subject, req.ClubId, PermManageMembers); err != nil {
+		return nil, err
 	}
 
 	// Validate target player exists
@@ -461,10 +734,7 @@ func (s *ClubMembershipService) UpdateMemberRole(ctx context.Context, req *pb.Up
 	}
 
 	// Convert role
-	role := "member"
-	if req.Role == pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN {
-		role = "admin"
-	}
+	role := membershipRoleFromProto(req.Role)
 
 	// Update role
 	err = s.PlayerRepo.UpdateClubMembershipRole(ctx, req.PlayerId, req.ClubId, role)
@@ -472,6 +742,21 @@ func (s *ClubMembershipService) UpdateMemberRole(ctx context.Context, req *pb.Up
 		return nil, status.Error(codes.Internal, "FAILED_TO_UPDATE_ROLE")
 	}
 
+	if s.Audit != nil {
+		s.Audit.LogEvent(ctx, audit.AuditEvent{
+			Type:       audit.EventAuthzRoleChanged,
+			Action:     "club.member.role_changed",
+			ActorID:    subject.GetEmail(),
+			ActorType:  "admin",
+			TargetID:   req.PlayerId,
+			TargetType: "player",
+			ClubID:     req.ClubId,
+			Result:     "SUCCESS",
+			Message:    fmt.Sprintf("Role of %s set to %s", targetPlayer.DisplayName, role),
+			Details:    map[string]interface{}{"role": role},
+		})
+	}
+
 	// Get updated membership for response
 	memberships, err := s.PlayerRepo.GetPlayerMemberships(ctx, req.PlayerId, true)
 	if err != nil {
@@ -482,10 +767,7 @@ func (s *ClubMembershipService) UpdateMemberRole(ctx context.Context, req *pb.Up
 	var updatedMembership *pb.ClubMembership
 	for _, membership := range memberships {
 		if membership.ClubID.Hex() == req.ClubId {
-			pbRole := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-			if membership.Role == "admin" {
-				pbRole = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-			}
+			pbRole := membershipRoleToProto(membership.Role)
 
 			updatedMembership = &pb.ClubMembership{
 				ClubId:   req.ClubId,
@@ -496,9 +778,6 @@ func (s *ClubMembershipService) UpdateMemberRole(ctx context.Context, req *pb.Up
 		}
 	}
 
-	// Use target player details for response
-	_ = targetPlayer.Email // Avoid unused variable error
-
 	return &pb.UpdateMemberRoleResponse{
 		Success:    true,
 		Membership: updatedMembership,
@@ -512,6 +791,10 @@ func (s *ClubMembershipService) ListClubMembers(ctx context.Context, req *pb.Lis
 		return nil, status.Error(codes.InvalidArgument, "CLUB_ID_REQUIRED")
 	}
 
+	if err := requireClubPermission(ctx, req.ClubId, PermViewMembers); err != nil {
+		return nil, err
+	}
+
 	// Check if club exists
 	_, err := s.ClubRepo.FindByID(ctx, req.ClubId)
 	if err != nil {
@@ -530,10 +813,7 @@ func (s *ClubMembershipService) ListClubMembers(ctx context.Context, req *pb.Lis
 		// Find the relevant membership
 		for _, membership := range player.ClubMemberships {
 			if membership.ClubID.Hex() == req.ClubId {
-				pbRole := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-				if membership.Role == "admin" {
-					pbRole = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-				}
+				pbRole := membershipRoleToProto(membership.Role)
 
 				pbMembership := &pb.ClubMembership{
 					ClubId:   req.ClubId,
@@ -591,10 +871,7 @@ func (s *ClubMembershipService) ListPlayerMemberships(ctx context.Context, req *
 			continue // Skip if club not found
 		}
 
-		pbRole := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-		if membership.Role == "admin" {
-			pbRole = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-		}
+		pbRole := membershipRoleToProto(membership.Role)
 
 		pbMembership := &pb.ClubMembership{
 			ClubId:   membership.ClubID.Hex(),
@@ -613,3 +890,199 @@ func (s *ClubMembershipService) ListPlayerMemberships(ctx context.Context, req *
 		Memberships: pbMemberships,
 	}, nil
 }
+
+// checkClubPermission verifies that the subject is platform owner or holds the
+// permission through their role in the club
+func (s *ClubMembershipService) checkClubPermission(ctx context.Context, subject Subject, clubID string, permission Permission) error {
+	allowed, err := subject.HasClubPermission(ctx, clubID, permission)
+	if err != nil {
+		return status.Error(codes.Internal, "ADMIN_CHECK_FAILED")
+	}
+	if !allowed {
+		return status.Error(codes.PermissionDenied, "ADMIN_REQUIRED")
+	}
+	return nil
+}
+
+// findInviteeInvitation loads an answerable invitation addressed to the subject
+func (s *ClubMembershipService) findInviteeInvitation(ctx context.Context, subject Subject, invitationID string) (*repo.ClubInvitation, error) {
+	invitation, err := s.Invitations.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "INVITATION_NOT_FOUND")
+	}
+
+	// Don't reveal invitations addressed to someone else
+	if !strings.EqualFold(invitation.Email, subject.GetEmail()) {
+		return nil, status.Error(codes.NotFound, "INVITATION_NOT_FOUND")
+	}
+	if invitation.Status != repo.InvitationStatusPending {
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_NOT_PENDING")
+	}
+	if invitation.IsExpired(time.Now().UTC()) {
+		return nil, status.Error(codes.FailedPrecondition, "INVITATION_EXPIRED")
+	}
+	return invitation, nil
+}
+
+// sendInvitation emails a magic link that signs the invitee in and opens the invitation
+func (s *ClubMembershipService) sendInvitation(ctx context.Context, invitation *repo.ClubInvitation, clubName string) error {
+	if s.EmailSvc == nil || s.TokenRepo == nil {
+		return fmt.Errorf("email not configured")
+	}
+
+	magicToken := uuid.New().String()
+	if _, err := s.TokenRepo.CreateMagicLinkTokenWithExpiry(ctx, magicToken, invitation.Email, "", 24*time.Hour); err != nil {
+		return err
+	}
+
+	returnURL := fmt.Sprintf("/invitations/%s", invitation.ID.Hex())
+	return s.EmailSvc.SendClubInvitationMagicLink(ctx, invitation.Email, playerLocale(ctx, s.PlayerRepo, invitation.Email), magicToken, returnURL, clubName, s.displayNameForEmail(ctx, invitation.InvitedBy), invitation.InvitedBy)
+}
+
+// displayNameForEmail returns the best display name for a player, falling back to the address
+func (s *ClubMembershipService) displayNameForEmail(ctx context.Context, address string) string {
+	player, err := s.PlayerRepo.FindByEmail(ctx, address)
+	if err != nil || player == nil {
+		return address
+	}
+	if player.DisplayName != "" {
+		return player.DisplayName
+	}
+	if player.FirstName != "" || player.LastName != "" {
+		return strings.TrimSpace(player.FirstName + " " + player.LastName)
+	}
+	return address
+}
+
+func (s *ClubMembershipService) clubName(ctx context.Context, clubID string) string {
+	club, err := s.ClubRepo.FindByID(ctx, clubID)
+	if err != nil || club == nil {
+		return ""
+	}
+	return club.Name
+}
+
+func invitationToProto(invitation *repo.ClubInvitation, clubName string, now time.Time) *pb.ClubInvitation {
+	invitationStatus := invitationStatusToProto[invitation.Status]
+	if invitation.IsExpired(now) {
+		invitationStatus = pb.InvitationStatus_INVITATION_STATUS_EXPIRED
+	}
+
+	return &pb.ClubInvitation{
+		Id:         invitation.ID.Hex(),
+		ClubId:     invitation.ClubID,
+		ClubName:   clubName,
+		Email:      invitation.Email,
+		Role:       membershipRoleToProto(invitation.Role),
+		InvitedBy:  invitation.InvitedBy,
+		Status:     invitationStatus,
+		CreatedAt:  timestamppb.New(invitation.CreatedAt),
+		ExpiresAt:  timestamppb.New(invitation.ExpiresAt),
+		LastSentAt: timestamppb.New(invitation.LastSentAt),
+	}
+}
+
+// requestToJoin records a join request for a request-to-join club and notifies its admins
+func (s *ClubMembershipService) requestToJoin(ctx context.Context, subject Subject, club *repo.Club, message string) (*pb.ClubJoinRequest, error) {
+	clubID := club.ID.Hex()
+
+	if _, err := s.JoinRequests.FindPending(ctx, clubID, subject.GetEmail()); err == nil {
+		return nil, status.Error(codes.AlreadyExists, "JOIN_REQUEST_ALREADY_PENDING")
+	}
+
+	player, err := s.PlayerRepo.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+
+	joinRequest, err := s.JoinRequests.Create(ctx, &repo.ClubJoinRequest{
+		ClubID:    clubID,
+		PlayerID:  player.ID.Hex(),
+		Email:     subject.GetEmail(),
+		Message:   message,
+		Status:    repo.JoinRequestStatusPending,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_CREATE_JOIN_REQUEST")
+	}
+
+	s.auditJoinRequest(ctx, joinRequest, "club.join_request.created", subject.GetEmail(), "player")
+	s.notifyAdminsOfJoinRequest(ctx, club, player)
+
+	return joinRequestToProto(joinRequest, player), nil
+}
+
+// notifyAdminsOfJoinRequest emails every club admin; failures are logged and ignored
+func (s *ClubMembershipService) notifyAdminsOfJoinRequest(ctx context.Context, club *repo.Club, requester *repo.Player) {
+	if s.EmailSvc == nil {
+		return
+	}
+
+	members, err := s.PlayerRepo.ListClubMembers(ctx, club.ID.Hex(), true)
+	if err != nil {
+		log.Warn().Err(err).Str("club_id", club.ID.Hex()).Msg("Failed to load club admins for join request notification")
+		return
+	}
+
+	requesterName := s.displayNameForEmail(ctx, requester.Email)
+	for _, member := range members {
+		for _, membership := range member.ClubMemberships {
+			if membership.ClubID != club.ID || membership.Role != repo.ClubRoleAdmin || member.Email == "" {
+				continue
+			}
+			if err := s.EmailSvc.SendJoinRequestNotification(ctx, member.Email, member.Locale, club.ID.Hex(), club.Name, requesterName, requester.Email); err != nil {
+				log.Warn().Err(err).Str("club_id", club.ID.Hex()).Msg("Failed to send join request notification")
+			}
+			break
+		}
+	}
+}
+
+func (s *ClubMembershipService) auditJoinRequest(ctx context.Context, joinRequest *repo.ClubJoinRequest, action, actorID, actorType string) {
+	if s.Audit == nil {
+		return
+	}
+
+	eventType := audit.EventDataUpdate
+	if joinRequest.Status == repo.JoinRequestStatusPending {
+		eventType = audit.EventDataCreate
+	}
+
+	s.Audit.LogEvent(ctx, audit.AuditEvent{
+		Type:       eventType,
+		Action:     action,
+		ActorID:    actorID,
+		ActorType:  actorType,
+		TargetID:   joinRequest.ID.Hex(),
+		TargetType: "club_join_request",
+		ClubID:     joinRequest.ClubID,
+		Result:     strings.ToUpper(joinRequest.Status),
+		Message:    fmt.Sprintf("Join request by %s is %s", joinRequest.Email, joinRequest.Status),
+		Details: map[string]interface{}{
+			"player_id":   joinRequest.PlayerID,
+			"review_note": joinRequest.ReviewNote,
+		},
+	})
+}
+
+func joinRequestToProto(joinRequest *repo.ClubJoinRequest, player *repo.Player) *pb.ClubJoinRequest {
+	out := &pb.ClubJoinRequest{
+		Id:         joinRequest.ID.Hex(),
+		ClubId:     joinRequest.ClubID,
+		PlayerId:   joinRequest.PlayerID,
+		Email:      joinRequest.Email,
+		Message:    joinRequest.Message,
+		Status:     joinRequestStatusToProto[joinRequest.Status],
+		CreatedAt:  timestamppb.New(joinRequest.CreatedAt),
+		ReviewedBy: joinRequest.ReviewedBy,
+		ReviewNote: joinRequest.ReviewNote,
+	}
+	if player != nil {
+		out.DisplayName = player.DisplayName
+	}
+	if joinRequest.ReviewedAt != nil {
+		out.ReviewedAt = timestamppb.New(*joinRequest.ReviewedAt)
+	}
+	return out
+}
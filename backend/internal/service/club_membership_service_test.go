@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// newMembershipService wires the membership service to the mock database
+func newMembershipService(mt *mtest.T) *ClubMembershipService {
+	mt.AddMockResponses(
+		mtest.CreateSuccessResponse(), // Player indexes
+		mtest.CreateSuccessResponse(), // Invitation indexes
+		mtest.CreateSuccessResponse(), // Join request indexes
+	)
+	return &ClubMembershipService{
+		PlayerRepo:   repo.NewPlayerRepo(mt.DB),
+		ClubRepo:     repo.NewClubRepo(mt.DB),
+		Invitations:  repo.NewClubInvitationRepo(mt.DB),
+		JoinRequests: repo.NewClubJoinRequestRepo(mt.DB),
+	}
+}
+
+func invitationDoc(id, clubID primitive.ObjectID, invitationStatus string, expiresAt time.Time) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "club_id", Value: clubID.Hex()},
+		{Key: "email", Value: "anna@example.com"},
+		{Key: "role", Value: repo.ClubRoleMember},
+		{Key: "invited_by", Value: "admin@example.com"},
+		{Key: "status", Value: invitationStatus},
+		{Key: "expires_at", Value: expiresAt},
+		{Key: "send_count", Value: int32(1)},
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	invitee := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+
+	mt.Run("adds the membership", func(mt *mtest.T) {
+		id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+		expiresAt := time.Now().Add(24 * time.Hour)
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusPending, expiresAt)),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch, bson.D{{Key: "email", Value: "anna@example.com"}}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch), // Not a member yet
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: invitationDoc(id, clubID, repo.InvitationStatusAccepted, expiresAt)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch, bson.D{{Key: "_id", Value: clubID}, {Key: "name", Value: "BTK"}}),
+		)
+
+		resp, err := svc.AcceptInvitation(invitee, &pb.AcceptInvitationRequest{InvitationId: id.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.InvitationStatus_INVITATION_STATUS_ACCEPTED, resp.GetInvitation().GetStatus())
+		require.Equal(mt, "BTK", resp.GetInvitation().GetClubName())
+		require.Equal(mt, clubID.Hex(), resp.GetMembership().GetClubId())
+		require.Equal(mt, pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER, resp.GetMembership().GetRole())
+	})
+
+	mt.Run("reopens the invitation when the membership cannot be added", func(mt *mtest.T) {
+		id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+		expiresAt := time.Now().Add(24 * time.Hour)
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusPending, expiresAt)),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch, bson.D{{Key: "email", Value: "anna@example.com"}}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: invitationDoc(id, clubID, repo.InvitationStatusAccepted, expiresAt)}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 91, Message: "shutting down"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		mt.ClearEvents()
+
+		_, err := svc.AcceptInvitation(invitee, &pb.AcceptInvitationRequest{InvitationId: id.Hex()})
+		require.Equal(mt, codes.Internal, status.Code(err))
+		require.Equal(mt, "FAILED_TO_ADD_MEMBERSHIP", status.Convert(err).Message())
+
+		var reopen bson.Raw
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == "club_invitations" {
+				reopen = event.Command
+			}
+		}
+		require.NotNil(mt, reopen)
+		require.Equal(mt, repo.InvitationStatusAccepted, reopen.Lookup("updates", "0", "q", "status").StringValue())
+		require.Equal(mt, repo.InvitationStatusPending, reopen.Lookup("updates", "0", "u", "$set", "status").StringValue())
+	})
+
+	mt.Run("expired", func(mt *mtest.T) {
+		id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".club_invitations", mtest.FirstBatch,
+			invitationDoc(id, clubID, repo.InvitationStatusPending, time.Now().Add(-time.Hour)),
+		))
+
+		_, err := svc.AcceptInvitation(invitee, &pb.AcceptInvitationRequest{InvitationId: id.Hex()})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "INVITATION_EXPIRED", status.Convert(err).Message())
+	})
+
+	mt.Run("addressed to someone else", func(mt *mtest.T) {
+		id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".club_invitations", mtest.FirstBatch,
+			invitationDoc(id, clubID, repo.InvitationStatusPending, time.Now().Add(time.Hour)),
+		))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "bo@example.com"})
+		_, err := svc.AcceptInvitation(ctx, &pb.AcceptInvitationRequest{InvitationId: id.Hex()})
+		require.Equal(mt, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestDeclineInvitation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("decline", func(mt *mtest.T) {
+		id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+		expiresAt := time.Now().Add(24 * time.Hour)
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusPending, expiresAt)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: invitationDoc(id, clubID, repo.InvitationStatusDeclined, expiresAt)}),
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch),
+		)
+		mt.ClearEvents()
+
+		ctx := WithSubject(context.Background(), testSubject{email: "Anna@Example.com"})
+		resp, err := svc.DeclineInvitation(ctx, &pb.DeclineInvitationRequest{InvitationId: id.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.InvitationStatus_INVITATION_STATUS_DECLINED, resp.GetInvitation().GetStatus())
+
+		mt.GetStartedEvent()
+		respond := mt.GetStartedEvent().Command
+		require.Equal(mt, repo.InvitationStatusPending, respond.Lookup("query", "status").StringValue())
+		require.Equal(mt, repo.InvitationStatusDeclined, respond.Lookup("update", "$set", "status").StringValue())
+	})
+}
+
+func TestResendInvitation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+	admin := WithSubject(context.Background(), testSubject{email: "admin@example.com", roles: map[string]string{clubID.Hex(): repo.ClubRoleAdmin}})
+
+	mt.Run("extends the expiry", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		renewed := invitationDoc(id, clubID, repo.InvitationStatusPending, time.Now().Add(invitationTTL))
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusPending, time.Now().Add(-time.Hour))),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: renewed}),
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch),
+		)
+		mt.ClearEvents()
+
+		start := time.Now()
+		resp, err := svc.ResendInvitation(admin, &pb.ResendInvitationRequest{InvitationId: id.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.InvitationStatus_INVITATION_STATUS_PENDING, resp.GetInvitation().GetStatus())
+		require.False(mt, resp.GetInvitationSent()) // No email service configured
+
+		mt.GetStartedEvent()
+		renew := mt.GetStartedEvent().Command
+		require.Equal(mt, int32(1), renew.Lookup("update", "$inc", "send_count").Int32())
+		require.WithinDuration(mt, start.Add(invitationTTL), renew.Lookup("update", "$set", "expires_at").Time(), time.Minute)
+	})
+
+	mt.Run("requires a member manager", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".club_invitations", mtest.FirstBatch,
+			invitationDoc(id, clubID, repo.InvitationStatusPending, time.Now().Add(time.Hour)),
+		))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "coach@example.com", roles: map[string]string{clubID.Hex(): repo.ClubRoleCoach}})
+		_, err := svc.ResendInvitation(ctx, &pb.ResendInvitationRequest{InvitationId: id.Hex()})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestRevokeInvitation(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	id, clubID := primitive.NewObjectID(), primitive.NewObjectID()
+	admin := WithSubject(context.Background(), testSubject{email: "admin@example.com", roles: map[string]string{clubID.Hex(): repo.ClubRoleAdmin}})
+
+	mt.Run("revoke", func(mt *mtest.T) {
+		expiresAt := time.Now().Add(24 * time.Hour)
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusPending, expiresAt)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: invitationDoc(id, clubID, repo.InvitationStatusRevoked, expiresAt)}),
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch),
+		)
+
+		resp, err := svc.RevokeInvitation(admin, &pb.RevokeInvitationRequest{InvitationId: id.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.InvitationStatus_INVITATION_STATUS_REVOKED, resp.GetInvitation().GetStatus())
+	})
+
+	mt.Run("already answered", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".club_invitations", mtest.FirstBatch, invitationDoc(id, clubID, repo.InvitationStatusAccepted, time.Now())),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+		)
+
+		_, err := svc.RevokeInvitation(admin, &pb.RevokeInvitationRequest{InvitationId: id.Hex()})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "INVITATION_NOT_PENDING", status.Convert(err).Message())
+	})
+}
+
+func TestInvitationExpiry(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	pending := &repo.ClubInvitation{Status: repo.InvitationStatusPending, ExpiresAt: now}
+	require.True(t, pending.IsExpired(now))
+	require.False(t, pending.IsExpired(now.Add(-time.Second)))
+	require.Equal(t, pb.InvitationStatus_INVITATION_STATUS_EXPIRED, invitationToProto(pending, "", now).GetStatus())
+
+	accepted := &repo.ClubInvitation{Status: repo.InvitationStatusAccepted, ExpiresAt: now}
+	require.False(t, accepted.IsExpired(now))
+	require.Equal(t, pb.InvitationStatus_INVITATION_STATUS_ACCEPTED, invitationToProto(accepted, "", now).GetStatus())
+}
+
+func TestJoinClubFollowsJoinPolicy(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	clubID := primitive.NewObjectID()
+	player := WithSubject(context.Background(), testSubject{email: "anna@example.com"})
+	club := func(policy string) bson.D {
+		return bson.D{{Key: "_id", Value: clubID}, {Key: "name", Value: "BTK"}, {Key: "join_policy", Value: policy}}
+	}
+
+	mt.Run("open", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".clubs", mtest.FirstBatch, club(repo.ClubJoinPolicyOpen)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+
+		resp, err := svc.JoinClub(player, &pb.JoinClubRequest{ClubId: clubID.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, clubID.Hex(), resp.GetMembership().GetClubId())
+		require.Nil(mt, resp.GetJoinRequest())
+	})
+
+	mt.Run("request", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		playerID := primitive.NewObjectID()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch, club(repo.ClubJoinPolicyRequest)),
+			mtest.CreateCursorResponse(0, db+".club_join_requests", mtest.FirstBatch), // No pending request
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch, bson.D{{Key: "_id", Value: playerID}, {Key: "email", Value: "anna@example.com"}}),
+			mtest.CreateSuccessResponse(),
+		)
+		mt.ClearEvents()
+
+		resp, err := svc.JoinClub(player, &pb.JoinClubRequest{ClubId: clubID.Hex(), Message: "Hej!"})
+		require.NoError(mt, err)
+		require.Nil(mt, resp.GetMembership())
+		require.Equal(mt, pb.JoinRequestStatus_JOIN_REQUEST_STATUS_PENDING, resp.GetJoinRequest().GetStatus())
+		require.Equal(mt, playerID.Hex(), resp.GetJoinRequest().GetPlayerId())
+
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			require.NotEqual(mt, "update", event.CommandName, "no membership may be added")
+		}
+	})
+
+	mt.Run("request already pending", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".clubs", mtest.FirstBatch, club(repo.ClubJoinPolicyRequest)),
+			mtest.CreateCursorResponse(0, db+".club_join_requests", mtest.FirstBatch, bson.D{{Key: "status", Value: repo.JoinRequestStatusPending}}),
+		)
+
+		_, err := svc.JoinClub(player, &pb.JoinClubRequest{ClubId: clubID.Hex()})
+		require.Equal(mt, codes.AlreadyExists, status.Code(err))
+		require.Equal(mt, "JOIN_REQUEST_ALREADY_PENDING", status.Convert(err).Message())
+	})
+
+	mt.Run("invite only", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".clubs", mtest.FirstBatch, club(repo.ClubJoinPolicyInviteOnly)))
+
+		_, err := svc.JoinClub(player, &pb.JoinClubRequest{ClubId: clubID.Hex()})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "CLUB_INVITE_ONLY", status.Convert(err).Message())
+	})
+}
+
+func TestReviewJoinRequest(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	requestID, clubID, playerID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	admin := WithSubject(context.Background(), testSubject{email: "admin@example.com", roles: map[string]string{clubID.Hex(): repo.ClubRoleAdmin}})
+	joinRequest := func(requestStatus string) bson.D {
+		return bson.D{
+			{Key: "_id", Value: requestID},
+			{Key: "club_id", Value: clubID.Hex()},
+			{Key: "player_id", Value: playerID.Hex()},
+			{Key: "email", Value: "anna@example.com"},
+			{Key: "status", Value: requestStatus},
+		}
+	}
+
+	mt.Run("approve", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_join_requests", mtest.FirstBatch, joinRequest(repo.JoinRequestStatusPending)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: joinRequest(repo.JoinRequestStatusApproved)}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch), // Not a member yet
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch, bson.D{{Key: "_id", Value: playerID}, {Key: "display_name", Value: "Anna"}}),
+		)
+
+		resp, err := svc.ReviewJoinRequest(admin, &pb.ReviewJoinRequestRequest{RequestId: requestID.Hex(), Approve: true})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.JoinRequestStatus_JOIN_REQUEST_STATUS_APPROVED, resp.GetJoinRequest().GetStatus())
+		require.Equal(mt, clubID.Hex(), resp.GetMembership().GetClubId())
+	})
+
+	mt.Run("reject", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_join_requests", mtest.FirstBatch, joinRequest(repo.JoinRequestStatusPending)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: joinRequest(repo.JoinRequestStatusRejected)}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch, bson.D{{Key: "_id", Value: playerID}}),
+		)
+
+		resp, err := svc.ReviewJoinRequest(admin, &pb.ReviewJoinRequestRequest{RequestId: requestID.Hex(), Approve: false, Note: "Full"})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.JoinRequestStatus_JOIN_REQUEST_STATUS_REJECTED, resp.GetJoinRequest().GetStatus())
+		require.Nil(mt, resp.GetMembership())
+	})
+
+	mt.Run("reopens the request when the membership cannot be added", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".club_join_requests", mtest.FirstBatch, joinRequest(repo.JoinRequestStatusPending)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: joinRequest(repo.JoinRequestStatusApproved)}),
+			mtest.CreateCursorResponse(0, db+".players", mtest.FirstBatch),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 91, Message: "shutting down"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		mt.ClearEvents()
+
+		_, err := svc.ReviewJoinRequest(admin, &pb.ReviewJoinRequestRequest{RequestId: requestID.Hex(), Approve: true})
+		require.Equal(mt, codes.Internal, status.Code(err))
+		require.Equal(mt, "FAILED_TO_ADD_MEMBERSHIP", status.Convert(err).Message())
+
+		var reopen bson.Raw
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == "club_join_requests" {
+				reopen = event.Command
+			}
+		}
+		require.NotNil(mt, reopen)
+		require.Equal(mt, repo.JoinRequestStatusApproved, reopen.Lookup("updates", "0", "q", "status").StringValue())
+		require.Equal(mt, repo.JoinRequestStatusPending, reopen.Lookup("updates", "0", "u", "$set", "status").StringValue())
+	})
+
+	mt.Run("requires a member manager", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".club_join_requests", mtest.FirstBatch, joinRequest(repo.JoinRequestStatusPending)))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "anna@example.com", clubs: []string{clubID.Hex()}, roles: map[string]string{clubID.Hex(): repo.ClubRoleMember}})
+		_, err := svc.ReviewJoinRequest(ctx, &pb.ReviewJoinRequestRequest{RequestId: requestID.Hex(), Approve: true})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+	})
+
+	mt.Run("already reviewed", func(mt *mtest.T) {
+		svc := newMembershipService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".club_join_requests", mtest.FirstBatch, joinRequest(repo.JoinRequestStatusApproved)))
+
+		_, err := svc.ReviewJoinRequest(admin, &pb.ReviewJoinRequestRequest{RequestId: requestID.Hex(), Approve: true})
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "JOIN_REQUEST_NOT_PENDING", status.Convert(err).Message())
+	})
+}
@@ -56,10 +56,17 @@ func (s *ClubService) CreateClub(ctx context.Context, in *pb.CreateClubRequest)
 		return nil, status.Error(codes.Internal, "CLUB_CREATE_FAILED")
 	}
 
+	if policy := joinPolicyFromProto(in.GetJoinPolicy()); policy != repo.ClubJoinPolicyOpen {
+		club, err = s.Clubs.Update(ctx, club.ID.Hex(), map[string]interface{}{"join_policy": policy})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "CLUB_CREATE_FAILED")
+		}
+	}
+
 	// Add the creator as a club admin member
 	membership := &repo.ClubMembership{
 		ClubID:   club.ID,
-		Role:     "admin",
+		Role:     repo.ClubRoleAdmin,
 		JoinedAt: time.Now(),
 	}
 
@@ -72,12 +79,7 @@ func (s *ClubService) CreateClub(ctx context.Context, in *pb.CreateClubRequest)
 	}
 
 	return &pb.CreateClubResponse{
-		Club: &pb.Club{
-			Id:              club.ID.Hex(),
-			Name:            club.Name,
-			SupportedSports: pbSupportedSports(club.SupportedSports),
-			SeriesSports:    pbSeriesSports(nil),
-		},
+		Club: clubToProto(club, nil),
 	}, nil
 }
 
@@ -93,12 +95,7 @@ func (s *ClubService) GetClub(ctx context.Context, in *pb.GetClubRequest) (*pb.G
 	}
 
 	return &pb.GetClubResponse{
-		Club: &pb.Club{
-			Id:              club.ID.Hex(),
-			Name:            club.Name,
-			SupportedSports: pbSupportedSports(club.SupportedSports),
-			SeriesSports:    pbSeriesSports(seriesSports[club.ID.Hex()]),
-		},
+		Club: clubToProto(club, seriesSports[club.ID.Hex()]),
 	}, nil
 }
 
@@ -123,6 +120,8 @@ func (s *ClubService) UpdateClub(ctx context.Context, in *pb.UpdateClubRequest)
 					return nil, err
 				}
 				updates["supported_sports"] = sports
+			case "join_policy":
+				updates["join_policy"] = joinPolicyFromProto(in.GetClub().GetJoinPolicy())
 			default:
 				return nil, status.Error(codes.InvalidArgument, "UNSUPPORTED_UPDATE_FIELD")
 			}
@@ -153,12 +152,7 @@ func (s *ClubService) UpdateClub(ctx context.Context, in *pb.UpdateClubRequest)
 	}
 
 	return &pb.UpdateClubResponse{
-		Club: &pb.Club{
-			Id:              club.ID.Hex(),
-			Name:            club.Name,
-			SupportedSports: pbSupportedSports(club.SupportedSports),
-			SeriesSports:    pbSeriesSports(seriesSports[club.ID.Hex()]),
-		},
+		Club: clubToProto(club, seriesSports[club.ID.Hex()]),
 	}, nil
 }
 
@@ -251,12 +245,7 @@ func (s *ClubService) ListClubs(ctx context.Context, in *pb.ListClubsRequest) (*
 	}
 
 	for _, club := range clubs {
-		pbClubs = append(pbClubs, &pb.Club{
-			Id:              club.ID.Hex(),
-			Name:            club.Name,
-			SupportedSports: pbSupportedSports(club.SupportedSports),
-			SeriesSports:    pbSeriesSports(seriesSports[club.ID.Hex()]),
-		})
+		pbClubs = append(pbClubs, clubToProto(club, seriesSports[club.ID.Hex()]))
 	}
 
 	return &pb.ListClubsResponse{
@@ -346,3 +335,31 @@ func pbSeriesSports(values []int32) []pb.Sport {
 	sort.Slice(sports, func(i, j int) bool { return sports[i] < sports[j] })
 	return sports
 }
+
+var joinPolicyToProto = map[string]pb.ClubJoinPolicy{
+	repo.ClubJoinPolicyOpen:       pb.ClubJoinPolicy_CLUB_JOIN_POLICY_OPEN,
+	repo.ClubJoinPolicyRequest:    pb.ClubJoinPolicy_CLUB_JOIN_POLICY_REQUEST,
+	repo.ClubJoinPolicyInviteOnly: pb.ClubJoinPolicy_CLUB_JOIN_POLICY_INVITE_ONLY,
+}
+
+// joinPolicyFromProto maps the API enum to the stored policy; unspecified means open
+func joinPolicyFromProto(policy pb.ClubJoinPolicy) string {
+	switch policy {
+	case pb.ClubJoinPolicy_CLUB_JOIN_POLICY_REQUEST:
+		return repo.ClubJoinPolicyRequest
+	case pb.ClubJoinPolicy_CLUB_JOIN_POLICY_INVITE_ONLY:
+		return repo.ClubJoinPolicyInviteOnly
+	default:
+		return repo.ClubJoinPolicyOpen
+	}
+}
+
+func clubToProto(club *repo.Club, seriesSports []int32) *pb.Club {
+	return &pb.Club{
+		Id:              club.ID.Hex(),
+		Name:            club.Name,
+		SupportedSports: pbSupportedSports(club.SupportedSports),
+		SeriesSports:    pbSeriesSports(seriesSports),
+		JoinPolicy:      joinPolicyToProto[club.EffectiveJoinPolicy()],
+	}
+}
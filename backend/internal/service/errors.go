@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// stableCodePattern matches status messages that are stable error codes such
+// as VALIDATION_SCORE_TIE rather than free text
+var stableCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+
+// fallbackCodes names errors whose status message is not a stable code
+var fallbackCodes = map[codes.Code]string{
+	codes.InvalidArgument:    "VALIDATION_FAILED",
+	codes.OutOfRange:         "VALIDATION_FAILED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.Unauthenticated:    "NOT_AUTHENTICATED",
+	codes.ResourceExhausted:  "RATE_LIMIT_EXCEEDED",
+	codes.FailedPrecondition: "PRECONDITION_FAILED",
+	codes.Unimplemented:      "NOT_IMPLEMENTED",
+	codes.Unavailable:        "SERVICE_UNAVAILABLE",
+	codes.DeadlineExceeded:   "SERVICE_UNAVAILABLE",
+	codes.Internal:           "INTERNAL_ERROR",
+	codes.Unknown:            "INTERNAL_ERROR",
+}
+
+// errorWithArgs returns a status error whose message is the stable code and
+// whose details carry args for interpolation into the localized message
+func errorWithArgs(c codes.Code, code string, args map[string]string) error {
+	st, err := status.New(c, code).WithDetails(&pb.Error{Code: code, Args: args})
+	if err != nil {
+		return status.Error(c, code)
+	}
+	return st.Err()
+}
+
+// localeHint collects what the handler learns about the caller, so errors can
+// be localized from the profile once the handler has returned
+type localeHint struct {
+	subject Subject
+
+	// Messages the handler supplied for the error with messageCode, keyed by locale
+	messageCode string
+	messages    map[string]string
+}
+
+const localeHintContextKey contextKey = "locale_hint"
+
+// ErrorLocalizer attaches a localized pb.Error to every error returned by the
+// gRPC services. The locale is the request's Accept-Language, else the
+// authenticated player's profile locale, else DefaultLocale.
+type ErrorLocalizer struct {
+	Players       *repo.PlayerRepo
+	DefaultLocale string
+}
+
+// UnaryInterceptor must run first in the chain so it also sees errors from
+// validation, rate limiting and authentication
+func (l *ErrorLocalizer) UnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	hint := &localeHint{}
+	resp, err := handler(context.WithValue(ctx, localeHintContextKey, hint), req)
+	if err == nil {
+		return resp, nil
+	}
+
+	locale := requestLocale(ctx)
+	if locale == "" && hint.subject != nil && hint.subject.GetEmail() != "" {
+		locale = playerLocale(ctx, l.Players, hint.subject.GetEmail())
+	}
+	locale = l.locale(locale)
+	if hint.messageCode != "" && status.Convert(err).Message() == hint.messageCode {
+		if message, ok := messageInLocale(hint.messages, locale); ok {
+			err = errorWithMessage(err, message)
+		}
+	}
+	return resp, LocalizeError(err, locale).Err()
+}
+
+// messageInLocale picks the message for locale from messages, following the
+// locale's fallback chain
+func messageInLocale(messages map[string]string, locale string) (string, bool) {
+	for _, l := range i18n.FallbackChain(locale) {
+		if message := messages[l]; message != "" {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// errorWithMessage attaches an already localized message to err, which
+// LocalizeError then leaves as it is
+func errorWithMessage(err error, message string) error {
+	st := status.Convert(err)
+	code, args := errorCode(st)
+	localized, detailErr := status.New(st.Code(), code).WithDetails(&pb.Error{Code: code, Message: message, Args: args})
+	if detailErr != nil {
+		return err
+	}
+	return localized.Err()
+}
+
+func (l *ErrorLocalizer) locale(locale string) string {
+	if i18n.IsSupported(locale) {
+		return locale
+	}
+	if i18n.IsSupported(l.DefaultLocale) {
+		return l.DefaultLocale
+	}
+	return i18n.SupportedLocales[0]
+}
+
+// LocalizeError returns the status of err with a pb.Error detail holding the
+// stable code, its args and the message in locale. The status message stays
+// the stable code. Errors that are already localized are returned unchanged.
+func LocalizeError(err error, locale string) *status.Status {
+	st := status.Convert(err)
+	code, args := errorCode(st)
+	for _, detail := range st.Details() {
+		if e, ok := detail.(*pb.Error); ok && e.GetMessage() != "" {
+			return st
+		}
+	}
+
+	message, ok := i18n.Message(locale, code, args)
+	if !ok {
+		if message, ok = i18n.Message(locale, fallbackCode(st.Code()), args); !ok {
+			message = code
+		}
+	}
+
+	localized, detailErr := status.New(st.Code(), code).WithDetails(&pb.Error{Code: code, Message: message, Args: args})
+	if detailErr != nil {
+		return status.New(st.Code(), code)
+	}
+	return localized
+}
+
+// errorCode extracts the stable code and args of st. Free text messages get
+// the fallback code of their gRPC code and, unless they may leak internals,
+// the text as the "detail" arg.
+func errorCode(st *status.Status) (string, map[string]string) {
+	for _, detail := range st.Details() {
+		if e, ok := detail.(*pb.Error); ok && e.GetCode() != "" {
+			return e.GetCode(), e.GetArgs()
+		}
+	}
+	if stableCodePattern.MatchString(st.Message()) {
+		return st.Message(), nil
+	}
+
+	code := fallbackCode(st.Code())
+	if st.Code() == codes.Internal || st.Code() == codes.Unknown || st.Message() == "" {
+		return code, nil
+	}
+	return code, map[string]string{"detail": st.Message()}
+}
+
+func fallbackCode(c codes.Code) string {
+	if code, ok := fallbackCodes[c]; ok {
+		return code
+	}
+	return fallbackCodes[codes.Internal]
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/validation"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func localizedError(t *testing.T, acceptLanguage string, handlerErr error) (*status.Status, *pb.Error) {
+	t.Helper()
+	ctx := context.Background()
+	if acceptLanguage != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", acceptLanguage))
+	}
+	l := &ErrorLocalizer{DefaultLocale: "sv"}
+	_, err := l.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, handlerErr
+	})
+
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if e, ok := detail.(*pb.Error); ok {
+			return st, e
+		}
+	}
+	t.Fatalf("no Error detail in %v", err)
+	return nil, nil
+}
+
+func TestErrorLocalizerUsesAcceptLanguage(t *testing.T) {
+	st, frame := localizedError(t, "en-GB,en;q=0.9", status.Error(codes.InvalidArgument, "VALIDATION_SCORE_TIE"))
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Equal(t, "VALIDATION_SCORE_TIE", st.Message(), "the status message stays the stable code")
+	require.Equal(t, "VALIDATION_SCORE_TIE", frame.GetCode())
+	require.Equal(t, "A match cannot end in a tie.", frame.GetMessage())
+
+	// Without Accept-Language the default locale is used
+	_, frame = localizedError(t, "", status.Error(codes.InvalidArgument, "VALIDATION_SCORE_TIE"))
+	require.Equal(t, "En match kan inte sluta oavgjort.", frame.GetMessage())
+}
+
+func TestErrorLocalizerInterpolatesArgs(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	err := validateMatchTimeWindow(time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC), start, end)
+
+	_, frame := localizedError(t, "en", err)
+	require.Equal(t, "SERIES_WINDOW", frame.GetCode())
+	require.Equal(t, "2026-10-05", frame.GetArgs()["match_date"])
+	require.Equal(t, "Match date 2026-10-05 must be within the series period 2026-09-01 – 2026-09-30.", frame.GetMessage())
+}
+
+func TestErrorLocalizerFallsBackForFreeText(t *testing.T) {
+	st, frame := localizedError(t, "en", status.Error(codes.InvalidArgument, "validation error: name: value is required"))
+	require.Equal(t, "VALIDATION_FAILED", st.Message())
+	require.Equal(t, "validation error: name: value is required", frame.GetArgs()["detail"])
+	require.Equal(t, "Please check your input and try again.", frame.GetMessage())
+
+	// Internal errors never expose their text
+	_, frame = localizedError(t, "en", status.Error(codes.Internal, "mongo: connection refused"))
+	require.Equal(t, "INTERNAL_ERROR", frame.GetCode())
+	require.Empty(t, frame.GetArgs())
+}
+
+func TestErrorLocalizerUsesRuleMessages(t *testing.T) {
+	violation := &validation.RuleViolation{Rule: validation.Rule{
+		Name:      "Weekday evenings",
+		ErrorCode: "CLUB_WEEKDAY_EVENINGS",
+		Messages:  map[string]string{"sv": "Matcher spelas vardagskvällar.", "en": "Matches are played on weekday evenings."},
+	}}
+	localize := func(acceptLanguage string) *pb.Error {
+		ctx := context.Background()
+		if acceptLanguage != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", acceptLanguage))
+		}
+		l := &ErrorLocalizer{DefaultLocale: "sv"}
+		_, err := l.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return nil, ruleViolationError(ctx, violation)
+		})
+		st := status.Convert(err)
+		require.Equal(t, "CLUB_WEEKDAY_EVENINGS", st.Message())
+		for _, detail := range st.Details() {
+			if e, ok := detail.(*pb.Error); ok {
+				return e
+			}
+		}
+		t.Fatalf("no Error detail in %v", err)
+		return nil
+	}
+
+	require.Equal(t, "Matches are played on weekday evenings.", localize("en").GetMessage())
+	require.Equal(t, "Matches are played on weekday evenings.", localize("de").GetMessage(), "German falls back to English")
+	require.Equal(t, "Matcher spelas vardagskvällar.", localize("nb").GetMessage(), "Norwegian falls back to Swedish")
+	require.Equal(t, "Matcher spelas vardagskvällar.", localize("").GetMessage(), "default locale")
+	require.Equal(t, "CLUB_WEEKDAY_EVENINGS", localize("en").GetCode())
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 
 	"github.com/goencoder/klubbspel/backend/internal/repo"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
@@ -14,22 +15,45 @@ type LeaderboardService struct {
 	pb.UnimplementedLeaderboardServiceServer
 	Leaderboard *repo.LeaderboardRepo
 	Players     *repo.PlayerRepo
-	Matches     *MatchService // For fallback recalculation
+	Series      *repo.SeriesRepo // For CLUB_ONLY visibility checks
+	Matches     *MatchService    // For fallback recalculation
 }
 
 func (s *LeaderboardService) GetLeaderboard(ctx context.Context, in *pb.GetLeaderboardRequest) (*pb.GetLeaderboardResponse, error) {
 	log.Info().Str("seriesId", in.GetSeriesId()).Msg("GetLeaderboard called")
 
-	// Read from pre-calculated leaderboard
-	leaderboardEntries, err := s.Leaderboard.FindBySeriesOrdered(ctx, in.GetSeriesId())
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
 	if err != nil {
-		log.Error().Str("seriesId", in.GetSeriesId()).Err(err).Msg("Failed to get leaderboard")
-		return nil, status.Error(codes.Internal, "LEADERBOARD_FETCH_FAILED")
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
+	var leaderboardEntries []*repo.LeaderboardEntry
+	if in.AsOf != nil {
+		// Point-in-time standings are calculated from the matches, not the stored leaderboard
+		if s.Matches == nil {
+			return nil, status.Error(codes.Unimplemented, "LEADERBOARD_AS_OF_UNAVAILABLE")
+		}
+		leaderboardEntries, err = s.Matches.StandingsAsOf(ctx, series, in.GetAsOf().AsTime())
+		if err != nil {
+			log.Error().Str("seriesId", in.GetSeriesId()).Err(err).Msg("Failed to calculate leaderboard as of")
+			return nil, status.Error(codes.Internal, "LEADERBOARD_FETCH_FAILED")
+		}
+	} else {
+		// Read from pre-calculated leaderboard
+		leaderboardEntries, err = s.Leaderboard.FindBySeriesOrdered(ctx, in.GetSeriesId())
+		if err != nil {
+			log.Error().Str("seriesId", in.GetSeriesId()).Err(err).Msg("Failed to get leaderboard")
+			return nil, status.Error(codes.Internal, "LEADERBOARD_FETCH_FAILED")
+		}
 	}
 
 	if len(leaderboardEntries) == 0 {
 		// Fallback: Trigger recalculation if no leaderboard exists yet
-		if s.Matches != nil {
+		if s.Matches != nil && in.AsOf == nil {
 			log.Info().Str("seriesId", in.GetSeriesId()).Msg("Leaderboard empty, triggering recalculation")
 			if err := s.Matches.RecalculateStandings(ctx, in.GetSeriesId()); err != nil {
 				log.Error().Str("seriesId", in.GetSeriesId()).Err(err).Msg("Fallback recalculation failed")
@@ -55,7 +79,7 @@ func (s *LeaderboardService) GetLeaderboard(ctx context.Context, in *pb.GetLeade
 				// Continue with populated leaderboard
 			}
 		} else {
-			// No MatchService available, return empty
+			// No MatchService available, or no matches played by as_of, return empty
 			return &pb.GetLeaderboardResponse{
 				Entries:         []*pb.LeaderboardEntry{},
 				StartCursor:     "",
@@ -93,6 +117,7 @@ func (s *LeaderboardService) GetLeaderboard(ctx context.Context, in *pb.GetLeade
 			PlayerId:      entry.PlayerID,
 			PlayerName:    playerName,
 			Rank:          entry.Rank,
+			StandingsRank: entry.Rank,
 			EloRating:     entry.Rating,
 			MatchesPlayed: entry.MatchesPlayed,
 			MatchesWon:    entry.MatchesWon,
@@ -112,6 +137,8 @@ func (s *LeaderboardService) GetLeaderboard(ctx context.Context, in *pb.GetLeade
 		entries = append(entries, pbEntry)
 	}
 
+	entries = sortLeaderboard(entries, in.GetSortBy(), in.GetMinMatches())
+
 	// Handle pagination
 	pageSize := in.GetPageSize()
 	if pageSize == 0 {
@@ -170,3 +197,39 @@ func (s *LeaderboardService) GetLeaderboard(ctx context.Context, in *pb.GetLeade
 		TotalPlayers:    totalPlayers,
 	}, nil
 }
+
+// sortLeaderboard drops players with fewer than minMatches matches and orders
+// the rest by sortBy. Players equal on the sort key keep their standings
+// order. Rank is renumbered to the position in the returned list.
+func sortLeaderboard(entries []*pb.LeaderboardEntry, sortBy pb.LeaderboardSort, minMatches int32) []*pb.LeaderboardEntry {
+	if sortBy == pb.LeaderboardSort_LEADERBOARD_SORT_UNSPECIFIED && minMatches <= 0 {
+		return entries
+	}
+
+	qualified := make([]*pb.LeaderboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.GetMatchesPlayed() >= minMatches {
+			qualified = append(qualified, entry)
+		}
+	}
+
+	var key func(e *pb.LeaderboardEntry) float64
+	switch sortBy {
+	case pb.LeaderboardSort_LEADERBOARD_SORT_WIN_RATE:
+		key = func(e *pb.LeaderboardEntry) float64 { return float64(e.GetWinRate()) }
+	case pb.LeaderboardSort_LEADERBOARD_SORT_MATCHES_PLAYED:
+		key = func(e *pb.LeaderboardEntry) float64 { return float64(e.GetMatchesPlayed()) }
+	case pb.LeaderboardSort_LEADERBOARD_SORT_GAMES_DIFFERENCE:
+		key = func(e *pb.LeaderboardEntry) float64 { return float64(e.GetGamesWon() - e.GetGamesLost()) }
+	}
+	if key != nil {
+		sort.SliceStable(qualified, func(i, j int) bool {
+			return key(qualified[i]) > key(qualified[j])
+		})
+	}
+
+	for i, entry := range qualified {
+		entry.Rank = int32(i + 1)
+	}
+	return qualified
+}
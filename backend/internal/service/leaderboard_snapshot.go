@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// StandingsAsOf returns the standings of series calculated only from the
+// matches played up to the end of asOf's UTC day. Days that are over and fall
+// within the series are cached as snapshots of the series' current snapshot
+// generation, which bounds the cache by the length of the series whatever
+// times callers ask for. A snapshot stays valid until a match played on or
+// before its day is reported, edited or deleted.
+func (s *MatchService) StandingsAsOf(ctx context.Context, series *repo.Series, asOf time.Time) ([]*repo.LeaderboardEntry, error) {
+	seriesID := series.ID.Hex()
+	asOf = endOfDay(asOf)
+	now := time.Now().UTC()
+	cacheable := s.Snapshots != nil && asOf.Before(now) &&
+		!asOf.Before(series.StartsAt) && !asOf.After(endOfDay(series.EndsAt))
+
+	if cacheable {
+		snapshot, err := s.Snapshots.Find(ctx, seriesID, asOf, series.SnapshotGeneration)
+		if err == nil {
+			return snapshot.Entries, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			// A broken cache should not fail the request
+			log.Warn().Err(err).Str("seriesID", seriesID).Msg("Failed to read leaderboard snapshot")
+		}
+	}
+
+	matches, err := s.Matches.FindBySeriesPlayedUntil(ctx, seriesID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch matches: %w", err)
+	}
+
+	entries, err := s.computeStandings(ctx, series, matches, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		// series was read before the matches, so a match change since then has
+		// bumped the generation past the one stored here
+		snapshot := &repo.LeaderboardSnapshot{SeriesID: seriesID, AsOf: asOf, Generation: series.SnapshotGeneration, Entries: entries, CreatedAt: now}
+		if err := s.Snapshots.Upsert(ctx, snapshot); err != nil {
+			log.Warn().Err(err).Str("seriesID", seriesID).Msg("Failed to store leaderboard snapshot")
+		}
+	}
+	return entries, nil
+}
+
+// endOfDay returns the last millisecond, as stored by MongoDB, of t's UTC day
+func endOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour).Add(24*time.Hour - time.Millisecond)
+}
+
+// invalidateSnapshots drops the cached standings that a change to a match
+// played at playedAt makes stale; the zero time drops every snapshot of the
+// series. The series' snapshot generation is bumped first, so standings
+// calculated from the matches before the change are never served, and the
+// snapshots that remain valid are moved to the new generation. The season
+// summary covers the whole series, so it is always dropped and generated
+// again on its next read or summarizer run.
+func (s *MatchService) invalidateSnapshots(ctx context.Context, seriesID string, playedAt time.Time) {
+	if s.Snapshots != nil {
+		var generation int64
+		if s.Series != nil {
+			bumped, err := s.Series.BumpSnapshotGeneration(ctx, seriesID)
+			if err != nil {
+				log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to bump snapshot generation")
+			}
+			generation = bumped
+		}
+		if err := s.Snapshots.DeleteFrom(ctx, seriesID, playedAt); err != nil {
+			log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to invalidate leaderboard snapshots")
+		}
+		if generation > 0 && !playedAt.IsZero() {
+			if err := s.Snapshots.Advance(ctx, seriesID, playedAt, generation); err != nil {
+				log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to keep earlier leaderboard snapshots")
+			}
+		}
+	}
+	if s.Summaries != nil {
+		if err := s.Summaries.Delete(ctx, seriesID); err != nil {
+			log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to invalidate series summary")
+		}
+	}
+}
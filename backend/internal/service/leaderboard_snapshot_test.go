@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+func TestStandingsAsOfCachesOnlyPastDaysOfTheSeries(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	series := &repo.Series{
+		ID:                 primitive.NewObjectID(),
+		StartsAt:           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:             time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		SnapshotGeneration: 3,
+	}
+
+	mt.Run("past day is normalized and cached", func(mt *mtest.T) {
+		db := mt.DB.Name()
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Snapshot indexes
+		svc := &MatchService{Matches: repo.NewMatchRepo(mt.DB, nil), Snapshots: repo.NewLeaderboardSnapshotRepo(mt.DB)}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".leaderboard_snapshots", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, db+".matches", mtest.FirstBatch),
+		)
+		mt.ClearEvents()
+
+		_, err := svc.StandingsAsOf(context.Background(), series, time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC))
+		require.NoError(mt, err)
+
+		endOfDay := time.Date(2025, 3, 14, 23, 59, 59, 999_000_000, time.UTC)
+		find := mt.GetStartedEvent().Command
+		require.Equal(mt, endOfDay.UnixMilli(), find.Lookup("filter", "as_of").Time().UnixMilli())
+		require.Equal(mt, int64(3), find.Lookup("filter", "generation").AsInt64())
+		matches := mt.GetStartedEvent().Command
+		require.Equal(mt, endOfDay.UnixMilli(), matches.Lookup("filter", "played_at", "$lte").Time().UnixMilli())
+		upsert := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		require.Equal(mt, endOfDay.UnixMilli(), upsert.Lookup("u", "as_of").Time().UnixMilli())
+		require.Equal(mt, int64(3), upsert.Lookup("u", "generation").AsInt64())
+	})
+
+	ongoing := *series
+	ongoing.EndsAt = time.Now().Add(30 * 24 * time.Hour)
+	tests := []struct {
+		name   string
+		series *repo.Series
+		asOf   time.Time
+	}{
+		{"before the series", series, time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)},
+		{"after the series", series, time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)},
+		{"today", &ongoing, time.Now()},
+	}
+	for _, test := range tests {
+		mt.Run(test.name+" is not cached", func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse()) // Snapshot indexes
+			svc := &MatchService{Matches: repo.NewMatchRepo(mt.DB, nil), Snapshots: repo.NewLeaderboardSnapshotRepo(mt.DB)}
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".matches", mtest.FirstBatch))
+			mt.ClearEvents()
+
+			_, err := svc.StandingsAsOf(context.Background(), test.series, test.asOf)
+			require.NoError(mt, err)
+			require.Equal(mt, "matches", mt.GetStartedEvent().Command.Lookup("find").StringValue())
+			require.Nil(mt, mt.GetStartedEvent())
+		})
+	}
+}
+
+func TestInvalidateSnapshotsBumpsGeneration(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("invalidate", func(mt *mtest.T) {
+		seriesID := primitive.NewObjectID()
+		playedAt := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Snapshot indexes
+		svc := &MatchService{Series: repo.NewSeriesRepo(mt.DB), Snapshots: repo.NewLeaderboardSnapshotRepo(mt.DB)}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: seriesID}, {Key: "snapshot_generation", Value: int64(4)}}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		mt.ClearEvents()
+
+		svc.invalidateSnapshots(context.Background(), seriesID.Hex(), playedAt)
+
+		bump := mt.GetStartedEvent()
+		require.Equal(mt, "findAndModify", bump.CommandName)
+		require.Equal(mt, int32(1), bump.Command.Lookup("update", "$inc", "snapshot_generation").Int32())
+		require.Equal(mt, "delete", mt.GetStartedEvent().CommandName)
+		advance := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		require.Equal(mt, playedAt.UnixMilli(), advance.Lookup("q", "as_of", "$lt").Time().UnixMilli())
+		require.Equal(mt, int64(4), advance.Lookup("u", "$set", "generation").AsInt64())
+	})
+}
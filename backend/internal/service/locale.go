@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/goencoder/klubbspel/backend/internal/i18n"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// requestLocale returns the supported locale the caller's browser prefers, or
+// "" when the request carries no usable Accept-Language header
+func requestLocale(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, key := range []string{"grpcgateway-accept-language", "accept-language"} {
+		if values := md.Get(key); len(values) > 0 {
+			if locale := i18n.FromAcceptLanguage(values[0]); locale != "" {
+				return locale
+			}
+		}
+	}
+	return ""
+}
+
+// playerLocale returns the preferred locale of the player with the given
+// email, or "" when the player is unknown or has not chosen one. The email
+// layer falls back to the platform default for "".
+func playerLocale(ctx context.Context, players *repo.PlayerRepo, email string) string {
+	if players == nil {
+		return ""
+	}
+	player, err := players.FindByEmail(ctx, email)
+	if err != nil {
+		return ""
+	}
+	return player.Locale
+}
+
+// recipientLocale picks the language for an email the caller requested for
+// themselves: the account's preference, else the browser's
+func (s *AuthService) recipientLocale(ctx context.Context, email string) string {
+	if locale := playerLocale(ctx, s.PlayerRepo, email); locale != "" {
+		return locale
+	}
+	return requestLocale(ctx)
+}
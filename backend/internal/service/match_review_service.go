@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goencoder/klubbspel/backend/internal/audit"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var flagStatusToProto = map[string]pb.MatchFlagStatus{
+	repo.FlagStatusPending:  pb.MatchFlagStatus_MATCH_FLAG_STATUS_PENDING,
+	repo.FlagStatusApproved: pb.MatchFlagStatus_MATCH_FLAG_STATUS_APPROVED,
+	repo.FlagStatusRejected: pb.MatchFlagStatus_MATCH_FLAG_STATUS_REJECTED,
+}
+
+type MatchReviewService struct {
+	pb.UnimplementedMatchReviewServiceServer
+	Flags    *repo.MatchFlagRepo
+	Matches  *repo.MatchRepo
+	Players  *repo.PlayerRepo
+	Clubs    *repo.ClubRepo
+	MatchSvc *MatchService
+	Audit    *audit.AuditLogger
+}
+
+func (s *MatchReviewService) ListMatchFlags(ctx context.Context, in *pb.ListMatchFlagsRequest) (*pb.ListMatchFlagsResponse, error) {
+	if err := s.requireFlagReviewer(ctx, in.GetClubId()); err != nil {
+		return nil, err
+	}
+
+	statusFilter := ""
+	for value, pbStatus := range flagStatusToProto {
+		if pbStatus == in.GetStatus() {
+			statusFilter = value
+		}
+	}
+
+	flags, err := s.Flags.ListByClub(ctx, in.GetClubId(), statusFilter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "MATCH_FLAG_LIST_FAILED")
+	}
+
+	resp := &pb.ListMatchFlagsResponse{}
+	for _, flag := range flags {
+		resp.Flags = append(resp.Flags, s.flagToProto(ctx, flag))
+	}
+	return resp, nil
+}
+
+func (s *MatchReviewService) ReviewMatchFlag(ctx context.Context, in *pb.ReviewMatchFlagRequest) (*pb.ReviewMatchFlagResponse, error) {
+	flag, err := s.Flags.FindByID(ctx, in.GetFlagId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "MATCH_FLAG_NOT_FOUND")
+	}
+
+	if err := s.requireFlagReviewer(ctx, flag.ClubID); err != nil {
+		return nil, err
+	}
+
+	if flag.Status != repo.FlagStatusPending {
+		return nil, status.Error(codes.FailedPrecondition, "MATCH_FLAG_ALREADY_REVIEWED")
+	}
+
+	newStatus := repo.FlagStatusApproved
+	// A match that is already gone invalidates every snapshot of the series
+	var playedAt time.Time
+	if !in.GetApprove() {
+		newStatus = repo.FlagStatusRejected
+		match, err := s.Matches.FindByID(ctx, flag.MatchID)
+		if err == nil {
+			playedAt = match.PlayedAt
+		} else if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Error(codes.Internal, "MATCH_DELETE_FAILED")
+		}
+	}
+
+	reviewed, err := s.Flags.Review(ctx, in.GetFlagId(), newStatus, GetSubjectFromContext(ctx).GetEmail(), in.GetNote(), time.Now().UTC())
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, "MATCH_FLAG_ALREADY_REVIEWED")
+	}
+
+	if !in.GetApprove() {
+		if err := s.Matches.Delete(ctx, flag.MatchID); err != nil {
+			// Put the flag back in the queue so the rejection can be retried
+			if err := s.Flags.Reopen(ctx, in.GetFlagId()); err != nil {
+				log.Error().Err(err).Str("flagID", in.GetFlagId()).Msg("Failed to reopen match flag")
+			}
+			return nil, status.Error(codes.Internal, "MATCH_DELETE_FAILED")
+		}
+		s.auditMatchRemoved(ctx, reviewed)
+		s.MatchSvc.invalidateSnapshots(ctx, flag.SeriesID, playedAt)
+		if err := s.MatchSvc.RecalculateStandings(ctx, flag.SeriesID); err != nil {
+			log.Error().Err(err).Str("seriesID", flag.SeriesID).Msg("Failed to recalculate standings")
+		}
+	}
+
+	return &pb.ReviewMatchFlagResponse{Flag: s.flagToProto(ctx, reviewed)}, nil
+}
+
+// auditMatchRemoved records that a reviewer removed a flagged match
+func (s *MatchReviewService) auditMatchRemoved(ctx context.Context, flag *repo.MatchFlag) {
+	if s.Audit == nil {
+		return
+	}
+	s.Audit.LogEvent(ctx, audit.AuditEvent{
+		Type:       audit.EventDataDelete,
+		Action:     "match.removed",
+		ActorID:    flag.ReviewedBy,
+		ActorType:  "player",
+		TargetID:   flag.MatchID,
+		TargetType: "match",
+		ClubID:     flag.ClubID,
+		Result:     "SUCCESS",
+		Message:    "Flagged match removed after review",
+		Details: map[string]interface{}{
+			"series_id":   flag.SeriesID,
+			"flag_id":     flag.ID.Hex(),
+			"reasons":     flag.Reasons,
+			"review_note": flag.ReviewNote,
+		},
+	})
+}
+
+func (s *MatchReviewService) GetAbuseThresholds(ctx context.Context, in *pb.GetAbuseThresholdsRequest) (*pb.GetAbuseThresholdsResponse, error) {
+	if err := requireClubPermission(ctx, in.GetClubId(), PermManageClub); err != nil {
+		return nil, err
+	}
+
+	club, err := s.Clubs.FindByID(ctx, in.GetClubId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "CLUB_NOT_FOUND")
+	}
+
+	return &pb.GetAbuseThresholdsResponse{Thresholds: abuseThresholdsToProto(effectiveAbuseThresholds(club.AbuseThresholds))}, nil
+}
+
+func (s *MatchReviewService) UpdateAbuseThresholds(ctx context.Context, in *pb.UpdateAbuseThresholdsRequest) (*pb.UpdateAbuseThresholdsResponse, error) {
+	if err := requireClubPermission(ctx, in.GetClubId(), PermManageClub); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Clubs.FindByID(ctx, in.GetClubId()); err != nil {
+		return nil, status.Error(codes.NotFound, "CLUB_NOT_FOUND")
+	}
+
+	t := in.GetThresholds()
+	thresholds := &repo.AbuseThresholds{
+		PairingWindowHours:  t.GetPairingWindowHours(),
+		MaxPairingsInWindow: t.GetMaxPairingsInWindow(),
+		LateReportHours:     t.GetLateReportHours(),
+		SelfWinMinReports:   t.GetSelfWinMinReports(),
+		SelfWinRatio:        t.GetSelfWinRatio(),
+	}
+
+	club, err := s.Clubs.Update(ctx, in.GetClubId(), map[string]interface{}{"abuse_thresholds": thresholds})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "CLUB_UPDATE_FAILED")
+	}
+
+	return &pb.UpdateAbuseThresholdsResponse{Thresholds: abuseThresholdsToProto(effectiveAbuseThresholds(club.AbuseThresholds))}, nil
+}
+
+// requireFlagReviewer checks review access; flags of open series have no club and
+// can only be reviewed by the platform owner
+func (s *MatchReviewService) requireFlagReviewer(ctx context.Context, clubID string) error {
+	if clubID != "" {
+		return requireClubPermission(ctx, clubID, PermManageMatches)
+	}
+
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+	isOwner, err := subject.IsPlatformOwner(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !isOwner {
+		return status.Error(codes.PermissionDenied, "PLATFORM_OWNER_REQUIRED")
+	}
+	return nil
+}
+
+func (s *MatchReviewService) flagToProto(ctx context.Context, flag *repo.MatchFlag) *pb.MatchFlag {
+	out := &pb.MatchFlag{
+		Id:         flag.ID.Hex(),
+		MatchId:    flag.MatchID,
+		SeriesId:   flag.SeriesID,
+		ClubId:     flag.ClubID,
+		ReportedBy: flag.ReportedBy,
+		Reasons:    flag.Reasons,
+		Status:     flagStatusToProto[flag.Status],
+		CreatedAt:  timestamppb.New(flag.CreatedAt),
+		ReviewedBy: flag.ReviewedBy,
+		ReviewNote: flag.ReviewNote,
+	}
+	if flag.ReviewedAt != nil {
+		out.ReviewedAt = timestamppb.New(*flag.ReviewedAt)
+	}
+
+	if match, err := s.Matches.FindByID(ctx, flag.MatchID); err == nil {
+		view := &pb.MatchView{
+			Id:       match.ID.Hex(),
+			SeriesId: match.SeriesID,
+			ScoreA:   match.ScoreA,
+			ScoreB:   match.ScoreB,
+			PlayedAt: timestamppb.New(match.PlayedAt),
+		}
+		if players, err := s.Players.FindByIDs(ctx, []string{match.PlayerAID, match.PlayerBID}); err == nil {
+			if p, ok := players[match.PlayerAID]; ok {
+				view.PlayerAName = p.DisplayName
+			}
+			if p, ok := players[match.PlayerBID]; ok {
+				view.PlayerBName = p.DisplayName
+			}
+		}
+		out.Match = view
+	}
+	return out
+}
+
+func abuseThresholdsToProto(t repo.AbuseThresholds) *pb.AbuseThresholds {
+	return &pb.AbuseThresholds{
+		PairingWindowHours:  t.PairingWindowHours,
+		MaxPairingsInWindow: t.MaxPairingsInWindow,
+		LateReportHours:     t.LateReportHours,
+		SelfWinMinReports:   t.SelfWinMinReports,
+		SelfWinRatio:        t.SelfWinRatio,
+	}
+}
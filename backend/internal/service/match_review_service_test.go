@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func TestRejectMatchFlagReopensFlagWhenDeleteFails(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("delete fails", func(mt *mtest.T) {
+		flagID := primitive.NewObjectID()
+		matchID := primitive.NewObjectID()
+		flag := func(status string) bson.D {
+			return bson.D{
+				{Key: "_id", Value: flagID},
+				{Key: "match_id", Value: matchID.Hex()},
+				{Key: "series_id", Value: "s1"},
+				{Key: "status", Value: status},
+			}
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Flag indexes
+		svc := &MatchReviewService{Flags: repo.NewMatchFlagRepo(mt.DB), Matches: repo.NewMatchRepo(mt.DB, nil)}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".match_flags", mtest.FirstBatch, flag(repo.FlagStatusPending)),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".matches", mtest.FirstBatch, bson.D{{Key: "_id", Value: matchID}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: flag(repo.FlagStatusRejected)}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 91, Message: "shutting down"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		mt.ClearEvents()
+
+		ctx := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+		_, err := svc.ReviewMatchFlag(ctx, &pb.ReviewMatchFlagRequest{FlagId: flagID.Hex(), Approve: false})
+		require.Equal(mt, codes.Internal, status.Code(err))
+		require.Equal(mt, "MATCH_DELETE_FAILED", status.Convert(err).Message())
+
+		var reopen bson.Raw
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "update" {
+				reopen = event.Command
+			}
+		}
+		require.NotNil(mt, reopen)
+		require.Equal(mt, repo.FlagStatusRejected, reopen.Lookup("updates", "0", "q", "status").StringValue())
+		require.Equal(mt, repo.FlagStatusPending, reopen.Lookup("updates", "0", "u", "$set", "status").StringValue())
+	})
+}
+
+func TestRejectMatchFlagInvalidatesSnapshotsFromPlayedAt(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("reject", func(mt *mtest.T) {
+		flagID := primitive.NewObjectID()
+		matchID := primitive.NewObjectID()
+		playedAt := time.Date(2025, 3, 14, 19, 0, 0, 0, time.UTC)
+		flag := func(status string) bson.D {
+			return bson.D{
+				{Key: "_id", Value: flagID},
+				{Key: "match_id", Value: matchID.Hex()},
+				{Key: "series_id", Value: "s1"},
+				{Key: "status", Value: status},
+			}
+		}
+		db := mt.DB.Name()
+
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Flag indexes
+			mtest.CreateSuccessResponse(), // Snapshot indexes
+		)
+		svc := &MatchReviewService{
+			Flags:    repo.NewMatchFlagRepo(mt.DB),
+			Matches:  repo.NewMatchRepo(mt.DB, nil),
+			MatchSvc: &MatchService{Series: repo.NewSeriesRepo(mt.DB), Snapshots: repo.NewLeaderboardSnapshotRepo(mt.DB)},
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".match_flags", mtest.FirstBatch, flag(repo.FlagStatusPending)),
+			mtest.CreateCursorResponse(0, db+".matches", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: matchID}, {Key: "series_id", Value: "s1"}, {Key: "played_at", Value: playedAt},
+			}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: flag(repo.FlagStatusRejected)}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),        // Delete match
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),        // Delete snapshots
+			mtest.CreateCursorResponse(0, db+".matches", mtest.FirstBatch), // Match view of the removed match
+		)
+		mt.ClearEvents()
+
+		ctx := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+		resp, err := svc.ReviewMatchFlag(ctx, &pb.ReviewMatchFlagRequest{FlagId: flagID.Hex(), Approve: false})
+		require.NoError(mt, err)
+		require.Equal(mt, pb.MatchFlagStatus_MATCH_FLAG_STATUS_REJECTED, resp.GetFlag().GetStatus())
+
+		var invalidation bson.Raw
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			if event.CommandName == "delete" && event.Command.Lookup("delete").StringValue() == "leaderboard_snapshots" {
+				invalidation = event.Command
+			}
+		}
+		require.NotNil(mt, invalidation)
+		require.Equal(mt, playedAt.UnixMilli(), invalidation.Lookup("deletes", "0", "q", "as_of", "$gte").Time().UnixMilli())
+	})
+}
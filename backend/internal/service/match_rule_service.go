@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/validation"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultRuleErrorCode is returned for violated rules without their own error code
+const defaultRuleErrorCode = "RULE_VIOLATION"
+
+type MatchRuleService struct {
+	pb.UnimplementedMatchRuleServiceServer
+	Rules     *repo.MatchRuleRepo
+	Series    *repo.SeriesRepo
+	Validator *validation.CELValidator
+}
+
+func (s *MatchRuleService) CreateMatchRule(ctx context.Context, in *pb.CreateMatchRuleRequest) (*pb.CreateMatchRuleResponse, error) {
+	if err := requireClubPermission(ctx, in.GetClubId(), PermManageClub); err != nil {
+		return nil, err
+	}
+
+	if in.GetSeriesId() != "" {
+		series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+		if err != nil {
+			return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+		}
+		if series.ClubID != in.GetClubId() {
+			return nil, status.Error(codes.InvalidArgument, "SERIES_NOT_IN_CLUB")
+		}
+	}
+
+	if _, err := s.Validator.Compile(in.GetExpression()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "RULE_INVALID_EXPRESSION")
+	}
+
+	now := time.Now().UTC()
+	rule, err := s.Rules.Create(ctx, &repo.MatchRule{
+		ClubID:     in.GetClubId(),
+		SeriesID:   in.GetSeriesId(),
+		Name:       in.GetName(),
+		Expression: in.GetExpression(),
+		ErrorCode:  in.GetErrorCode(),
+		Messages:   in.GetMessages(),
+		Active:     in.GetActive(),
+		CreatedBy:  GetSubjectFromContext(ctx).GetEmail(),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "RULE_CREATE_FAILED")
+	}
+
+	return &pb.CreateMatchRuleResponse{Rule: matchRuleToProto(rule)}, nil
+}
+
+func (s *MatchRuleService) UpdateMatchRule(ctx context.Context, in *pb.UpdateMatchRuleRequest) (*pb.UpdateMatchRuleResponse, error) {
+	existing, err := s.Rules.FindByID(ctx, in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "RULE_NOT_FOUND")
+	}
+
+	if err := requireClubPermission(ctx, existing.ClubID, PermManageClub); err != nil {
+		return nil, err
+	}
+
+	paths := []string{"name", "expression", "error_code", "messages", "active"}
+	if mask := in.GetUpdateMask(); mask != nil && len(mask.GetPaths()) > 0 {
+		paths = mask.GetPaths()
+	}
+
+	updates := map[string]interface{}{}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			updates["name"] = in.GetRule().GetName()
+		case "expression":
+			if _, err := s.Validator.Compile(in.GetRule().GetExpression()); err != nil {
+				return nil, status.Error(codes.InvalidArgument, "RULE_INVALID_EXPRESSION")
+			}
+			updates["expression"] = in.GetRule().GetExpression()
+		case "error_code":
+			updates["error_code"] = in.GetRule().GetErrorCode()
+		case "messages":
+			updates["messages"] = in.GetRule().GetMessages()
+		case "active":
+			updates["active"] = in.GetRule().GetActive()
+		default:
+			return nil, status.Error(codes.InvalidArgument, "UNSUPPORTED_UPDATE_FIELD")
+		}
+	}
+	updates["updated_at"] = time.Now().UTC()
+
+	rule, err := s.Rules.Update(ctx, in.GetId(), updates)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "RULE_UPDATE_FAILED")
+	}
+
+	return &pb.UpdateMatchRuleResponse{Rule: matchRuleToProto(rule)}, nil
+}
+
+func (s *MatchRuleService) DeleteMatchRule(ctx context.Context, in *pb.DeleteMatchRuleRequest) (*pb.DeleteMatchRuleResponse, error) {
+	existing, err := s.Rules.FindByID(ctx, in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "RULE_NOT_FOUND")
+	}
+
+	if err := requireClubPermission(ctx, existing.ClubID, PermManageClub); err != nil {
+		return nil, err
+	}
+
+	if err := s.Rules.Delete(ctx, in.GetId()); err != nil {
+		return nil, status.Error(codes.Internal, "RULE_DELETE_FAILED")
+	}
+
+	return &pb.DeleteMatchRuleResponse{Success: true}, nil
+}
+
+func (s *MatchRuleService) ListMatchRules(ctx context.Context, in *pb.ListMatchRulesRequest) (*pb.ListMatchRulesResponse, error) {
+	if err := requireClubPermission(ctx, in.GetClubId(), PermViewAdminData); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.Rules.List(ctx, in.GetClubId(), in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "RULE_LIST_FAILED")
+	}
+
+	resp := &pb.ListMatchRulesResponse{}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, matchRuleToProto(rule))
+	}
+	return resp, nil
+}
+
+func (s *MatchRuleService) TestMatchRule(ctx context.Context, in *pb.TestMatchRuleRequest) (*pb.TestMatchRuleResponse, error) {
+	if err := requireClubPermission(ctx, in.GetClubId(), PermManageClub); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Validator.Compile(in.GetExpression()); err != nil {
+		return &pb.TestMatchRuleResponse{Valid: false, Error: err.Error()}, nil
+	}
+
+	sample := in.GetSample()
+	input := validation.MatchRuleInput{
+		PlayerA:          validation.RulePlayer{ID: sample.GetPlayerAId(), Rating: sample.GetPlayerARating()},
+		PlayerB:          validation.RulePlayer{ID: sample.GetPlayerBId(), Rating: sample.GetPlayerBRating()},
+		ScoreA:           sample.GetScoreA(),
+		ScoreB:           sample.GetScoreB(),
+		PlayedAt:         sample.GetPlayedAt().AsTime(),
+		ClubID:           in.GetClubId(),
+		SeriesFormat:     sample.GetSeriesFormat(),
+		SetsToPlay:       sample.GetSetsToPlay(),
+		PairMatchesWeek:  sample.GetPairMatchesWeek(),
+		PairMatchesMonth: sample.GetPairMatchesMonth(),
+		PairMatchesTotal: sample.GetPairMatchesTotal(),
+	}
+
+	passed, err := s.Validator.Evaluate(in.GetExpression(), input)
+	if err != nil {
+		return &pb.TestMatchRuleResponse{Valid: true, Error: err.Error()}, nil
+	}
+
+	return &pb.TestMatchRuleResponse{Valid: true, Passed: passed}, nil
+}
+
+// ruleViolationError converts a validator error into a gRPC status carrying
+// the rule's error code. The rule's own messages are handed to the
+// ErrorLocalizer, which shows the one for the caller's locale.
+func ruleViolationError(ctx context.Context, err error) error {
+	var violation *validation.RuleViolation
+	if errors.As(err, &violation) {
+		code := violation.Rule.ErrorCode
+		if code == "" {
+			code = defaultRuleErrorCode
+		}
+		if hint, ok := ctx.Value(localeHintContextKey).(*localeHint); ok {
+			hint.messageCode, hint.messages = code, violation.Rule.Messages
+		}
+		return status.Error(codes.FailedPrecondition, code)
+	}
+
+	log.Error().Err(err).Msg("Match rule evaluation failed")
+	return status.Error(codes.FailedPrecondition, "RULE_EVALUATION_FAILED")
+}
+
+func matchRuleToProto(rule *repo.MatchRule) *pb.MatchRule {
+	return &pb.MatchRule{
+		Id:         rule.ID.Hex(),
+		ClubId:     rule.ClubID,
+		SeriesId:   rule.SeriesID,
+		Name:       rule.Name,
+		Expression: rule.Expression,
+		ErrorCode:  rule.ErrorCode,
+		Messages:   rule.Messages,
+		Active:     rule.Active,
+		CreatedAt:  timestamppb.New(rule.CreatedAt),
+		UpdatedAt:  timestamppb.New(rule.UpdatedAt),
+	}
+}
@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"fmt"
+	"maps"
 	"math"
-	"sort"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"github.com/goencoder/klubbspel/backend/internal/validation"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
@@ -30,6 +33,21 @@ type MatchService struct {
 	Players     *repo.PlayerRepo
 	Series      *repo.SeriesRepo
 	Leaderboard *repo.LeaderboardRepo
+	// SeriesPlayers holds optional seeds (ladder positions or initial ratings) for cloned series
+	SeriesPlayers *repo.SeriesPlayerRepo
+	// Entries is consulted when a series requires registration before reporting
+	Entries *repo.SeriesEntryRepo
+	// Rules and Validator evaluate configurable club/series rules (optional)
+	Rules     *repo.MatchRuleRepo
+	Validator *validation.CELValidator
+	// Abuse flags suspicious reports for admin review (optional)
+	Abuse *AbuseDetector
+	// Notifier tells players about reported matches and ladder changes (optional)
+	Notifier *Notifier
+	// Snapshots caches point-in-time standings (optional)
+	Snapshots *repo.LeaderboardSnapshotRepo
+	// Summaries holds season summaries, dropped when a match changes (optional)
+	Summaries *repo.SeriesSummaryRepo
 }
 
 func (s *MatchService) ReportMatch(ctx context.Context, in *pb.ReportMatchRequest) (*pb.ReportMatchResponse, error) {
@@ -61,19 +79,46 @@ func (s *MatchService) ReportMatch(ctx context.Context, in *pb.ReportMatchReques
 		return nil, err
 	}
 
+	if err := checkAPIKeyClub(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesParticipants(ctx, s.Players, series, in.GetPlayerAId(), in.GetPlayerBId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRegisteredPlayers(ctx, series, in.GetPlayerAId(), in.GetPlayerBId()); err != nil {
+		return nil, err
+	}
+
+	if err := s.evaluateMatchRules(ctx, series, "", in.GetPlayerAId(), in.GetPlayerBId(), in.GetScoreA(), in.GetScoreB(), playedAt); err != nil {
+		return nil, err
+	}
+
 	// Create the match record
-	match, err := s.Matches.Create(ctx, in.GetSeriesId(), in.GetPlayerAId(), in.GetPlayerBId(), in.GetScoreA(), in.GetScoreB(), playedAt)
+	match, err := s.Matches.Create(ctx, in.GetSeriesId(), in.GetPlayerAId(), in.GetPlayerBId(), in.GetScoreA(), in.GetScoreB(), playedAt, s.reporterID(ctx))
 	if err != nil {
 		return nil, status.Error(codes.Internal, "MATCH_CREATE_FAILED")
 	}
 
+	s.invalidateSnapshots(ctx, match.SeriesID, match.PlayedAt)
+	s.inspectForAbuse(ctx, series, match)
+	ranks := s.ladderSnapshot(ctx, series)
+
 	// Recalculate and store leaderboard
 	var warnings []string
 	if err := s.RecalculateStandings(ctx, in.GetSeriesId()); err != nil {
 		log.Error().Err(err).Str("seriesID", in.GetSeriesId()).Msg("Failed to recalculate standings")
 		warnings = append(warnings, "Leaderboard recalculation failed; standings may be out of date.")
+		ranks = nil
 	}
 
+	s.notifyMatchReported(ctx, series, match, ranks)
+
 	return &pb.ReportMatchResponse{
 		MatchId:  match.ID.Hex(),
 		Warnings: warnings,
@@ -94,41 +139,92 @@ func (s *MatchService) RecalculateStandings(ctx context.Context, seriesID string
 		return fmt.Errorf("failed to fetch matches: %w", err)
 	}
 
+	entries, err := s.computeStandings(ctx, series, matches, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Clear existing leaderboard
 	if err := s.Leaderboard.DeleteAllForSeries(ctx, seriesID); err != nil {
 		return fmt.Errorf("failed to clear leaderboard: %w", err)
 	}
 
-	if len(matches) == 0 {
-		return nil // No matches, nothing to calculate
+	for _, entry := range entries {
+		if err := s.Leaderboard.UpsertEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to upsert leaderboard entry for player %s: %w", entry.PlayerID, err)
+		}
+	}
+
+	return nil
+}
+
+// computeStandings calculates the leaderboard of a series from matches, in
+// chronological order, without storing it
+func (s *MatchService) computeStandings(ctx context.Context, series *repo.Series, matches []*repo.Match, now time.Time) ([]*repo.LeaderboardEntry, error) {
+	seriesID := series.ID.Hex()
+
+	// Get seeds carried over from a previous series (empty for most series)
+	seeds, err := s.loadSeeds(ctx, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch series seeds: %w", err)
 	}
 
-	now := time.Now()
 	format := pb.SeriesFormat(series.Format)
 
 	if format == pb.SeriesFormat_SERIES_FORMAT_LADDER {
+		if len(matches) == 0 && len(seeds) == 0 {
+			return nil, nil // No matches or seeded positions, nothing to calculate
+		}
 		// For ladder series, calculate positions based on ladder rules
-		return s.recalculateLadderStandings(ctx, seriesID, series.LadderRules, matches, now)
+		return calculateLadderStandings(seriesID, series.LadderRules, matches, seeds, now), nil
+	}
+
+	if len(matches) == 0 && len(seeds) == 0 {
+		return nil, nil // No matches or seeded ratings, nothing to calculate
 	}
 
 	// For open series, calculate ELO ratings
-	return s.recalculateEloStandings(ctx, seriesID, matches, now)
+	return s.calculateEloStandings(ctx, seriesID, seriesTiebreakers(series.Tiebreakers), matches, seeds, now)
+}
+
+// loadSeeds returns the seeded series players for a series, ordered by position
+func (s *MatchService) loadSeeds(ctx context.Context, seriesID string) ([]*repo.SeriesPlayer, error) {
+	if s.SeriesPlayers == nil {
+		return nil, nil
+	}
+	return s.SeriesPlayers.FindBySeriesOrdered(ctx, seriesID)
 }
 
-// recalculate EloStandings calculates ELO ratings for all players and stores in leaderboard
-func (s *MatchService) recalculateEloStandings(ctx context.Context, seriesID string, matches []*repo.Match, now time.Time) error {
+// calculateEloStandings calculates ELO ratings for all players and ranks them
+func (s *MatchService) calculateEloStandings(ctx context.Context, seriesID string, tiebreakers []pb.Tiebreaker, matches []*repo.Match, seeds []*repo.SeriesPlayer, now time.Time) ([]*repo.LeaderboardEntry, error) {
 	// Calculate ELO ratings from matches
 	eloRatings := make(map[string]int32)
 	matchStats := make(map[string]*playerMatchStats)
 
-	// Initialize all players at 1000 ELO
+	// Seeded players start from their carried-over rating
+	initialRatings := make(map[string]int32)
+	for _, seed := range seeds {
+		if seed.InitialRating > 0 {
+			initialRatings[seed.PlayerID] = seed.InitialRating
+		}
+	}
+
+	// Seeded players are ranked from their carried-over rating before they play
+	for _, seed := range seeds {
+		if _, exists := eloRatings[seed.PlayerID]; !exists {
+			eloRatings[seed.PlayerID] = initialRating(initialRatings, seed.PlayerID)
+			matchStats[seed.PlayerID] = &playerMatchStats{}
+		}
+	}
+
+	// Initialize all other players at 1000 ELO
 	for _, match := range matches {
 		if _, exists := eloRatings[match.PlayerAID]; !exists {
-			eloRatings[match.PlayerAID] = 1000
+			eloRatings[match.PlayerAID] = initialRating(initialRatings, match.PlayerAID)
 			matchStats[match.PlayerAID] = &playerMatchStats{}
 		}
 		if _, exists := eloRatings[match.PlayerBID]; !exists {
-			eloRatings[match.PlayerBID] = 1000
+			eloRatings[match.PlayerBID] = initialRating(initialRatings, match.PlayerBID)
 			matchStats[match.PlayerBID] = &playerMatchStats{}
 		}
 
@@ -164,30 +260,34 @@ func (s *MatchService) recalculateEloStandings(ctx context.Context, seriesID str
 		}
 	}
 
-	// Convert to slice and sort by rating
-	type playerRating struct {
-		playerID string
-		rating   int32
-		stats    *playerMatchStats
-	}
-
-	var ratings []playerRating
+	standings := make([]*standing, 0, len(eloRatings))
 	for playerID, rating := range eloRatings {
-		ratings = append(ratings, playerRating{
+		standings = append(standings, &standing{
 			playerID: playerID,
 			rating:   rating,
 			stats:    matchStats[playerID],
 		})
 	}
 
-	// Sort by rating (highest first)
-	sort.Slice(ratings, func(i, j int) bool {
-		return ratings[i].rating > ratings[j].rating
-	})
+	// Names are only needed to break ties in rating
+	if sortsByName(tiebreakers) && hasEqualRatings(eloRatings) {
+		players, err := s.Players.FindByIDs(ctx, slices.Collect(maps.Keys(eloRatings)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch player names: %w", err)
+		}
+		for _, st := range standings {
+			if player, ok := players[st.playerID]; ok {
+				st.name = player.DisplayName
+			}
+		}
+	}
+
+	// Sort by rating (highest first), then by the series' tiebreak chain
+	rankStandings(standings, tiebreakers, matches)
 
-	// Store in leaderboard with ranks
-	for rank, pr := range ratings {
-		entry := &repo.LeaderboardEntry{
+	entries := make([]*repo.LeaderboardEntry, len(standings))
+	for rank, pr := range standings {
+		entries[rank] = &repo.LeaderboardEntry{
 			SeriesID:      seriesID,
 			PlayerID:      pr.playerID,
 			Rank:          int32(rank + 1),
@@ -199,21 +299,34 @@ func (s *MatchService) recalculateEloStandings(ctx context.Context, seriesID str
 			GamesLost:     pr.stats.gamesLost,
 			UpdatedAt:     now,
 		}
-
-		if err := s.Leaderboard.UpsertEntry(ctx, entry); err != nil {
-			return fmt.Errorf("failed to upsert leaderboard entry for player %s: %w", pr.playerID, err)
-		}
 	}
 
-	return nil
+	return entries, nil
 }
 
-// recalculateLadderStandings calculates ladder positions and stores in leaderboard
-func (s *MatchService) recalculateLadderStandings(ctx context.Context, seriesID string, ladderRulesValue int32, matches []*repo.Match, now time.Time) error {
+// initialRating returns the seeded rating for a player, or the default of 1000
+func initialRating(seeded map[string]int32, playerID string) int32 {
+	if rating, ok := seeded[playerID]; ok {
+		return rating
+	}
+	return 1000
+}
+
+// calculateLadderStandings calculates ladder positions, ordered by position
+func calculateLadderStandings(seriesID string, ladderRulesValue int32, matches []*repo.Match, seeds []*repo.SeriesPlayer, now time.Time) []*repo.LeaderboardEntry {
 	// Track positions: playerID -> position
 	positions := make(map[string]int32)
 	nextPosition := int32(1)
 
+	// Seeded players start at their carried-over positions, in order
+	for _, seed := range seeds {
+		if _, exists := positions[seed.PlayerID]; exists {
+			continue
+		}
+		positions[seed.PlayerID] = nextPosition
+		nextPosition++
+	}
+
 	ladderRules := pb.LadderRules(ladderRulesValue)
 
 	// Track match statistics
@@ -266,49 +379,17 @@ func (s *MatchService) recalculateLadderStandings(ctx context.Context, seriesID
 			matchStats[match.PlayerAID].lost++
 		}
 
-		winnerPos := positions[winnerID]
-		loserPos := positions[loserID]
-
-		// Apply ladder climbing rules
-		if winnerPos > loserPos {
-			// Lower-ranked player beats higher-ranked player - winner climbs
-			targetPos := loserPos
-
-			// Shift everyone between targetPos and winnerPos down by 1
-			for pid, pos := range positions {
-				if pos >= targetPos && pos < winnerPos && pid != winnerID {
-					positions[pid] = pos + 1
-				}
-			}
-
-			positions[winnerID] = targetPos
-		} else {
-			// Higher-ranked player wins - apply penalty rules
-			if ladderRules == pb.LadderRules_LADDER_RULES_AGGRESSIVE {
-				// Loser drops one position (swap with player below)
-				belowPos := loserPos + 1
-
-				// Find player at belowPos and swap
-				for pid, pos := range positions {
-					if pos == belowPos {
-						positions[pid] = loserPos
-						positions[loserID] = belowPos
-						break
-					}
-				}
-			}
-			// Classic rules: no penalty
-		}
+		applyLadderResult(positions, winnerID, loserID, ladderRules)
 	}
 
-	// Store in leaderboard
+	entries := make([]*repo.LeaderboardEntry, 0, len(positions))
 	for playerID, position := range positions {
 		stats := matchStats[playerID]
 		if stats == nil {
 			stats = &playerMatchStats{}
 		}
 
-		entry := &repo.LeaderboardEntry{
+		entries = append(entries, &repo.LeaderboardEntry{
 			SeriesID:      seriesID,
 			PlayerID:      playerID,
 			Rank:          position,
@@ -319,14 +400,54 @@ func (s *MatchService) recalculateLadderStandings(ctx context.Context, seriesID
 			GamesWon:      stats.gamesWon,
 			GamesLost:     stats.gamesLost,
 			UpdatedAt:     now,
+		})
+	}
+
+	// Positions are unique, so this order never depends on map iteration
+	slices.SortFunc(entries, func(a, b *repo.LeaderboardEntry) int {
+		return int(a.Rank - b.Rank)
+	})
+	return entries
+}
+
+// applyLadderResult moves the players of a decided match on the ladder: a
+// winner below the loser takes the loser's position and everyone in between
+// drops one step, while under aggressive rules a losing higher ranked player
+// swaps with the player below
+func applyLadderResult(positions map[string]int32, winnerID, loserID string, ladderRules pb.LadderRules) {
+	winnerPos := positions[winnerID]
+	loserPos := positions[loserID]
+
+	// Apply ladder climbing rules
+	if winnerPos > loserPos {
+		// Lower-ranked player beats higher-ranked player - winner climbs
+		targetPos := loserPos
+
+		// Shift everyone between targetPos and winnerPos down by 1
+		for pid, pos := range positions {
+			if pos >= targetPos && pos < winnerPos && pid != winnerID {
+				positions[pid] = pos + 1
+			}
 		}
 
-		if err := s.Leaderboard.UpsertEntry(ctx, entry); err != nil {
-			return fmt.Errorf("failed to upsert leaderboard entry for player %s: %w", playerID, err)
+		positions[winnerID] = targetPos
+	} else {
+		// Higher-ranked player wins - apply penalty rules
+		if ladderRules == pb.LadderRules_LADDER_RULES_AGGRESSIVE {
+			// Loser drops one position (swap with player below)
+			belowPos := loserPos + 1
+
+			// Find player at belowPos and swap
+			for pid, pos := range positions {
+				if pos == belowPos {
+					positions[pid] = loserPos
+					positions[loserID] = belowPos
+					break
+				}
+			}
 		}
+		// Classic rules: no penalty
 	}
-
-	return nil
 }
 
 // validateTableTennisScore validates table tennis scoring rules
@@ -371,11 +492,141 @@ func validateMatchTimeWindow(matchTime, seriesStart, seriesEnd time.Time) error
 	seriesEndDate := seriesEnd.Truncate(24 * time.Hour).Add(24*time.Hour - time.Nanosecond)
 
 	if matchTime.Before(seriesStartDate) || matchTime.After(seriesEndDate) {
-		return status.Errorf(codes.InvalidArgument,
-			"match date %v must be between series start date %v and end date %v (inclusive)",
-			matchTime.Format("2006-01-02"),
-			seriesStart.Format("2006-01-02"),
-			seriesEnd.Format("2006-01-02"))
+		return errorWithArgs(codes.InvalidArgument, "SERIES_WINDOW", map[string]string{
+			"match_date": matchTime.Format("2006-01-02"),
+			"start_date": seriesStart.Format("2006-01-02"),
+			"end_date":   seriesEnd.Format("2006-01-02"),
+		})
+	}
+	return nil
+}
+
+// validateRegisteredPlayers rejects matches involving players without a registered
+// entry when the series requires registration
+func (s *MatchService) validateRegisteredPlayers(ctx context.Context, series *repo.Series, playerIDs ...string) error {
+	if series.Registration == nil || !series.Registration.Required || s.Entries == nil {
+		return nil
+	}
+
+	for _, playerID := range playerIDs {
+		registered, err := s.Entries.IsRegistered(ctx, series.ID.Hex(), playerID)
+		if err != nil {
+			return status.Error(codes.Internal, "REGISTRATION_LOOKUP_FAILED")
+		}
+		if !registered {
+			return status.Error(codes.FailedPrecondition, "VALIDATION_PLAYER_NOT_REGISTERED")
+		}
+	}
+	return nil
+}
+
+// reporterID returns the player ID of the authenticated caller, or "" if unknown
+func (s *MatchService) reporterID(ctx context.Context) string {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return ""
+	}
+
+	player, err := s.Players.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return ""
+	}
+	return player.ID.Hex()
+}
+
+// inspectForAbuse queues suspicious matches for review. Flagging never blocks reporting.
+func (s *MatchService) inspectForAbuse(ctx context.Context, series *repo.Series, match *repo.Match) {
+	if s.Abuse == nil {
+		return
+	}
+
+	if _, err := s.Abuse.Inspect(ctx, series, match); err != nil {
+		log.Error().Err(err).Str("matchID", match.ID.Hex()).Msg("Failed to inspect match for suspicious activity")
+	}
+}
+
+// ladderSnapshot records ladder ranks before a report changes them, so
+// overtaken players can be notified afterwards
+func (s *MatchService) ladderSnapshot(ctx context.Context, series *repo.Series) map[string]int32 {
+	if s.Notifier == nil {
+		return nil
+	}
+	return s.Notifier.LadderSnapshot(ctx, series)
+}
+
+// notifyMatchReported notifies the players of a new match and anyone it
+// pushed down the ladder. ranks is nil when standings were not recalculated.
+func (s *MatchService) notifyMatchReported(ctx context.Context, series *repo.Series, match *repo.Match, ranks map[string]int32) {
+	if s.Notifier == nil {
+		return
+	}
+	s.Notifier.MatchReported(ctx, series, match)
+	s.Notifier.LadderOvertaken(ctx, series, match, ranks)
+}
+
+// evaluateMatchRules checks a match against the active CEL rules of the series and its club.
+// matchID is set when an existing match is edited so it is not counted as an earlier pairing.
+func (s *MatchService) evaluateMatchRules(ctx context.Context, series *repo.Series, matchID, playerAID, playerBID string, scoreA, scoreB int32, playedAt time.Time) error {
+	if s.Rules == nil || s.Validator == nil {
+		return nil
+	}
+
+	stored, err := s.Rules.FindActiveForSeries(ctx, series.ClubID, series.ID.Hex())
+	if err != nil {
+		return status.Error(codes.Internal, "RULE_LOOKUP_FAILED")
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+
+	rules := make([]validation.Rule, len(stored))
+	for i, rule := range stored {
+		rules[i] = validation.Rule{ID: rule.ID.Hex(), Name: rule.Name, Expression: rule.Expression, ErrorCode: rule.ErrorCode, Messages: rule.Messages}
+	}
+
+	input := validation.MatchRuleInput{
+		PlayerA:        validation.RulePlayer{ID: playerAID},
+		PlayerB:        validation.RulePlayer{ID: playerBID},
+		ScoreA:         scoreA,
+		ScoreB:         scoreB,
+		PlayedAt:       playedAt,
+		SeriesID:       series.ID.Hex(),
+		ClubID:         series.ClubID,
+		SeriesFormat:   strings.TrimPrefix(pbSeriesFormat(series.Format).String(), "SERIES_FORMAT_"),
+		SeriesStartsAt: series.StartsAt,
+		SeriesEndsAt:   series.EndsAt,
+		SetsToPlay:     series.SetsToPlay,
+	}
+
+	standings, err := s.Leaderboard.FindBySeriesAndPlayers(ctx, series.ID.Hex(), []string{playerAID, playerBID})
+	if err != nil {
+		return status.Error(codes.Internal, "RULE_LOOKUP_FAILED")
+	}
+	for _, player := range []*validation.RulePlayer{&input.PlayerA, &input.PlayerB} {
+		if entry, ok := standings[player.ID]; ok {
+			player.Rating = entry.Rating
+			player.Rank = entry.Rank
+			player.MatchesPlayed = entry.MatchesPlayed
+		}
+	}
+
+	pairCounts := []struct {
+		from  time.Time
+		count *int64
+	}{
+		{playedAt.AddDate(0, 0, -7), &input.PairMatchesWeek},
+		{playedAt.AddDate(0, 0, -30), &input.PairMatchesMonth},
+		{time.Time{}, &input.PairMatchesTotal},
+	}
+	for _, pc := range pairCounts {
+		*pc.count, err = s.Matches.CountBetweenPlayers(ctx, series.ID.Hex(), playerAID, playerBID, pc.from, playedAt, matchID)
+		if err != nil {
+			return status.Error(codes.Internal, "RULE_LOOKUP_FAILED")
+		}
+	}
+
+	if err := s.Validator.ValidateMatch(rules, input); err != nil {
+		return ruleViolationError(ctx, err)
 	}
 	return nil
 }
@@ -462,24 +713,60 @@ func (s *MatchService) ReportMatchV2(ctx context.Context, in *pb.ReportMatchV2Re
 		return nil, err
 	}
 
+	if err := checkAPIKeyClub(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
+	if err := checkSeriesParticipants(ctx, s.Players, series, playerAId, playerBId); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateRegisteredPlayers(ctx, series, playerAId, playerBId); err != nil {
+		return nil, err
+	}
+
+	if err := s.evaluateMatchRules(ctx, series, "", playerAId, playerBId, scoreA, scoreB, playedAt); err != nil {
+		return nil, err
+	}
+
 	// Create match using existing repository method
-	match, err := s.Matches.Create(ctx, in.GetSeriesId(), playerAId, playerBId, scoreA, scoreB, playedAt)
+	match, err := s.Matches.Create(ctx, in.GetSeriesId(), playerAId, playerBId, scoreA, scoreB, playedAt, s.reporterID(ctx))
 	if err != nil {
 		return nil, status.Error(codes.Internal, "MATCH_CREATE_FAILED")
 	}
 
+	s.invalidateSnapshots(ctx, match.SeriesID, match.PlayedAt)
+	s.inspectForAbuse(ctx, series, match)
+	ranks := s.ladderSnapshot(ctx, series)
+
 	// Recalculate and store leaderboard
 	if err := s.RecalculateStandings(ctx, in.GetSeriesId()); err != nil {
 		log.Error().Err(err).Str("seriesID", in.GetSeriesId()).Msg("Failed to recalculate standings")
 		// Don't fail the match creation, just log the error
+		ranks = nil
 	}
 
+	s.notifyMatchReported(ctx, series, match, ranks)
+
 	return &pb.ReportMatchV2Response{
 		MatchId: match.ID.Hex(),
 	}, nil
 }
 
 func (s *MatchService) ListMatches(ctx context.Context, in *pb.ListMatchesRequest) (*pb.ListMatchesResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
 	matches, nextPageToken, err := s.Matches.ListBySeriesID(ctx, in.GetSeriesId(), in.GetPageSize(), in.GetCursorAfter())
 	if err != nil {
 		return nil, status.Error(codes.Internal, "MATCH_LIST_FAILED")
@@ -529,6 +816,17 @@ func (s *MatchService) UpdateMatch(ctx context.Context, in *pb.UpdateMatchReques
 		return nil, status.Error(codes.NotFound, "MATCH_NOT_FOUND")
 	}
 
+	series, err := s.Series.FindByID(ctx, existingMatch.SeriesID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+	if err := checkAPIKeyClub(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+	if err := checkMatchEditor(ctx, s.Players, series, existingMatch); err != nil {
+		return nil, err
+	}
+
 	// Extract optional fields
 	var scoreA, scoreB *int32
 	var playedAt *time.Time
@@ -544,21 +842,8 @@ func (s *MatchService) UpdateMatch(ctx context.Context, in *pb.UpdateMatchReques
 		playedAt = &t
 
 		// Validate that updated match date is within series time window
-		series, err := s.Series.FindByID(ctx, existingMatch.SeriesID)
-		if err != nil {
-			return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
-		}
-
-		// Convert series dates to inclusive ranges
-		seriesStart := series.StartsAt.Truncate(24 * time.Hour)                                 // Start of start date
-		seriesEnd := series.EndsAt.Truncate(24 * time.Hour).Add(24*time.Hour - time.Nanosecond) // End of end date
-
-		if t.Before(seriesStart) || t.After(seriesEnd) {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"match date %v must be between series start date %v and end date %v (inclusive)",
-				t.Format("2006-01-02"),
-				series.StartsAt.Format("2006-01-02"),
-				series.EndsAt.Format("2006-01-02"))
+		if err := validateMatchTimeWindow(t, series.StartsAt, series.EndsAt); err != nil {
+			return nil, err
 		}
 	}
 
@@ -579,12 +864,34 @@ func (s *MatchService) UpdateMatch(ctx context.Context, in *pb.UpdateMatchReques
 		}
 	}
 
+	// Evaluate rules against the match as it will look after the update
+	newScoreA, newScoreB, newPlayedAt := existingMatch.ScoreA, existingMatch.ScoreB, existingMatch.PlayedAt
+	if scoreA != nil {
+		newScoreA = *scoreA
+	}
+	if scoreB != nil {
+		newScoreB = *scoreB
+	}
+	if playedAt != nil {
+		newPlayedAt = *playedAt
+	}
+	if err := s.evaluateMatchRules(ctx, series, existingMatch.ID.Hex(), existingMatch.PlayerAID, existingMatch.PlayerBID, newScoreA, newScoreB, newPlayedAt); err != nil {
+		return nil, err
+	}
+
 	// Update the match
 	updatedMatch, err := s.Matches.Update(ctx, in.GetMatchId(), scoreA, scoreB, playedAt)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "MATCH_UPDATE_FAILED")
 	}
 
+	// Snapshots from before either date are unaffected
+	invalidFrom := existingMatch.PlayedAt
+	if updatedMatch.PlayedAt.Before(invalidFrom) {
+		invalidFrom = updatedMatch.PlayedAt
+	}
+	s.invalidateSnapshots(ctx, updatedMatch.SeriesID, invalidFrom)
+
 	// Recalculate and store leaderboard
 	if err := s.RecalculateStandings(ctx, updatedMatch.SeriesID); err != nil {
 		log.Error().Err(err).Str("seriesID", updatedMatch.SeriesID).Msg("Failed to recalculate standings")
@@ -631,12 +938,25 @@ func (s *MatchService) DeleteMatch(ctx context.Context, in *pb.DeleteMatchReques
 		return nil, status.Error(codes.NotFound, "MATCH_NOT_FOUND")
 	}
 
+	series, err := s.Series.FindByID(ctx, match.SeriesID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+	if err := checkAPIKeyClub(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+	if err := checkMatchEditor(ctx, s.Players, series, match); err != nil {
+		return nil, err
+	}
+
 	// Delete the match
 	err = s.Matches.Delete(ctx, in.GetMatchId())
 	if err != nil {
 		return nil, status.Error(codes.Internal, "MATCH_DELETE_FAILED")
 	}
 
+	s.invalidateSnapshots(ctx, match.SeriesID, match.PlayedAt)
+
 	// Recalculate and store leaderboard
 	if err := s.RecalculateStandings(ctx, match.SeriesID); err != nil {
 		log.Error().Err(err).Str("seriesID", match.SeriesID).Msg("Failed to recalculate standings")
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// NotificationService manages a player's notification opt-ins
+type NotificationService struct {
+	pb.UnimplementedNotificationServiceServer
+	Players  *repo.PlayerRepo
+	Notifier *Notifier // Verifies unsubscribe tokens
+}
+
+var notificationTypeToProto = map[string]pb.NotificationType{
+	repo.NotificationMatchReported:   pb.NotificationType_NOTIFICATION_TYPE_MATCH_REPORTED,
+	repo.NotificationLadderOvertaken: pb.NotificationType_NOTIFICATION_TYPE_LADDER_OVERTAKEN,
+	repo.NotificationSeriesEnding:    pb.NotificationType_NOTIFICATION_TYPE_SERIES_ENDING,
+	repo.NotificationSeriesSummary:   pb.NotificationType_NOTIFICATION_TYPE_SERIES_SUMMARY,
+}
+
+// GetNotificationPreferences returns the caller's notification preferences
+func (s *NotificationService) GetNotificationPreferences(ctx context.Context, req *pb.GetNotificationPreferencesRequest) (*pb.GetNotificationPreferencesResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetNotificationPreferencesResponse{
+		Preferences: notificationPreferencesToProto(player.Notifications),
+	}, nil
+}
+
+// UpdateNotificationPreferences replaces the caller's notification preferences
+func (s *NotificationService) UpdateNotificationPreferences(ctx context.Context, req *pb.UpdateNotificationPreferencesRequest) (*pb.UpdateNotificationPreferencesResponse, error) {
+	player, err := s.currentPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !player.CanLogin() {
+		return nil, status.Error(codes.FailedPrecondition, "EMAIL_REQUIRED_FOR_NOTIFICATIONS")
+	}
+
+	prefs := req.GetPreferences()
+	updated, err := s.Players.SetNotificationPreferences(ctx, player.ID, repo.NotificationPreferences{
+		MatchReported:   prefs.GetMatchReported(),
+		LadderOvertaken: prefs.GetLadderOvertaken(),
+		SeriesEnding:    prefs.GetSeriesEnding(),
+		SeriesSummary:   prefs.GetSeriesSummary(),
+		DailyDigest:     prefs.GetDailyDigest(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "FAILED_TO_UPDATE_NOTIFICATION_PREFERENCES")
+	}
+
+	return &pb.UpdateNotificationPreferencesResponse{
+		Preferences: notificationPreferencesToProto(updated.Notifications),
+	}, nil
+}
+
+// Unsubscribe turns off notifications for the player a signed email link was sent to
+func (s *NotificationService) Unsubscribe(ctx context.Context, req *pb.UnsubscribeRequest) (*pb.UnsubscribeResponse, error) {
+	playerID, notificationType, err := s.Notifier.parseUnsubscribeToken(req.GetToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "INVALID_UNSUBSCRIBE_TOKEN")
+	}
+	objID, err := primitive.ObjectIDFromHex(playerID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "INVALID_UNSUBSCRIBE_TOKEN")
+	}
+
+	if err := s.Players.DisableNotification(ctx, objID, notificationType); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+		}
+		return nil, status.Error(codes.Internal, "FAILED_TO_UPDATE_NOTIFICATION_PREFERENCES")
+	}
+
+	log.Info().Str("playerID", playerID).Str("type", notificationType).Msg("Player unsubscribed from notifications")
+
+	return &pb.UnsubscribeResponse{
+		NotificationType: notificationTypeToProto[notificationType],
+	}, nil
+}
+
+func (s *NotificationService) currentPlayer(ctx context.Context) (*repo.Player, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return nil, status.Error(codes.Unauthenticated, "NOT_AUTHENTICATED")
+	}
+
+	player, err := s.Players.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+	return player, nil
+}
+
+func notificationPreferencesToProto(prefs *repo.NotificationPreferences) *pb.NotificationPreferences {
+	if prefs == nil {
+		return &pb.NotificationPreferences{}
+	}
+	return &pb.NotificationPreferences{
+		MatchReported:   prefs.MatchReported,
+		LadderOvertaken: prefs.LadderOvertaken,
+		SeriesEnding:    prefs.SeriesEnding,
+		SeriesSummary:   prefs.SeriesSummary,
+		DailyDigest:     prefs.DailyDigest,
+	}
+}
@@ -0,0 +1,472 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/goencoder/klubbspel/backend/internal/email"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// Notification scheduling defaults
+const (
+	seriesEndingNotice = 3 * 24 * time.Hour // How long before the end players are reminded
+	notifierInterval   = time.Hour
+	defaultDigestHour  = 7 // UTC hour when daily digests go out
+)
+
+// errInvalidUnsubscribeToken is returned for tampered or malformed unsubscribe tokens
+var errInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// Notifier tells players about match reports, ladder changes and ending
+// series they opted in to, either immediately or in a daily digest.
+// Notifying never fails the write that triggered it.
+type Notifier struct {
+	Players       *repo.PlayerRepo
+	Series        *repo.SeriesRepo
+	Leaderboard   *repo.LeaderboardRepo
+	Notifications *repo.NotificationRepo
+	EmailSvc      email.Service
+	BaseURL       string // Frontend URL used for links in emails
+	Secret        []byte // Signs unsubscribe links
+	DigestHour    int    // UTC hour for the daily digest; defaults to 07
+	Now           func() time.Time
+}
+
+// notificationEvent is one notification for one player
+type notificationEvent struct {
+	Type     string
+	URL      string
+	Data     map[string]string
+	DedupKey string
+}
+
+// MatchReported notifies the players of a newly reported match, except the
+// player who reported it
+func (n *Notifier) MatchReported(ctx context.Context, series *repo.Series, match *repo.Match) {
+	players, err := n.Players.FindByIDs(ctx, []string{match.PlayerAID, match.PlayerBID})
+	if err != nil {
+		log.Error().Err(err).Str("matchID", match.ID.Hex()).Msg("Failed to load players for match notification")
+		return
+	}
+
+	reporterName := ""
+	if reporter, ok := players[match.ReportedBy]; ok {
+		reporterName = reporter.DisplayName
+	} else if match.ReportedBy != "" {
+		if reporter, err := n.Players.FindByID(ctx, match.ReportedBy); err == nil {
+			reporterName = reporter.DisplayName
+		}
+	}
+
+	sides := []struct {
+		playerID, opponentID string
+		score, opponentScore int32
+	}{
+		{match.PlayerAID, match.PlayerBID, match.ScoreA, match.ScoreB},
+		{match.PlayerBID, match.PlayerAID, match.ScoreB, match.ScoreA},
+	}
+	for _, side := range sides {
+		player, ok := players[side.playerID]
+		if !ok || side.playerID == match.ReportedBy {
+			continue
+		}
+		opponentName := ""
+		if opponent, ok := players[side.opponentID]; ok {
+			opponentName = opponent.DisplayName
+		}
+		// Matches reported without a known reporter name the opponent
+		reportedBy := reporterName
+		if reportedBy == "" {
+			reportedBy = opponentName
+		}
+
+		n.notify(ctx, player, notificationEvent{
+			Type: repo.NotificationMatchReported,
+			URL:  n.seriesURL(series, false),
+			Data: map[string]string{
+				"ReporterName": reportedBy,
+				"OpponentName": opponentName,
+				"SeriesTitle":  series.Title,
+				"Score":        fmt.Sprintf("%d–%d", side.score, side.opponentScore),
+			},
+			DedupKey: fmt.Sprintf("%s:%s:%s", repo.NotificationMatchReported, match.ID.Hex(), side.playerID),
+		})
+	}
+}
+
+// LadderSnapshot captures the current ranks of a ladder series so
+// LadderOvertaken can tell who lost positions. Returns nil for other formats.
+func (n *Notifier) LadderSnapshot(ctx context.Context, series *repo.Series) map[string]int32 {
+	if series.Format != int32(pb.SeriesFormat_SERIES_FORMAT_LADDER) {
+		return nil
+	}
+	return n.ranks(ctx, series.ID.Hex())
+}
+
+// LadderOvertaken notifies players who were passed on the ladder by one of the
+// players in match. before comes from LadderSnapshot.
+func (n *Notifier) LadderOvertaken(ctx context.Context, series *repo.Series, match *repo.Match, before map[string]int32) {
+	if before == nil {
+		return
+	}
+	after := n.ranks(ctx, series.ID.Hex())
+
+	overtaken := overtakenBy(match, before, after)
+	if len(overtaken) == 0 {
+		return
+	}
+
+	ids := []string{match.PlayerAID, match.PlayerBID}
+	for playerID := range overtaken {
+		ids = append(ids, playerID)
+	}
+	players, err := n.Players.FindByIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to load players for ladder notification")
+		return
+	}
+
+	for playerID, moverID := range overtaken {
+		player, ok := players[playerID]
+		if !ok {
+			continue
+		}
+		overtakerName := ""
+		if mover, ok := players[moverID]; ok {
+			overtakerName = mover.DisplayName
+		}
+		n.notify(ctx, player, notificationEvent{
+			Type: repo.NotificationLadderOvertaken,
+			URL:  n.seriesURL(series, true),
+			Data: map[string]string{
+				"OvertakerName":    overtakerName,
+				"SeriesTitle":      series.Title,
+				"Position":         fmt.Sprint(after[playerID]),
+				"PreviousPosition": fmt.Sprint(before[playerID]),
+			},
+			DedupKey: fmt.Sprintf("%s:%s:%s", repo.NotificationLadderOvertaken, match.ID.Hex(), playerID),
+		})
+	}
+}
+
+// overtakenBy returns player ID -> overtaker ID for the players who dropped
+// in rank because one of the players in match passed them
+func overtakenBy(match *repo.Match, before, after map[string]int32) map[string]string {
+	overtaken := map[string]string{}
+	for playerID, rank := range after {
+		previous, ok := before[playerID]
+		if !ok || rank <= previous || playerID == match.PlayerAID || playerID == match.PlayerBID {
+			continue
+		}
+		for _, moverID := range []string{match.PlayerAID, match.PlayerBID} {
+			moverBefore, wasRanked := before[moverID]
+			moverAfter, isRanked := after[moverID]
+			if isRanked && (!wasRanked || moverBefore > previous) && moverAfter < rank {
+				overtaken[playerID] = moverID
+				break
+			}
+		}
+	}
+	return overtaken
+}
+
+// SeriesEnding reminds the players in series that it ends soon. Each player is
+// reminded once per end date, so moving the end date sends a new reminder.
+func (n *Notifier) SeriesEnding(ctx context.Context, series *repo.Series) {
+	now := n.now()
+	if series.EndsAt.Before(now) || series.EndsAt.Sub(now) > seriesEndingNotice {
+		return
+	}
+
+	entries, err := n.Leaderboard.FindBySeriesOrdered(ctx, series.ID.Hex())
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to load standings for series ending notification")
+		return
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.PlayerID
+	}
+	players, err := n.Players.FindByIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to load players for series ending notification")
+		return
+	}
+
+	endDate := series.EndsAt.UTC().Format("2006-01-02")
+	for _, entry := range entries {
+		player, ok := players[entry.PlayerID]
+		if !ok {
+			continue
+		}
+		n.notify(ctx, player, notificationEvent{
+			Type: repo.NotificationSeriesEnding,
+			URL:  n.seriesURL(series, true),
+			Data: map[string]string{
+				"SeriesTitle": series.Title,
+				"EndDate":     endDate,
+				"Position":    fmt.Sprint(entry.Rank),
+			},
+			DedupKey: fmt.Sprintf("%s:%s:%s:%s", repo.NotificationSeriesEnding, series.ID.Hex(), endDate, entry.PlayerID),
+		})
+	}
+}
+
+// NotifyEndingSeries sends reminders for every series ending soon
+func (n *Notifier) NotifyEndingSeries(ctx context.Context) error {
+	now := n.now()
+	series, err := n.Series.ListEndingBetween(ctx, now, now.Add(seriesEndingNotice))
+	if err != nil {
+		return err
+	}
+	for _, s := range series {
+		n.SeriesEnding(ctx, s)
+	}
+	return nil
+}
+
+// SeriesSummary tells the players in the final standings of an ended series
+// that its season summary is ready. Each player is told once per series.
+func (n *Notifier) SeriesSummary(ctx context.Context, series *repo.Series, summary *repo.SeriesSummary) {
+	entries, err := n.Leaderboard.FindBySeriesOrdered(ctx, series.ID.Hex())
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to load standings for series summary notification")
+		return
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.PlayerID
+	}
+	players, err := n.Players.FindByIDs(ctx, ids)
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to load players for series summary notification")
+		return
+	}
+
+	championName := ""
+	if summary.Champion != nil {
+		if champion, ok := players[summary.Champion.PlayerID]; ok {
+			championName = champion.DisplayName
+		}
+	}
+
+	for _, entry := range entries {
+		player, ok := players[entry.PlayerID]
+		if !ok {
+			continue
+		}
+		n.notify(ctx, player, notificationEvent{
+			Type: repo.NotificationSeriesSummary,
+			URL:  n.seriesURL(series, true),
+			Data: map[string]string{
+				"SeriesTitle":  series.Title,
+				"ChampionName": championName,
+				"Position":     fmt.Sprint(entry.Rank),
+			},
+			DedupKey: fmt.Sprintf("%s:%s:%s", repo.NotificationSeriesSummary, series.ID.Hex(), entry.PlayerID),
+		})
+	}
+}
+
+// SendDigests emails every player their pending digest notifications as one
+// summary and returns how many digests were sent
+func (n *Notifier) SendDigests(ctx context.Context) (int, error) {
+	pending, err := n.Notifications.ClaimDigest(ctx, n.now())
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	byPlayer := map[string][]email.DigestItem{}
+	var order []string
+	for _, notification := range pending {
+		if _, ok := byPlayer[notification.PlayerID]; !ok {
+			order = append(order, notification.PlayerID)
+		}
+		byPlayer[notification.PlayerID] = append(byPlayer[notification.PlayerID], email.DigestItem{
+			Template: notification.Type,
+			URL:      notification.URL,
+			Data:     notification.Data,
+		})
+	}
+
+	players, err := n.Players.FindByIDs(ctx, order)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, playerID := range order {
+		player, ok := players[playerID]
+		if !ok || !notifiable(player) {
+			continue
+		}
+		// Drop items the player opted out of after they were queued
+		var items []email.DigestItem
+		for _, item := range byPlayer[playerID] {
+			if player.Notifications.Enabled(item.Template) {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		if err := n.EmailSvc.SendDigest(ctx, player.Email, player.Locale, items, n.unsubscribeToken(playerID, "")); err != nil {
+			log.Error().Err(err).Str("playerID", playerID).Msg("Failed to send notification digest")
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Run reminds players of ending series every hour and sends the daily digests
+// at DigestHour until ctx is cancelled
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(notifierInterval)
+	defer ticker.Stop()
+
+	digestHour := n.DigestHour
+	if digestHour <= 0 || digestHour > 23 {
+		digestHour = defaultDigestHour
+	}
+
+	for {
+		if err := n.NotifyEndingSeries(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("Series ending notifications failed")
+		}
+		if n.now().UTC().Hour() == digestHour {
+			if _, err := n.SendDigests(ctx); err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("Notification digests failed")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notify records the event for player and emails it right away unless the
+// player gets a daily digest. Players who did not opt in are skipped.
+func (n *Notifier) notify(ctx context.Context, player *repo.Player, event notificationEvent) {
+	if !notifiable(player) || !player.Notifications.Enabled(event.Type) {
+		return
+	}
+
+	now := n.now()
+	notification := &repo.Notification{
+		PlayerID:  player.ID.Hex(),
+		Type:      event.Type,
+		URL:       event.URL,
+		Data:      event.Data,
+		DedupKey:  event.DedupKey,
+		Digest:    player.Notifications.DailyDigest,
+		CreatedAt: now,
+	}
+	if !notification.Digest {
+		notification.DeliveredAt = &now
+	}
+
+	if err := n.Notifications.Create(ctx, notification); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			log.Error().Err(err).Str("playerID", notification.PlayerID).Str("type", event.Type).Msg("Failed to store notification")
+		}
+		return
+	}
+	if notification.Digest {
+		return
+	}
+
+	token := n.unsubscribeToken(notification.PlayerID, event.Type)
+	if err := n.EmailSvc.SendNotification(ctx, player.Email, player.Locale, event.Type, event.URL, event.Data, token); err != nil {
+		log.Error().Err(err).Str("playerID", notification.PlayerID).Str("type", event.Type).Msg("Failed to send notification")
+	}
+}
+
+// ranks returns player ID -> rank for a series
+func (n *Notifier) ranks(ctx context.Context, seriesID string) map[string]int32 {
+	entries, err := n.Leaderboard.FindBySeriesOrdered(ctx, seriesID)
+	if err != nil {
+		log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to load standings for ladder notification")
+		return nil
+	}
+	ranks := make(map[string]int32, len(entries))
+	for _, entry := range entries {
+		ranks[entry.PlayerID] = entry.Rank
+	}
+	return ranks
+}
+
+func (n *Notifier) seriesURL(series *repo.Series, leaderboard bool) string {
+	url := fmt.Sprintf("%s/series/%s", n.BaseURL, series.ID.Hex())
+	if leaderboard {
+		url += "/leaderboard"
+	}
+	return url
+}
+
+func (n *Notifier) now() time.Time {
+	if n.Now != nil {
+		return n.Now()
+	}
+	return time.Now().UTC()
+}
+
+// notifiable reports whether player has a real mailbox and may receive email
+func notifiable(player *repo.Player) bool {
+	return player.CanLogin() && !player.Disabled
+}
+
+// unsubscribeToken signs a one-click unsubscribe link for one notification
+// type, or for all of them when notificationType is empty
+func (n *Notifier) unsubscribeToken(playerID, notificationType string) string {
+	payload := playerID + ":" + notificationType
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(n.sign(payload))
+}
+
+// parseUnsubscribeToken verifies an unsubscribe token and returns the player
+// and notification type it was issued for
+func (n *Notifier) parseUnsubscribeToken(token string) (playerID, notificationType string, err error) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, n.sign(string(payload))) {
+		return "", "", errInvalidUnsubscribeToken
+	}
+
+	playerID, notificationType, ok = strings.Cut(string(payload), ":")
+	if !ok || playerID == "" {
+		return "", "", errInvalidUnsubscribeToken
+	}
+	return playerID, notificationType, nil
+}
+
+func (n *Notifier) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, n.Secret)
+	mac.Write([]byte("unsubscribe:" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/goencoder/klubbspel/backend/internal/email"
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// recordingEmail records the notification emails it is asked to send
+type recordingEmail struct {
+	email.Service
+	notifications []string            // Recipients of immediate notifications
+	digests       map[string][]string // Recipient -> digest item templates
+}
+
+func (e *recordingEmail) SendNotification(_ context.Context, toEmail, _, _, _ string, _ map[string]string, _ string) error {
+	e.notifications = append(e.notifications, toEmail)
+	return nil
+}
+
+func (e *recordingEmail) SendDigest(_ context.Context, toEmail, _ string, items []email.DigestItem, _ string) error {
+	if e.digests == nil {
+		e.digests = map[string][]string{}
+	}
+	for _, item := range items {
+		e.digests[toEmail] = append(e.digests[toEmail], item.Template)
+	}
+	return nil
+}
+
+var notifyEvent = notificationEvent{Type: repo.NotificationMatchReported, DedupKey: "match_reported:m1:p1"}
+
+func notifyPlayer(prefs *repo.NotificationPreferences) *repo.Player {
+	return &repo.Player{ID: primitive.NewObjectID(), Email: "anna@example.com", Notifications: prefs}
+}
+
+func TestUnsubscribeTokenRoundTrip(t *testing.T) {
+	n := &Notifier{Secret: []byte("secret")}
+
+	playerID, notificationType, err := n.parseUnsubscribeToken(n.unsubscribeToken("player-1", repo.NotificationLadderOvertaken))
+	require.NoError(t, err)
+	require.Equal(t, "player-1", playerID)
+	require.Equal(t, repo.NotificationLadderOvertaken, notificationType)
+
+	// An empty type unsubscribes from everything
+	_, notificationType, err = n.parseUnsubscribeToken(n.unsubscribeToken("player-1", ""))
+	require.NoError(t, err)
+	require.Empty(t, notificationType)
+}
+
+func TestUnsubscribeTokenRejectsTampering(t *testing.T) {
+	n := &Notifier{Secret: []byte("secret")}
+	token := n.unsubscribeToken("player-1", repo.NotificationMatchReported)
+	_, mac, _ := strings.Cut(token, ".")
+
+	forged := (&Notifier{Secret: []byte("secret")}).unsubscribeToken("player-2", repo.NotificationMatchReported)
+	payload, _, _ := strings.Cut(forged, ".")
+
+	for _, bad := range []string{
+		"",
+		"no-dot",
+		payload + "." + mac, // another player's payload with this MAC
+		(&Notifier{Secret: []byte("other")}).unsubscribeToken("player-1", repo.NotificationMatchReported),
+	} {
+		_, _, err := n.parseUnsubscribeToken(bad)
+		require.ErrorIs(t, err, errInvalidUnsubscribeToken, bad)
+	}
+}
+
+func TestNotificationPreferencesAreOptIn(t *testing.T) {
+	var prefs *repo.NotificationPreferences
+	require.False(t, prefs.Enabled(repo.NotificationMatchReported))
+
+	prefs = &repo.NotificationPreferences{SeriesEnding: true}
+	require.True(t, prefs.Enabled(repo.NotificationSeriesEnding))
+	require.False(t, prefs.Enabled(repo.NotificationLadderOvertaken))
+	require.False(t, prefs.Enabled("unknown"))
+}
+
+func TestNotifyRespectsOptIn(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not opted in", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), EmailSvc: mailer}
+		mt.ClearEvents()
+
+		n.notify(context.Background(), notifyPlayer(&repo.NotificationPreferences{SeriesEnding: true}), notifyEvent)
+		n.notify(context.Background(), notifyPlayer(nil), notifyEvent)
+		require.Nil(mt, mt.GetStartedEvent())
+		require.Empty(mt, mailer.notifications)
+	})
+
+	mt.Run("emailless player", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), EmailSvc: mailer}
+		mt.ClearEvents()
+
+		player := notifyPlayer(&repo.NotificationPreferences{MatchReported: true})
+		player.Email = "noemail-" + player.ID.Hex() + "@klubbspel.internal"
+		n.notify(context.Background(), player, notifyEvent)
+		require.Nil(mt, mt.GetStartedEvent())
+		require.Empty(mt, mailer.notifications)
+	})
+}
+
+func TestNotifyImmediateOrDigest(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mt.Run("immediate", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), EmailSvc: mailer, Now: func() time.Time { return now }}
+		mt.ClearEvents()
+
+		n.notify(context.Background(), notifyPlayer(&repo.NotificationPreferences{MatchReported: true}), notifyEvent)
+		inserted := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		require.False(mt, inserted.Lookup("digest").Boolean())
+		require.Equal(mt, now.UnixMilli(), inserted.Lookup("delivered_at").Time().UnixMilli())
+		require.Equal(mt, []string{"anna@example.com"}, mailer.notifications)
+	})
+
+	mt.Run("daily digest", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), EmailSvc: mailer, Now: func() time.Time { return now }}
+		mt.ClearEvents()
+
+		n.notify(context.Background(), notifyPlayer(&repo.NotificationPreferences{MatchReported: true, DailyDigest: true}), notifyEvent)
+		inserted := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		require.True(mt, inserted.Lookup("digest").Boolean())
+		_, err := inserted.LookupErr("delivered_at")
+		require.Error(mt, err)
+		require.Empty(mt, mailer.notifications)
+	})
+
+	mt.Run("already notified", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index: 0, Code: 11000, Message: "E11000 duplicate key error collection: notifications index: dedup_key_1",
+		}))
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), EmailSvc: mailer}
+
+		n.notify(context.Background(), notifyPlayer(&repo.NotificationPreferences{MatchReported: true}), notifyEvent)
+		require.Empty(mt, mailer.notifications)
+	})
+}
+
+func TestSendDigestsDropsOptedOutItems(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("digest", func(mt *mtest.T) {
+		anna := primitive.NewObjectID()
+		bo := primitive.NewObjectID()
+		notifications := mt.DB.Name() + ".notifications"
+		players := mt.DB.Name() + ".players"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Notification indexes
+			mtest.CreateSuccessResponse(), // Player indexes
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}, bson.E{Key: "nModified", Value: 3}),
+			mtest.CreateCursorResponse(0, notifications, mtest.FirstBatch,
+				bson.D{{Key: "player_id", Value: anna.Hex()}, {Key: "type", Value: repo.NotificationMatchReported}, {Key: "digest", Value: true}},
+				bson.D{{Key: "player_id", Value: anna.Hex()}, {Key: "type", Value: repo.NotificationLadderOvertaken}, {Key: "digest", Value: true}},
+				bson.D{{Key: "player_id", Value: bo.Hex()}, {Key: "type", Value: repo.NotificationSeriesEnding}, {Key: "digest", Value: true}},
+			),
+			mtest.CreateCursorResponse(0, players, mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: anna}, {Key: "email", Value: "anna@example.com"}, {Key: "notifications", Value: bson.D{
+					{Key: "match_reported", Value: true}, {Key: "daily_digest", Value: true},
+				}}},
+				// Bo opted out after the item was queued
+				bson.D{{Key: "_id", Value: bo}, {Key: "email", Value: "bo@example.com"}},
+			),
+		)
+		mailer := &recordingEmail{}
+		n := &Notifier{Notifications: repo.NewNotificationRepo(mt.DB), Players: repo.NewPlayerRepo(mt.DB), EmailSvc: mailer}
+
+		sent, err := n.SendDigests(context.Background())
+		require.NoError(mt, err)
+		require.Equal(mt, 1, sent)
+		require.Equal(mt, map[string][]string{"anna@example.com": {repo.NotificationMatchReported}}, mailer.digests)
+		require.Empty(mt, mailer.notifications)
+	})
+}
+
+func TestOvertakenBy(t *testing.T) {
+	match := &repo.Match{PlayerAID: "a", PlayerBID: "b"}
+
+	tests := []struct {
+		name          string
+		before, after map[string]int32
+		expected      map[string]string
+	}{
+		{
+			name:     "winner climbs past two players",
+			before:   map[string]int32{"x": 1, "y": 2, "a": 3, "b": 4},
+			after:    map[string]int32{"a": 1, "x": 2, "y": 3, "b": 4},
+			expected: map[string]string{"x": "a", "y": "a"},
+		},
+		{
+			name:     "players in the match are not notified",
+			before:   map[string]int32{"b": 1, "a": 2},
+			after:    map[string]int32{"a": 1, "b": 2},
+			expected: map[string]string{},
+		},
+		{
+			name:     "dropping below a player who was already ahead",
+			before:   map[string]int32{"a": 1, "x": 2, "y": 3},
+			after:    map[string]int32{"a": 1, "y": 2, "x": 3},
+			expected: map[string]string{},
+		},
+		{
+			name:     "newcomer entering above",
+			before:   map[string]int32{"x": 1},
+			after:    map[string]int32{"b": 1, "x": 2, "a": 3},
+			expected: map[string]string{"x": "b"},
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, overtakenBy(match, test.before, test.after), test.name)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Permission is a club-scoped capability granted through a membership role
+type Permission string
+
+const (
+	PermViewMembers   Permission = "members.view"   // See the member list and club rules
+	PermViewAdminData Permission = "admin.view"     // Read-only access to admin views (join requests, invitations)
+	PermManageClub    Permission = "club.manage"    // Club settings, match rules and abuse thresholds
+	PermManageMembers Permission = "members.manage" // Invitations, join requests, roles and player creation
+	PermManageSeries  Permission = "series.manage"  // Create, edit, clone and delete series; review entrants
+	PermManageMatches Permission = "matches.manage" // Report, edit and delete any club match; review flagged matches
+	PermManageBilling Permission = "billing.manage" // Club billing
+)
+
+// rolePermissions is the permission matrix for club roles. Platform owners have every permission.
+var rolePermissions = map[string][]Permission{
+	repo.ClubRoleAdmin: {
+		PermViewMembers, PermViewAdminData, PermManageClub, PermManageMembers,
+		PermManageSeries, PermManageMatches, PermManageBilling,
+	},
+	repo.ClubRoleMatchSecretary: {PermViewMembers, PermManageMatches},
+	repo.ClubRoleSeriesManager:  {PermViewMembers, PermManageSeries},
+	repo.ClubRoleTreasurer:      {PermViewMembers, PermManageBilling},
+	repo.ClubRoleCoach:          {PermViewMembers, PermViewAdminData},
+	repo.ClubRoleMember:         {PermViewMembers},
+}
+
+// RoleHasPermission reports whether a club role grants a permission
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// requireClubPermission checks that the caller holds a permission in the club
+func requireClubPermission(ctx context.Context, clubID string, permission Permission) error {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	allowed, err := subject.HasClubPermission(ctx, clubID, permission)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !allowed {
+		return status.Error(codes.PermissionDenied, "CLUB_PERMISSION_REQUIRED")
+	}
+	return nil
+}
+
+var membershipRoles = map[string]pb.MembershipRole{
+	repo.ClubRoleMember:         pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER,
+	repo.ClubRoleAdmin:          pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN,
+	repo.ClubRoleMatchSecretary: pb.MembershipRole_MEMBERSHIP_ROLE_MATCH_SECRETARY,
+	repo.ClubRoleSeriesManager:  pb.MembershipRole_MEMBERSHIP_ROLE_SERIES_MANAGER,
+	repo.ClubRoleTreasurer:      pb.MembershipRole_MEMBERSHIP_ROLE_TREASURER,
+	repo.ClubRoleCoach:          pb.MembershipRole_MEMBERSHIP_ROLE_COACH,
+}
+
+// membershipRoleToProto maps a stored role to the API enum; unknown roles read as member
+func membershipRoleToProto(role string) pb.MembershipRole {
+	if pbRole, ok := membershipRoles[role]; ok {
+		return pbRole
+	}
+	return pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
+}
+
+// membershipRoleFromProto maps the API enum to a stored role; unspecified means member
+func membershipRoleFromProto(role pb.MembershipRole) string {
+	for value, pbRole := range membershipRoles {
+		if pbRole == role {
+			return value
+		}
+	}
+	return repo.ClubRoleMember
+}
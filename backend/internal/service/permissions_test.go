@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func TestRoleHasPermission(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission Permission
+		expected   bool
+	}{
+		{repo.ClubRoleAdmin, PermManageMembers, true},
+		{repo.ClubRoleAdmin, PermManageBilling, true},
+		{repo.ClubRoleMatchSecretary, PermManageMatches, true},
+		{repo.ClubRoleMatchSecretary, PermManageSeries, false},
+		{repo.ClubRoleSeriesManager, PermManageSeries, true},
+		{repo.ClubRoleSeriesManager, PermManageMembers, false},
+		{repo.ClubRoleTreasurer, PermManageBilling, true},
+		{repo.ClubRoleTreasurer, PermManageMatches, false},
+		{repo.ClubRoleCoach, PermViewAdminData, true},
+		{repo.ClubRoleCoach, PermManageClub, false},
+		{repo.ClubRoleMember, PermViewMembers, true},
+		{repo.ClubRoleMember, PermViewAdminData, false},
+		{"", PermViewMembers, false},
+		{"unknown", PermViewMembers, false},
+	}
+
+	for _, test := range tests {
+		if got := RoleHasPermission(test.role, test.permission); got != test.expected {
+			t.Errorf("RoleHasPermission(%q, %q) = %v, want %v", test.role, test.permission, got, test.expected)
+		}
+	}
+}
+
+func TestMembershipRoleRoundTrip(t *testing.T) {
+	for role := range rolePermissions {
+		if got := membershipRoleFromProto(membershipRoleToProto(role)); got != role {
+			t.Errorf("role %q round-tripped to %q", role, got)
+		}
+	}
+
+	if got := membershipRoleFromProto(pb.MembershipRole_MEMBERSHIP_ROLE_UNSPECIFIED); got != repo.ClubRoleMember {
+		t.Errorf("unspecified role mapped to %q, want %q", got, repo.ClubRoleMember)
+	}
+}
@@ -16,6 +16,7 @@ import (
 type PlayerService struct {
 	pb.UnimplementedPlayerServiceServer
 	Players *repo.PlayerRepo
+	Stats   *repo.PlayerStatsRepo
 }
 
 // convertToProtobuf converts a repo.Player to pb.Player
@@ -23,10 +24,7 @@ func (s *PlayerService) convertToProtobuf(p *repo.Player) *pb.Player {
 	// Convert club memberships
 	var clubMemberships []*pb.ClubMembership
 	for _, membership := range p.ClubMemberships {
-		role := pb.MembershipRole_MEMBERSHIP_ROLE_MEMBER
-		if membership.Role == "admin" {
-			role = pb.MembershipRole_MEMBERSHIP_ROLE_ADMIN
-		}
+		role := membershipRoleToProto(membership.Role)
 
 		clubMemberships = append(clubMemberships, &pb.ClubMembership{
 			ClubId:   membership.ClubID.Hex(),
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GetPlayerStats returns a player's record across the series the caller can see
+func (s *PlayerService) GetPlayerStats(ctx context.Context, in *pb.GetPlayerStatsRequest) (*pb.GetPlayerStatsResponse, error) {
+	if _, err := s.Players.FindByID(ctx, in.GetPlayerId()); err != nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+
+	stats, err := s.Stats.PlayerStats(ctx, in.GetPlayerId(), seriesViewer(ctx, s.Players))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "PLAYER_STATS_FAILED")
+	}
+
+	resp := &pb.GetPlayerStatsResponse{
+		PlayerId:          in.GetPlayerId(),
+		Overall:           sportRecordToProto(stats.Overall),
+		LongestWinStreak:  streakToProto(stats.LongestWin),
+		LongestLossStreak: streakToProto(stats.LongestLoss),
+		CurrentStreak:     stats.CurrentStreak,
+	}
+	for _, record := range stats.BySport {
+		resp.BySport = append(resp.BySport, sportRecordToProto(record))
+	}
+	for i := range stats.Form {
+		resp.Form = append(resp.Form, playerMatchToProto(&stats.Form[i]))
+	}
+	if stats.BestWin != nil {
+		resp.BestWin = playerMatchToProto(stats.BestWin)
+	}
+	return resp, nil
+}
+
+// GetHeadToHead returns the record between two players in the series the caller can see
+func (s *PlayerService) GetHeadToHead(ctx context.Context, in *pb.GetHeadToHeadRequest) (*pb.GetHeadToHeadResponse, error) {
+	if in.GetPlayerId() == in.GetOpponentId() {
+		return nil, status.Error(codes.InvalidArgument, "VALIDATION_SAME_PLAYER")
+	}
+
+	found, err := s.Players.FindByIDs(ctx, []string{in.GetPlayerId(), in.GetOpponentId()})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "PLAYER_LOOKUP_FAILED")
+	}
+	if found[in.GetPlayerId()] == nil || found[in.GetOpponentId()] == nil {
+		return nil, status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+
+	h2h, err := s.Stats.HeadToHead(ctx, in.GetPlayerId(), in.GetOpponentId(), seriesViewer(ctx, s.Players))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "PLAYER_STATS_FAILED")
+	}
+
+	resp := &pb.GetHeadToHeadResponse{
+		PlayerId:   in.GetPlayerId(),
+		OpponentId: in.GetOpponentId(),
+		Overall:    sportRecordToProto(h2h.Overall),
+	}
+	for _, record := range h2h.BySport {
+		resp.BySport = append(resp.BySport, sportRecordToProto(record))
+	}
+	for i := range h2h.Matches {
+		resp.Matches = append(resp.Matches, playerMatchToProto(&h2h.Matches[i]))
+	}
+	return resp, nil
+}
+
+func sportRecordToProto(r repo.SportRecord) *pb.SportRecord {
+	record := &pb.SportRecord{
+		Sport:         pb.Sport(r.Sport),
+		MatchesPlayed: r.Played,
+		MatchesWon:    r.Won,
+		MatchesLost:   r.Played - r.Won,
+	}
+	if r.Played > 0 {
+		played := float64(r.Played)
+		record.WinRate = float64(r.Won) / played * 100
+		record.AverageSetsWon = float64(r.SetsWon) / played
+		record.AverageSetsLost = float64(r.SetsLost) / played
+	}
+	return record
+}
+
+func streakToProto(run *repo.StreakRun) *pb.Streak {
+	if run == nil {
+		return nil
+	}
+	return &pb.Streak{
+		Length:    run.Length,
+		StartedAt: timestamppb.New(run.StartedAt),
+		EndedAt:   timestamppb.New(run.EndedAt),
+	}
+}
+
+func playerMatchToProto(m *repo.PlayerMatchRecord) *pb.PlayerMatch {
+	return &pb.PlayerMatch{
+		MatchId:               m.MatchID.Hex(),
+		SeriesId:              m.SeriesID,
+		SeriesTitle:           m.SeriesTitle,
+		Sport:                 pb.Sport(m.Sport),
+		OpponentId:            m.OpponentID,
+		OpponentName:          m.OpponentName,
+		SetsWon:               m.SetsWon,
+		SetsLost:              m.SetsLost,
+		Won:                   m.Won,
+		PlayedAt:              timestamppb.New(m.PlayedAt),
+		OpponentCurrentRating: m.OpponentCurrentRating,
+	}
+}
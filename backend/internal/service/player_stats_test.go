@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+func TestSportRecordWinRateIsPercentage(t *testing.T) {
+	record := sportRecordToProto(repo.SportRecord{Played: 8, Won: 6, SetsWon: 20, SetsLost: 10})
+	require.Equal(t, int32(2), record.GetMatchesLost())
+	require.InDelta(t, 75.0, record.GetWinRate(), 1e-9)
+	require.InDelta(t, 2.5, record.GetAverageSetsWon(), 1e-9)
+
+	require.Zero(t, sportRecordToProto(repo.SportRecord{}).GetWinRate())
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+// Scope limits which API methods an API key may call
+type Scope string
+
+const (
+	ScopeClubsRead        Scope = "clubs:read"
+	ScopePlayersRead      Scope = "players:read"
+	ScopeSeriesRead       Scope = "series:read"
+	ScopeSeriesWrite      Scope = "series:write"
+	ScopeMatchesRead      Scope = "matches:read"
+	ScopeMatchesWrite     Scope = "matches:write"
+	ScopeLeaderboardsRead Scope = "leaderboards:read"
+)
+
+// AllScopes lists every scope an API key can be granted
+var AllScopes = []Scope{
+	ScopeClubsRead, ScopePlayersRead, ScopeSeriesRead, ScopeSeriesWrite,
+	ScopeMatchesRead, ScopeMatchesWrite, ScopeLeaderboardsRead,
+}
+
+// scopePermissions are the club permissions a club key gains from its scopes.
+// Personal keys use their owner's club roles instead.
+var scopePermissions = map[Scope][]Permission{
+	ScopeSeriesWrite:  {PermManageSeries},
+	ScopeMatchesWrite: {PermManageMatches},
+}
+
+// ParseScopes validates and de-duplicates requested scopes
+func ParseScopes(requested []string) ([]string, error) {
+	seen := map[string]bool{}
+	var scopes []string
+	for _, raw := range requested {
+		scope := strings.ToLower(strings.TrimSpace(raw))
+		if !isKnownScope(Scope(scope)) {
+			return nil, status.Error(codes.InvalidArgument, "INVALID_API_KEY_SCOPE")
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "VALIDATION_SCOPES_REQUIRED")
+	}
+	return scopes, nil
+}
+
+func isKnownScope(scope Scope) bool {
+	for _, known := range AllScopes {
+		if known == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether an API key was granted a scope
+func HasScope(key *repo.APIKey, scope Scope) bool {
+	for _, granted := range key.Scopes {
+		if Scope(granted) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeySubject is the caller behind an API key. Personal keys act as their
+// owner, limited to the key's club when it has one; club keys act only in their
+// club with the permissions their scopes grant. API keys never carry platform
+// owner rights.
+type APIKeySubject struct {
+	*LazySubject
+	key *repo.APIKey
+}
+
+// NewAPIKeySubject creates the subject for a verified API key
+func NewAPIKeySubject(key *repo.APIKey, playerRepo *repo.PlayerRepo) *APIKeySubject {
+	email := key.OwnerEmail
+	return &APIKeySubject{
+		LazySubject: &LazySubject{email: &email, playerRepo: playerRepo},
+		key:         key,
+	}
+}
+
+// APIKey returns the key the request was made with
+func (s *APIKeySubject) APIKey() *repo.APIKey {
+	return s.key
+}
+
+// inClub reports whether the key may act in a club
+func (s *APIKeySubject) inClub(clubID string) bool {
+	return s.key.ClubID == "" || s.key.ClubID == clubID
+}
+
+// IsPlatformOwner is always false for API keys
+func (s *APIKeySubject) IsPlatformOwner(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// IsClubAdmin checks the owner's admin role; club keys are never admins
+func (s *APIKeySubject) IsClubAdmin(ctx context.Context, clubID string) (bool, error) {
+	if s.key.ClubLevel || !s.inClub(clubID) {
+		return false, nil
+	}
+	return s.LazySubject.IsClubAdmin(ctx, clubID)
+}
+
+// IsClubMember checks membership within the key's club
+func (s *APIKeySubject) IsClubMember(ctx context.Context, clubID string) (bool, error) {
+	if s.key.ClubLevel {
+		return s.key.ClubID == clubID, nil
+	}
+	if !s.inClub(clubID) {
+		return false, nil
+	}
+	return s.LazySubject.IsClubMember(ctx, clubID)
+}
+
+// GetClubMemberships returns the memberships the key may act in
+func (s *APIKeySubject) GetClubMemberships(ctx context.Context) ([]repo.ClubMembership, error) {
+	if s.key.ClubLevel {
+		clubID, err := primitive.ObjectIDFromHex(s.key.ClubID)
+		if err != nil {
+			return nil, nil
+		}
+		return []repo.ClubMembership{{ClubID: clubID, Role: repo.ClubRoleMember}}, nil
+	}
+
+	memberships, err := s.LazySubject.GetClubMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var allowed []repo.ClubMembership
+	for _, membership := range memberships {
+		if s.inClub(membership.ClubID.Hex()) {
+			allowed = append(allowed, membership)
+		}
+	}
+	return allowed, nil
+}
+
+// GetClubRole returns the owner's role within the key's club
+func (s *APIKeySubject) GetClubRole(ctx context.Context, clubID string) (string, error) {
+	if s.key.ClubLevel {
+		if s.key.ClubID == clubID {
+			return repo.ClubRoleMember, nil
+		}
+		return "", nil
+	}
+	if !s.inClub(clubID) {
+		return "", nil
+	}
+	return s.LazySubject.GetClubRole(ctx, clubID)
+}
+
+// GetAdminClubIDs returns the admin clubs the key may act in
+func (s *APIKeySubject) GetAdminClubIDs(ctx context.Context) ([]string, error) {
+	if s.key.ClubLevel {
+		return nil, nil
+	}
+
+	clubIDs, err := s.LazySubject.GetAdminClubIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var allowed []string
+	for _, clubID := range clubIDs {
+		if s.inClub(clubID) {
+			allowed = append(allowed, clubID)
+		}
+	}
+	return allowed, nil
+}
+
+// CanAccessClub checks membership within the key's club
+func (s *APIKeySubject) CanAccessClub(ctx context.Context, clubID string) (bool, error) {
+	return s.IsClubMember(ctx, clubID)
+}
+
+// CanManageClub checks the owner's admin role within the key's club
+func (s *APIKeySubject) CanManageClub(ctx context.Context, clubID string) (bool, error) {
+	return s.IsClubAdmin(ctx, clubID)
+}
+
+// HasClubPermission evaluates the owner's role, or the key's scopes for club keys
+func (s *APIKeySubject) HasClubPermission(ctx context.Context, clubID string, permission Permission) (bool, error) {
+	if !s.inClub(clubID) {
+		return false, nil
+	}
+
+	if s.key.ClubLevel {
+		for _, granted := range s.key.Scopes {
+			for _, scopePermission := range scopePermissions[Scope(granted)] {
+				if scopePermission == permission {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
+	role, err := s.LazySubject.GetClubRole(ctx, clubID)
+	if err != nil {
+		return false, err
+	}
+	return RoleHasPermission(role, permission), nil
+}
+
+// checkAPIKeyClub rejects API keys bound to another club. Used by write paths
+// whose permission checks do not otherwise involve the club, such as reporting
+// a match in an open series.
+func checkAPIKeyClub(ctx context.Context, clubID string) error {
+	subject, ok := GetSubjectFromContext(ctx).(*APIKeySubject)
+	if !ok || subject.key.ClubID == "" || subject.key.ClubID == clubID {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "API_KEY_CLUB_MISMATCH")
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+)
+
+const (
+	scoreboardClub = "64b7f0c2a1b2c3d4e5f60718"
+	otherClub      = "64b7f0c2a1b2c3d4e5f60719"
+)
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{" Matches:Write", "leaderboards:read", "matches:write"})
+	if err != nil {
+		t.Fatalf("ParseScopes: %v", err)
+	}
+	if len(scopes) != 2 || scopes[0] != "matches:write" || scopes[1] != "leaderboards:read" {
+		t.Errorf("unexpected scopes %v", scopes)
+	}
+
+	if _, err := ParseScopes([]string{"matches:admin"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown scope: got %v, want InvalidArgument", err)
+	}
+	if _, err := ParseScopes(nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no scopes: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestClubKeyPermissions(t *testing.T) {
+	ctx := context.Background()
+	subject := NewAPIKeySubject(&repo.APIKey{
+		ClubID:    scoreboardClub,
+		ClubLevel: true,
+		Scopes:    []string{string(ScopeMatchesWrite), string(ScopeLeaderboardsRead)},
+	}, nil)
+
+	tests := []struct {
+		clubID     string
+		permission Permission
+		expected   bool
+	}{
+		{scoreboardClub, PermManageMatches, true},
+		{scoreboardClub, PermManageSeries, false},
+		{scoreboardClub, PermManageClub, false},
+		{otherClub, PermManageMatches, false},
+	}
+	for _, test := range tests {
+		got, err := subject.HasClubPermission(ctx, test.clubID, test.permission)
+		if err != nil {
+			t.Fatalf("HasClubPermission: %v", err)
+		}
+		if got != test.expected {
+			t.Errorf("HasClubPermission(%s, %q) = %v, want %v", test.clubID, test.permission, got, test.expected)
+		}
+	}
+
+	if owner, _ := subject.IsPlatformOwner(ctx); owner {
+		t.Error("API keys must never act as platform owner")
+	}
+	if member, _ := subject.IsClubMember(ctx, otherClub); member {
+		t.Error("club key is a member of another club")
+	}
+}
+
+func TestCheckAPIKeyClub(t *testing.T) {
+	restricted := WithSubject(context.Background(), NewAPIKeySubject(&repo.APIKey{ClubID: scoreboardClub}, nil))
+	unrestricted := WithSubject(context.Background(), NewAPIKeySubject(&repo.APIKey{}, nil))
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		clubID   string
+		expected codes.Code
+	}{
+		{"same club", restricted, scoreboardClub, codes.OK},
+		{"other club", restricted, otherClub, codes.PermissionDenied},
+		{"open series", restricted, "", codes.PermissionDenied},
+		{"unrestricted key", unrestricted, otherClub, codes.OK},
+		{"session token", context.Background(), otherClub, codes.OK},
+	}
+	for _, test := range tests {
+		if got := status.Code(checkAPIKeyClub(test.ctx, test.clubID)); got != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkSeriesVisible returns an error unless the caller may view the series.
+// OPEN series are public; CLUB_ONLY series are limited to club members,
+// the series' guest players and platform owners.
+func checkSeriesVisible(ctx context.Context, players *repo.PlayerRepo, series *repo.Series) error {
+	if !series.IsClubOnly() {
+		return nil
+	}
+
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if isPlatformOwner {
+		return nil
+	}
+
+	if series.ClubID != "" {
+		isMember, err := subject.IsClubMember(ctx, series.ClubID)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+		}
+		if isMember {
+			return nil
+		}
+	}
+
+	if len(series.GuestPlayerIDs) > 0 {
+		player, err := players.FindByEmail(ctx, subject.GetEmail())
+		if err == nil && series.IsGuest(player.ID.Hex()) {
+			return nil
+		}
+	}
+
+	return status.Error(codes.PermissionDenied, "SERIES_CLUB_ONLY")
+}
+
+// checkSeriesParticipants ensures every player may take part in the series:
+// for CLUB_ONLY series each player must be a club member or a guest player
+func checkSeriesParticipants(ctx context.Context, players *repo.PlayerRepo, series *repo.Series, playerIDs ...string) error {
+	if !series.IsClubOnly() {
+		return nil
+	}
+
+	found, err := players.FindByIDs(ctx, playerIDs)
+	if err != nil {
+		return status.Error(codes.Internal, "PLAYER_LOOKUP_FAILED")
+	}
+
+	for _, playerID := range playerIDs {
+		player, ok := found[playerID]
+		if !ok {
+			return status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+		}
+		if series.IsGuest(playerID) || isClubMember(player, series.ClubID) {
+			continue
+		}
+		return status.Error(codes.FailedPrecondition, "VALIDATION_PLAYER_NOT_IN_CLUB")
+	}
+	return nil
+}
+
+// checkMatchEditor returns an error unless the caller may edit or delete a match:
+// one of its players, a club member with the match management permission,
+// or a platform owner
+func checkMatchEditor(ctx context.Context, players *repo.PlayerRepo, series *repo.Series, match *repo.Match) error {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	if player, err := players.FindByEmail(ctx, subject.GetEmail()); err == nil {
+		if id := player.ID.Hex(); id == match.PlayerAID || id == match.PlayerBID {
+			return nil
+		}
+	}
+
+	if series.ClubID != "" {
+		return requireClubPermission(ctx, series.ClubID, PermManageMatches)
+	}
+
+	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !isPlatformOwner {
+		return status.Error(codes.PermissionDenied, "MATCH_EDIT_NOT_ALLOWED")
+	}
+	return nil
+}
+
+// seriesViewer builds the visibility filter for listing series.
+// Returns nil when the caller can see every series.
+func seriesViewer(ctx context.Context, players *repo.PlayerRepo) *repo.SeriesViewer {
+	viewer := &repo.SeriesViewer{}
+
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return viewer
+	}
+
+	if isPlatformOwner, err := subject.IsPlatformOwner(ctx); err != nil {
+		return viewer
+	} else if isPlatformOwner {
+		return nil
+	}
+
+	memberships, err := subject.GetClubMemberships(ctx)
+	if err != nil {
+		return viewer
+	}
+	for _, membership := range memberships {
+		viewer.ClubIDs = append(viewer.ClubIDs, membership.ClubID.Hex())
+	}
+
+	if player, err := players.FindByEmail(ctx, subject.GetEmail()); err == nil {
+		viewer.PlayerID = player.ID.Hex()
+	}
+	return viewer
+}
+
+func isClubMember(player *repo.Player, clubID string) bool {
+	for _, membership := range player.ClubMemberships {
+		if membership.ClubID.Hex() == clubID {
+			return true
+		}
+	}
+	return false
+}
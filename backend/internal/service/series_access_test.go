@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// clubOnlySeriesDoc is a running CLUB_ONLY series document
+func clubOnlySeriesDoc(id primitive.ObjectID, clubID string, guests ...string) bson.D {
+	now := time.Now()
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "club_id", Value: clubID},
+		{Key: "visibility", Value: repo.SeriesVisibilityClubOnly},
+		{Key: "starts_at", Value: now.Add(-7 * 24 * time.Hour)},
+		{Key: "ends_at", Value: now.Add(7 * 24 * time.Hour)},
+		{Key: "guest_player_ids", Value: guests},
+	}
+}
+
+// memberDoc is a player document belonging to one club
+func memberDoc(id primitive.ObjectID, email string, clubID primitive.ObjectID) bson.D {
+	return accountDoc(id, email, bson.E{Key: "club_memberships", Value: bson.A{
+		bson.D{{Key: "club_id", Value: clubID}, {Key: "role", Value: "member"}},
+	}})
+}
+
+func TestClubOnlySeriesReads(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	clubID, seriesID, guestID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	newSeriesService := func(mt *mtest.T) *SeriesService {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+		svc := &SeriesService{Series: repo.NewSeriesRepo(mt.DB), Players: repo.NewPlayerRepo(mt.DB)}
+		mt.ClearEvents()
+		return svc
+	}
+
+	mt.Run("non-member is denied", func(mt *mtest.T) {
+		svc := newSeriesService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex(), guestID.Hex())),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch, accountDoc(primitive.NewObjectID(), "outsider@example.com")),
+		)
+
+		ctx := WithSubject(context.Background(), testSubject{email: "outsider@example.com", clubs: []string{"other-club"}})
+		_, err := svc.GetSeries(ctx, &pb.GetSeriesRequest{Id: seriesID.Hex()})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+		require.Equal(mt, "SERIES_CLUB_ONLY", status.Convert(err).Message())
+	})
+
+	mt.Run("anonymous caller must log in", func(mt *mtest.T) {
+		svc := newSeriesService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex())))
+
+		_, err := svc.GetSeries(context.Background(), &pb.GetSeriesRequest{Id: seriesID.Hex()})
+		require.Equal(mt, codes.Unauthenticated, status.Code(err))
+	})
+
+	mt.Run("member may read", func(mt *mtest.T) {
+		svc := newSeriesService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex())))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "member@example.com", clubs: []string{clubID.Hex()}})
+		resp, err := svc.GetSeries(ctx, &pb.GetSeriesRequest{Id: seriesID.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, seriesID.Hex(), resp.GetSeries().GetId())
+	})
+
+	mt.Run("guest player may read", func(mt *mtest.T) {
+		svc := newSeriesService(mt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex(), guestID.Hex())),
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch, accountDoc(guestID, "guest@example.com")),
+		)
+
+		ctx := WithSubject(context.Background(), testSubject{email: "guest@example.com"})
+		resp, err := svc.GetSeries(ctx, &pb.GetSeriesRequest{Id: seriesID.Hex()})
+		require.NoError(mt, err)
+		require.Equal(mt, seriesID.Hex(), resp.GetSeries().GetId())
+	})
+
+	mt.Run("platform owner may read", func(mt *mtest.T) {
+		svc := newSeriesService(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex())))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+		_, err := svc.GetSeries(ctx, &pb.GetSeriesRequest{Id: seriesID.Hex()})
+		require.NoError(mt, err)
+	})
+}
+
+func TestClubOnlySeriesReports(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	clubID, seriesID := primitive.NewObjectID(), primitive.NewObjectID()
+	memberID, guestID, outsiderID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	mt.Run("non-member cannot report", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+		svc := &MatchService{Series: repo.NewSeriesRepo(mt.DB), Players: repo.NewPlayerRepo(mt.DB)}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".series", mtest.FirstBatch, clubOnlySeriesDoc(seriesID, clubID.Hex())),
+		)
+		mt.ClearEvents()
+
+		ctx := WithSubject(context.Background(), testSubject{email: "outsider@example.com", clubs: []string{"other-club"}})
+		_, err := svc.ReportMatch(ctx, &pb.ReportMatchRequest{
+			SeriesId:  seriesID.Hex(),
+			PlayerAId: memberID.Hex(),
+			PlayerBId: outsiderID.Hex(),
+			ScoreA:    3,
+			ScoreB:    1,
+			PlayedAt:  timestamppb.Now(),
+		})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+		require.Equal(mt, "SERIES_CLUB_ONLY", status.Convert(err).Message())
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			require.NotEqual(mt, "insert", event.CommandName, "the match must not be stored")
+		}
+	})
+
+	series := &repo.Series{ID: seriesID, ClubID: clubID.Hex(), Visibility: repo.SeriesVisibilityClubOnly, GuestPlayerIDs: []string{guestID.Hex()}}
+
+	mt.Run("guest player may take part", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+		players := repo.NewPlayerRepo(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch,
+			memberDoc(memberID, "member@example.com", clubID),
+			accountDoc(guestID, "guest@example.com"),
+		))
+
+		require.NoError(mt, checkSeriesParticipants(context.Background(), players, series, memberID.Hex(), guestID.Hex()))
+	})
+
+	mt.Run("non-member may not take part", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+		players := repo.NewPlayerRepo(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch,
+			memberDoc(memberID, "member@example.com", clubID),
+			accountDoc(outsiderID, "outsider@example.com"),
+		))
+
+		err := checkSeriesParticipants(context.Background(), players, series, memberID.Hex(), outsiderID.Hex())
+		require.Equal(mt, codes.FailedPrecondition, status.Code(err))
+		require.Equal(mt, "VALIDATION_PLAYER_NOT_IN_CLUB", status.Convert(err).Message())
+	})
+
+	mt.Run("guest player may report", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // Player indexes
+		players := repo.NewPlayerRepo(mt.DB)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".players", mtest.FirstBatch, accountDoc(guestID, "guest@example.com")))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "guest@example.com"})
+		require.NoError(mt, checkSeriesVisible(ctx, players, series))
+	})
+}
@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/goencoder/klubbspel/backend/internal/i18n"
 	"github.com/goencoder/klubbspel/backend/internal/repo"
 	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -16,6 +21,16 @@ type SeriesService struct {
 	Series  *repo.SeriesRepo
 	Matches *repo.MatchRepo
 	Players *repo.PlayerRepo
+	// Leaderboard and SeriesPlayers are used to seed cloned series from final standings
+	Leaderboard   *repo.LeaderboardRepo
+	SeriesPlayers *repo.SeriesPlayerRepo
+	Entries       *repo.SeriesEntryRepo
+	// Notifier reminds players when a series is moved to end soon (optional)
+	Notifier *Notifier
+	// Summarizer generates season summaries of ended series
+	Summarizer *SeriesSummarizer
+	// Standings recalculates the leaderboard when the format or tiebreak chain changes (optional)
+	Standings *MatchService
 }
 
 var supportedSeriesSports = map[pb.Sport]struct{}{
@@ -28,6 +43,10 @@ var supportedSeriesSports = map[pb.Sport]struct{}{
 }
 
 func (s *SeriesService) CreateSeries(ctx context.Context, in *pb.CreateSeriesRequest) (*pb.CreateSeriesResponse, error) {
+	if err := s.requireSeriesManager(ctx, in.GetClubId()); err != nil {
+		return nil, err
+	}
+
 	startsAt := in.GetStartsAt().AsTime()
 	endsAt := in.GetEndsAt().AsTime()
 
@@ -69,25 +88,13 @@ func (s *SeriesService) CreateSeries(ctx context.Context, in *pb.CreateSeriesReq
 		}
 	}
 
-	series, err := s.Series.Create(ctx, in.GetClubId(), in.GetTitle(), startsAt, endsAt, int32(in.GetVisibility()), int32(sport), int32(format), int32(ladderRules), int32(scoringProfile), setsToPlay)
+	series, err := s.Series.Create(ctx, in.GetClubId(), in.GetTitle(), startsAt, endsAt, int32(in.GetVisibility()), int32(sport), int32(format), int32(ladderRules), int32(scoringProfile), setsToPlay, seriesRegistrationFromProto(in.GetRegistration()), tiebreakersFromProto(in.GetTiebreakers()))
 	if err != nil {
 		return nil, status.Error(codes.Internal, "SERIES_CREATE_FAILED")
 	}
 
 	return &pb.CreateSeriesResponse{
-		Series: &pb.Series{
-			Id:             series.ID.Hex(),
-			ClubId:         series.ClubID,
-			Title:          series.Title,
-			StartsAt:       timestamppb.New(series.StartsAt),
-			EndsAt:         timestamppb.New(series.EndsAt),
-			Visibility:     pb.SeriesVisibility(series.Visibility),
-			Sport:          pbSeriesSport(series.Sport),
-			Format:         pbSeriesFormat(series.Format),
-			LadderRules:    pb.LadderRules(series.LadderRules),
-			ScoringProfile: pb.ScoringProfile(series.ScoringProfile),
-			SetsToPlay:     series.SetsToPlay,
-		},
+		Series: seriesToProto(series),
 	}, nil
 }
 
@@ -118,6 +125,7 @@ func (s *SeriesService) ListSeries(ctx context.Context, in *pb.ListSeriesRequest
 		}
 		filters.ClubIDs = clubIDs
 	}
+	filters.Viewer = seriesViewer(ctx, s.Players)
 
 	seriesList, hasNext, hasPrev, err := s.Series.ListWithCursor(ctx, in.GetPageSize(), cursor, filters)
 	if err != nil {
@@ -126,19 +134,7 @@ func (s *SeriesService) ListSeries(ctx context.Context, in *pb.ListSeriesRequest
 
 	var pbSeries []*pb.Series
 	for _, series := range seriesList {
-		pbSeries = append(pbSeries, &pb.Series{
-			Id:             series.ID.Hex(),
-			ClubId:         series.ClubID,
-			Title:          series.Title,
-			StartsAt:       timestamppb.New(series.StartsAt),
-			EndsAt:         timestamppb.New(series.EndsAt),
-			Visibility:     pb.SeriesVisibility(series.Visibility),
-			Sport:          pbSeriesSport(series.Sport),
-			Format:         pbSeriesFormat(series.Format),
-			LadderRules:    pb.LadderRules(series.LadderRules),
-			ScoringProfile: pb.ScoringProfile(series.ScoringProfile),
-			SetsToPlay:     series.SetsToPlay,
-		})
+		pbSeries = append(pbSeries, seriesToProto(series))
 	}
 
 	// Simplified pagination info
@@ -163,24 +159,24 @@ func (s *SeriesService) GetSeries(ctx context.Context, in *pb.GetSeriesRequest)
 		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
 	}
 
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
 	return &pb.GetSeriesResponse{
-		Series: &pb.Series{
-			Id:             series.ID.Hex(),
-			ClubId:         series.ClubID,
-			Title:          series.Title,
-			StartsAt:       timestamppb.New(series.StartsAt),
-			EndsAt:         timestamppb.New(series.EndsAt),
-			Visibility:     pb.SeriesVisibility(series.Visibility),
-			Sport:          pbSeriesSport(series.Sport),
-			Format:         pbSeriesFormat(series.Format),
-			LadderRules:    pb.LadderRules(series.LadderRules),
-			ScoringProfile: pb.ScoringProfile(series.ScoringProfile),
-			SetsToPlay:     series.SetsToPlay,
-		},
+		Series: seriesToProto(series),
 	}, nil
 }
 
 func (s *SeriesService) UpdateSeries(ctx context.Context, in *pb.UpdateSeriesRequest) (*pb.UpdateSeriesResponse, error) {
+	existing, err := s.Series.FindByID(ctx, in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+	if err := s.requireSeriesManager(ctx, existing.ClubID); err != nil {
+		return nil, err
+	}
+
 	updates := map[string]interface{}{}
 	if mask := in.GetUpdateMask(); mask != nil && len(mask.GetPaths()) > 0 {
 		for _, path := range mask.GetPaths() {
@@ -211,6 +207,12 @@ func (s *SeriesService) UpdateSeries(ctx context.Context, in *pb.UpdateSeriesReq
 				updates["scoring_profile"] = int32(in.GetSeries().GetScoringProfile())
 			case "sets_to_play":
 				updates["sets_to_play"] = in.GetSeries().GetSetsToPlay()
+			case "registration":
+				updates["registration"] = seriesRegistrationFromProto(in.GetSeries().GetRegistration())
+			case "guest_player_ids":
+				updates["guest_player_ids"] = in.GetSeries().GetGuestPlayerIds()
+			case "tiebreakers":
+				updates["tiebreakers"] = tiebreakersFromProto(in.GetSeries().GetTiebreakers())
 			}
 		}
 	} else {
@@ -231,35 +233,497 @@ func (s *SeriesService) UpdateSeries(ctx context.Context, in *pb.UpdateSeriesReq
 		updates["format"] = int32(format)
 		updates["scoring_profile"] = int32(in.GetSeries().GetScoringProfile())
 		updates["sets_to_play"] = in.GetSeries().GetSetsToPlay()
+		updates["registration"] = seriesRegistrationFromProto(in.GetSeries().GetRegistration())
+		updates["guest_player_ids"] = in.GetSeries().GetGuestPlayerIds()
+		updates["tiebreakers"] = tiebreakersFromProto(in.GetSeries().GetTiebreakers())
 	}
 
 	if len(updates) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "NO_FIELDS_TO_UPDATE")
 	}
 
+	// Moving a series to another club requires series management there as well
+	if clubID, ok := updates["club_id"].(string); ok && clubID != existing.ClubID {
+		if err := s.requireSeriesManager(ctx, clubID); err != nil {
+			return nil, err
+		}
+	}
+
 	series, err := s.Series.Update(ctx, in.GetId(), updates)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "SERIES_UPDATE_FAILED")
 	}
 
+	if _, ok := updates["ends_at"]; ok && s.Notifier != nil {
+		s.Notifier.SeriesEnding(ctx, series)
+	}
+
+	// Stored ranks and snapshots depend on the format and the tiebreak chain
+	if s.Standings != nil && (existing.Format != series.Format || !slices.Equal(existing.Tiebreakers, series.Tiebreakers)) {
+		s.Standings.invalidateSnapshots(ctx, series.ID.Hex(), time.Time{})
+		if err := s.Standings.RecalculateStandings(ctx, series.ID.Hex()); err != nil {
+			log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to recalculate standings after ranking rules changed")
+		}
+	}
+
 	return &pb.UpdateSeriesResponse{
-		Series: &pb.Series{
-			Id:             series.ID.Hex(),
-			ClubId:         series.ClubID,
-			Title:          series.Title,
-			StartsAt:       timestamppb.New(series.StartsAt),
-			EndsAt:         timestamppb.New(series.EndsAt),
-			Visibility:     pb.SeriesVisibility(series.Visibility),
-			Sport:          pbSeriesSport(series.Sport),
-			Format:         pbSeriesFormat(series.Format),
-			LadderRules:    pb.LadderRules(series.LadderRules),
-			ScoringProfile: pb.ScoringProfile(series.ScoringProfile),
-			SetsToPlay:     series.SetsToPlay,
-		},
+		Series: seriesToProto(series),
+	}, nil
+}
+
+// CloneSeries copies the settings of an existing series into new dates, optionally
+// seeding ladder positions or ELO ratings from the source series' final standings
+func (s *SeriesService) CloneSeries(ctx context.Context, in *pb.CloneSeriesRequest) (*pb.CloneSeriesResponse, error) {
+	source, err := s.Series.FindByID(ctx, in.GetSourceSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	// Club series can only be cloned by series managers; open series by platform owners
+	if err := s.requireSeriesManager(ctx, source.ClubID); err != nil {
+		return nil, err
+	}
+
+	series, err := s.Series.Create(ctx, source.ClubID, in.GetTitle(), in.GetStartsAt().AsTime(), in.GetEndsAt().AsTime(), source.Visibility, source.Sport, source.Format, source.LadderRules, source.ScoringProfile, source.SetsToPlay, cloneRegistration(source.Registration), source.Tiebreakers)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "SERIES_CREATE_FAILED")
+	}
+
+	seriesID := series.ID.Hex()
+
+	// Guests of a club series keep their access to the next season
+	if len(source.GuestPlayerIDs) > 0 {
+		series, err = s.Series.Update(ctx, seriesID, map[string]interface{}{"guest_player_ids": source.GuestPlayerIDs})
+		if err != nil {
+			s.discardClone(ctx, seriesID)
+			return nil, status.Error(codes.Internal, "SERIES_CREATE_FAILED")
+		}
+	}
+
+	var seeded int32
+	if in.GetSeedMode() == pb.SeriesSeedMode_SERIES_SEED_MODE_FINAL_STANDINGS {
+		seeded, err = s.seedFromStandings(ctx, source, seriesID)
+		if err != nil {
+			s.discardClone(ctx, seriesID)
+			return nil, status.Error(codes.Internal, "SERIES_SEED_FAILED")
+		}
+	}
+
+	return &pb.CloneSeriesResponse{
+		Series:        seriesToProto(series),
+		SeededPlayers: seeded,
 	}, nil
 }
 
+// discardClone removes a series whose cloning failed half way, with any seeds
+// already copied into it
+func (s *SeriesService) discardClone(ctx context.Context, seriesID string) {
+	if s.SeriesPlayers != nil {
+		if err := s.SeriesPlayers.DeleteAllForSeries(ctx, seriesID); err != nil {
+			log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to delete seeds of failed clone")
+		}
+	}
+	if err := s.Series.Delete(ctx, seriesID); err != nil {
+		log.Error().Err(err).Str("seriesID", seriesID).Msg("Failed to delete failed clone")
+	}
+}
+
+// seedFromStandings copies the final standings of the source series into series player
+// entries for the target series. Ladders keep their order; open play keeps ELO ratings.
+func (s *SeriesService) seedFromStandings(ctx context.Context, source *repo.Series, targetID string) (int32, error) {
+	if s.Leaderboard == nil || s.SeriesPlayers == nil {
+		return 0, nil
+	}
+
+	standings, err := s.Leaderboard.FindBySeriesOrdered(ctx, source.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	isLadder := pb.SeriesFormat(source.Format) == pb.SeriesFormat_SERIES_FORMAT_LADDER
+
+	var seeded int32
+	for _, entry := range standings {
+		position := seeded + 1
+		if isLadder {
+			err = s.SeriesPlayers.UpsertPlayer(ctx, targetID, entry.PlayerID, position, now)
+		} else {
+			err = s.SeriesPlayers.UpsertPlayerRating(ctx, targetID, entry.PlayerID, position, entry.Rating, now)
+		}
+		if err != nil {
+			return seeded, err
+		}
+		seeded++
+	}
+
+	return seeded, nil
+}
+
+// RegisterForSeries enters a player into a series' entry list
+func (s *SeriesService) RegisterForSeries(ctx context.Context, in *pb.RegisterForSeriesRequest) (*pb.RegisterForSeriesResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
+	playerID, err := s.resolveEntrantPlayer(ctx, series, in.GetPlayerId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins may enter other players, who must be able to play in the series too
+	if err := checkSeriesParticipants(ctx, s.Players, series, playerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reg := series.Registration
+	if reg == nil {
+		reg = &repo.SeriesRegistration{}
+	}
+	if reg.OpensAt != nil && now.Before(*reg.OpensAt) {
+		return nil, status.Error(codes.FailedPrecondition, "REGISTRATION_NOT_OPEN")
+	}
+	if reg.ClosesAt != nil && now.After(*reg.ClosesAt) {
+		return nil, status.Error(codes.FailedPrecondition, "REGISTRATION_CLOSED")
+	}
+
+	existing, err := s.Entries.FindBySeriesAndPlayer(ctx, series.ID.Hex(), playerID)
+	if err == nil {
+		switch existing.Status {
+		case repo.EntryStatusRegistered, repo.EntryStatusWaitlisted, repo.EntryStatusPendingApproval:
+			return nil, status.Error(codes.AlreadyExists, "ALREADY_REGISTERED")
+		case repo.EntryStatusRejected:
+			return nil, status.Error(codes.PermissionDenied, "REGISTRATION_REJECTED")
+		}
+	} else if err != mongo.ErrNoDocuments {
+		return nil, status.Error(codes.Internal, "REGISTRATION_FAILED")
+	}
+
+	entryStatus := repo.EntryStatusPendingApproval
+	if !reg.ApprovalRequired {
+		entryStatus, err = s.admissionStatus(ctx, series)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "REGISTRATION_FAILED")
+		}
+	}
+
+	entry, err := s.Entries.Upsert(ctx, series.ID.Hex(), playerID, entryStatus, now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "REGISTRATION_FAILED")
+	}
+	if entry, err = s.confirmPlace(ctx, series, entry); err != nil {
+		return nil, status.Error(codes.Internal, "REGISTRATION_FAILED")
+	}
+
+	entrant, err := s.entrantToProto(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RegisterForSeriesResponse{Entrant: entrant}, nil
+}
+
+// WithdrawFromSeries withdraws a player and promotes the next waitlisted entrant
+func (s *SeriesService) WithdrawFromSeries(ctx context.Context, in *pb.WithdrawFromSeriesRequest) (*pb.WithdrawFromSeriesResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	playerID, err := s.resolveEntrantPlayer(ctx, series, in.GetPlayerId())
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Entries.FindBySeriesAndPlayer(ctx, series.ID.Hex(), playerID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ENTRY_NOT_FOUND")
+	}
+	if existing.Status == repo.EntryStatusWithdrawn || existing.Status == repo.EntryStatusRejected {
+		return nil, status.Error(codes.FailedPrecondition, "ENTRY_NOT_ACTIVE")
+	}
+
+	now := time.Now().UTC()
+	entry, err := s.Entries.UpdateStatus(ctx, series.ID.Hex(), playerID, repo.EntryStatusWithdrawn, now)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "WITHDRAWAL_FAILED")
+	}
+
+	resp := &pb.WithdrawFromSeriesResponse{}
+	if resp.Entrant, err = s.entrantToProto(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// A registered place was freed: promote the longest-waiting entrant
+	if existing.Status == repo.EntryStatusRegistered {
+		promoted, err := s.Entries.PromoteFirstWaitlisted(ctx, series.ID.Hex(), now)
+		if err == nil {
+			if promoted, err = s.confirmPlace(ctx, series, promoted); err != nil {
+				return nil, status.Error(codes.Internal, "WAITLIST_PROMOTION_FAILED")
+			}
+			if promoted.Status == repo.EntryStatusRegistered {
+				if resp.Promoted, err = s.entrantToProto(ctx, promoted); err != nil {
+					return nil, err
+				}
+			}
+		} else if err != mongo.ErrNoDocuments {
+			return nil, status.Error(codes.Internal, "WAITLIST_PROMOTION_FAILED")
+		}
+	}
+
+	return resp, nil
+}
+
+// ListSeriesEntrants lists the entry list of a series in registration order
+func (s *SeriesService) ListSeriesEntrants(ctx context.Context, in *pb.ListSeriesEntrantsRequest) (*pb.ListSeriesEntrantsResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+
+	var statuses []string
+	for _, st := range in.GetStatusFilter() {
+		if value := entryStatusFromProto(st); value != "" {
+			statuses = append(statuses, value)
+		}
+	}
+
+	entries, err := s.Entries.ListBySeries(ctx, in.GetSeriesId(), statuses)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ENTRANTS_LIST_FAILED")
+	}
+
+	playerIDs := make([]string, len(entries))
+	for i, entry := range entries {
+		playerIDs[i] = entry.PlayerID
+	}
+	players, err := s.Players.FindByIDs(ctx, playerIDs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "PLAYER_LOOKUP_FAILED")
+	}
+
+	resp := &pb.ListSeriesEntrantsResponse{}
+	for _, entry := range entries {
+		entrant := entryToProto(entry, players[entry.PlayerID])
+		switch entry.Status {
+		case repo.EntryStatusRegistered:
+			resp.RegisteredCount++
+		case repo.EntryStatusWaitlisted:
+			resp.WaitlistedCount++
+			entrant.WaitlistPosition = resp.WaitlistedCount
+		}
+		resp.Entrants = append(resp.Entrants, entrant)
+	}
+
+	return resp, nil
+}
+
+// ReviewSeriesEntrant approves or rejects a pending entry (club admin only)
+func (s *SeriesService) ReviewSeriesEntrant(ctx context.Context, in *pb.ReviewSeriesEntrantRequest) (*pb.ReviewSeriesEntrantResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+
+	if err := s.requireSeriesManager(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Entries.FindBySeriesAndPlayer(ctx, series.ID.Hex(), in.GetPlayerId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "ENTRY_NOT_FOUND")
+	}
+	if existing.Status != repo.EntryStatusPendingApproval {
+		return nil, status.Error(codes.FailedPrecondition, "ENTRY_NOT_PENDING")
+	}
+
+	entryStatus := repo.EntryStatusRejected
+	if in.GetApprove() {
+		entryStatus, err = s.admissionStatus(ctx, series)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ENTRY_REVIEW_FAILED")
+		}
+	}
+
+	entry, err := s.Entries.UpdateStatus(ctx, series.ID.Hex(), in.GetPlayerId(), entryStatus, time.Now().UTC())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "ENTRY_REVIEW_FAILED")
+	}
+	if entry, err = s.confirmPlace(ctx, series, entry); err != nil {
+		return nil, status.Error(codes.Internal, "ENTRY_REVIEW_FAILED")
+	}
+
+	entrant, err := s.entrantToProto(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReviewSeriesEntrantResponse{Entrant: entrant}, nil
+}
+
+// admissionStatus returns REGISTERED while the series has free places, otherwise WAITLISTED
+func (s *SeriesService) admissionStatus(ctx context.Context, series *repo.Series) (string, error) {
+	if series.Registration == nil || series.Registration.MaxEntrants <= 0 {
+		return repo.EntryStatusRegistered, nil
+	}
+
+	registered, err := s.Entries.CountByStatus(ctx, series.ID.Hex(), repo.EntryStatusRegistered)
+	if err != nil {
+		return "", err
+	}
+	if registered >= int64(series.Registration.MaxEntrants) {
+		return repo.EntryStatusWaitlisted, nil
+	}
+	return repo.EntryStatusRegistered, nil
+}
+
+// confirmPlace enforces MaxEntrants after an entry was registered. Two
+// concurrent admissions can both see a free place, so once written every
+// registered entry checks that it is among the first MaxEntrants admitted
+// and moves back to the front of the waitlist otherwise.
+func (s *SeriesService) confirmPlace(ctx context.Context, series *repo.Series, entry *repo.SeriesEntry) (*repo.SeriesEntry, error) {
+	if entry.Status != repo.EntryStatusRegistered || series.Registration == nil || series.Registration.MaxEntrants <= 0 {
+		return entry, nil
+	}
+
+	before, err := s.Entries.CountAdmittedBefore(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if before < int64(series.Registration.MaxEntrants) {
+		return entry, nil
+	}
+
+	demoted, err := s.Entries.Demote(ctx, entry.SeriesID, entry.PlayerID, time.Now().UTC())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Withdrawn meanwhile; report the entry as it is now
+		return s.Entries.FindBySeriesAndPlayer(ctx, entry.SeriesID, entry.PlayerID)
+	}
+	return demoted, err
+}
+
+// resolveEntrantPlayer returns the player an entry operation applies to. Users act on
+// their own entry; acting on someone else's entry requires club admin rights.
+func (s *SeriesService) resolveEntrantPlayer(ctx context.Context, series *repo.Series, playerID string) (string, error) {
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return "", status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+
+	self, err := s.Players.FindByEmail(ctx, subject.GetEmail())
+	if err != nil {
+		return "", status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+
+	if playerID == "" || playerID == self.ID.Hex() {
+		return self.ID.Hex(), nil
+	}
+
+	if err := s.requireSeriesManager(ctx, series.ClubID); err != nil {
+		return "", err
+	}
+	if _, err := s.Players.FindByID(ctx, playerID); err != nil {
+		return "", status.Error(codes.NotFound, "PLAYER_NOT_FOUND")
+	}
+	return playerID, nil
+}
+
+// requireSeriesManager checks that the caller can manage series of a club
+// (or is a platform owner for open series without a club)
+func (s *SeriesService) requireSeriesManager(ctx context.Context, clubID string) error {
+	if clubID != "" {
+		return requireClubPermission(ctx, clubID, PermManageSeries)
+	}
+
+	subject := GetSubjectFromContext(ctx)
+	if subject == nil {
+		return status.Error(codes.Unauthenticated, "LOGIN_REQUIRED")
+	}
+	isPlatformOwner, err := subject.IsPlatformOwner(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "INVALID_TOKEN")
+	}
+	if !isPlatformOwner {
+		return status.Error(codes.PermissionDenied, "PLATFORM_OWNER_REQUIRED")
+	}
+	return nil
+}
+
+func (s *SeriesService) entrantToProto(ctx context.Context, entry *repo.SeriesEntry) (*pb.SeriesEntrant, error) {
+	player, err := s.Players.FindByID(ctx, entry.PlayerID)
+	if err != nil {
+		player = nil
+	}
+	entrant := entryToProto(entry, player)
+
+	if entry.Status == repo.EntryStatusWaitlisted {
+		waitlist, err := s.Entries.ListBySeries(ctx, entry.SeriesID, []string{repo.EntryStatusWaitlisted})
+		if err != nil {
+			return nil, status.Error(codes.Internal, "ENTRANTS_LIST_FAILED")
+		}
+		for i, w := range waitlist {
+			if w.PlayerID == entry.PlayerID {
+				entrant.WaitlistPosition = int32(i + 1)
+				break
+			}
+		}
+	}
+	return entrant, nil
+}
+
+func entryToProto(entry *repo.SeriesEntry, player *repo.Player) *pb.SeriesEntrant {
+	playerName := "Unknown Player"
+	if player != nil {
+		playerName = player.DisplayName
+	}
+	return &pb.SeriesEntrant{
+		SeriesId:     entry.SeriesID,
+		PlayerId:     entry.PlayerID,
+		PlayerName:   playerName,
+		Status:       entryStatusToProto(entry.Status),
+		RegisteredAt: timestamppb.New(entry.RegisteredAt),
+		UpdatedAt:    timestamppb.New(entry.UpdatedAt),
+	}
+}
+
+var entryStatuses = map[string]pb.SeriesEntrantStatus{
+	repo.EntryStatusPendingApproval: pb.SeriesEntrantStatus_SERIES_ENTRANT_STATUS_PENDING_APPROVAL,
+	repo.EntryStatusRegistered:      pb.SeriesEntrantStatus_SERIES_ENTRANT_STATUS_REGISTERED,
+	repo.EntryStatusWaitlisted:      pb.SeriesEntrantStatus_SERIES_ENTRANT_STATUS_WAITLISTED,
+	repo.EntryStatusWithdrawn:       pb.SeriesEntrantStatus_SERIES_ENTRANT_STATUS_WITHDRAWN,
+	repo.EntryStatusRejected:        pb.SeriesEntrantStatus_SERIES_ENTRANT_STATUS_REJECTED,
+}
+
+func entryStatusToProto(value string) pb.SeriesEntrantStatus {
+	return entryStatuses[value]
+}
+
+func entryStatusFromProto(value pb.SeriesEntrantStatus) string {
+	for k, v := range entryStatuses {
+		if v == value {
+			return k
+		}
+	}
+	return ""
+}
+
 func (s *SeriesService) DeleteSeries(ctx context.Context, in *pb.DeleteSeriesRequest) (*pb.DeleteSeriesResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+	if err := s.requireSeriesManager(ctx, series.ClubID); err != nil {
+		return nil, err
+	}
+
 	if err := s.Series.Delete(ctx, in.GetId()); err != nil {
 		return nil, status.Error(codes.Internal, "SERIES_DELETE_FAILED")
 	}
@@ -277,6 +741,72 @@ func (s *SeriesService) GetLadderStandings(ctx context.Context, in *pb.GetLadder
 	return nil, status.Error(codes.Unimplemented, "LADDER_STANDINGS_DEPRECATED")
 }
 
+// seriesToProto converts a repo.Series to pb.Series
+func seriesToProto(series *repo.Series) *pb.Series {
+	return &pb.Series{
+		Id:             series.ID.Hex(),
+		ClubId:         series.ClubID,
+		Title:          series.Title,
+		StartsAt:       timestamppb.New(series.StartsAt),
+		EndsAt:         timestamppb.New(series.EndsAt),
+		Visibility:     pb.SeriesVisibility(series.Visibility),
+		Sport:          pbSeriesSport(series.Sport),
+		Format:         pbSeriesFormat(series.Format),
+		LadderRules:    pb.LadderRules(series.LadderRules),
+		ScoringProfile: pb.ScoringProfile(series.ScoringProfile),
+		SetsToPlay:     series.SetsToPlay,
+		Registration:   seriesRegistrationToProto(series.Registration),
+		GuestPlayerIds: series.GuestPlayerIDs,
+		Tiebreakers:    tiebreakersToProto(series.Tiebreakers),
+	}
+}
+
+func seriesRegistrationToProto(reg *repo.SeriesRegistration) *pb.SeriesRegistration {
+	if reg == nil {
+		return nil
+	}
+	return &pb.SeriesRegistration{
+		Required:         reg.Required,
+		OpensAt:          timestampFromTimePtr(reg.OpensAt),
+		ClosesAt:         timestampFromTimePtr(reg.ClosesAt),
+		MaxEntrants:      reg.MaxEntrants,
+		ApprovalRequired: reg.ApprovalRequired,
+	}
+}
+
+func seriesRegistrationFromProto(reg *pb.SeriesRegistration) *repo.SeriesRegistration {
+	if reg == nil {
+		return nil
+	}
+	out := &repo.SeriesRegistration{
+		Required:         reg.GetRequired(),
+		MaxEntrants:      reg.GetMaxEntrants(),
+		ApprovalRequired: reg.GetApprovalRequired(),
+	}
+	if reg.OpensAt != nil {
+		t := reg.GetOpensAt().AsTime()
+		out.OpensAt = &t
+	}
+	if reg.ClosesAt != nil {
+		t := reg.GetClosesAt().AsTime()
+		out.ClosesAt = &t
+	}
+	return out
+}
+
+// cloneRegistration copies entry list settings for a new season. The registration
+// window belongs to the old season's dates, so it is not carried over.
+func cloneRegistration(reg *repo.SeriesRegistration) *repo.SeriesRegistration {
+	if reg == nil {
+		return nil
+	}
+	return &repo.SeriesRegistration{
+		Required:         reg.Required,
+		MaxEntrants:      reg.MaxEntrants,
+		ApprovalRequired: reg.ApprovalRequired,
+	}
+}
+
 func normalizeSeriesSport(sport pb.Sport) (pb.Sport, error) {
 	if sport == pb.Sport_SPORT_UNSPECIFIED {
 		return pb.Sport_SPORT_TABLE_TENNIS, nil
@@ -324,14 +854,15 @@ func (s *SeriesService) GetSeriesRules(ctx context.Context, in *pb.GetSeriesRule
 		format = pb.SeriesFormat_SERIES_FORMAT_OPEN_PLAY
 	}
 
-	// Get locale from request, default to Swedish
-	locale := in.GetLocale()
+	// Use the requested locale, else the browser's, else Swedish. Tags like
+	// "nb-NO" or "no" resolve to a supported locale, and rules a locale lacks
+	// come from its fallback chain.
+	locale := i18n.FromAcceptLanguage(in.GetLocale())
 	if locale == "" {
-		locale = "sv"
+		locale = requestLocale(ctx)
 	}
-	// Normalize locale to supported values
-	if locale != "sv" && locale != "en" {
-		locale = "sv" // Default to Swedish
+	if locale == "" {
+		locale = "sv"
 	}
 
 	var rules *pb.RulesDescription
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// testSubject is a logged-in user who belongs to clubs
+type testSubject struct {
+	Subject
+	email string
+	clubs []string
+	roles map[string]string // Club ID to role, for permission checks
+	owner bool              // Platform owner
+}
+
+func (s testSubject) GetEmail() string { return s.email }
+
+func (s testSubject) IsPlatformOwner(context.Context) (bool, error) { return s.owner, nil }
+
+func (s testSubject) IsClubMember(_ context.Context, clubID string) (bool, error) {
+	return slices.Contains(s.clubs, clubID), nil
+}
+
+func (s testSubject) HasClubPermission(_ context.Context, clubID string, permission Permission) (bool, error) {
+	return s.owner || RoleHasPermission(s.roles[clubID], permission), nil
+}
+
+func TestRegisterForClubOnlySeriesRequiresMembership(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("non-member", func(mt *mtest.T) {
+		svc := &SeriesService{Series: repo.NewSeriesRepo(mt.DB)}
+		seriesID := primitive.NewObjectID()
+
+		namespace := mt.DB.Name() + ".series"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: seriesID},
+			{Key: "club_id", Value: "club1"},
+			{Key: "visibility", Value: repo.SeriesVisibilityClubOnly},
+		}))
+
+		ctx := WithSubject(context.Background(), testSubject{email: "outsider@example.com", clubs: []string{"club2"}})
+		_, err := svc.RegisterForSeries(ctx, &pb.RegisterForSeriesRequest{SeriesId: seriesID.Hex()})
+		require.Equal(mt, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestConfirmPlaceDemotesLateAdmission(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	series := &repo.Series{ID: primitive.NewObjectID(), Registration: &repo.SeriesRegistration{MaxEntrants: 2}}
+	admittedAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry := &repo.SeriesEntry{ID: primitive.NewObjectID(), SeriesID: series.ID.Hex(), PlayerID: "p3", Status: repo.EntryStatusRegistered, AdmittedAt: &admittedAt}
+
+	mt.Run("place taken by a concurrent admission", func(mt *mtest.T) {
+		namespace := mt.DB.Name() + ".series_entries"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Indexes
+			mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+				{Key: "_id", Value: entry.ID},
+				{Key: "series_id", Value: entry.SeriesID},
+				{Key: "player_id", Value: "p3"},
+				{Key: "status", Value: repo.EntryStatusWaitlisted},
+			}}),
+		)
+		svc := &SeriesService{Entries: repo.NewSeriesEntryRepo(mt.DB)}
+		mt.ClearEvents()
+
+		confirmed, err := svc.confirmPlace(context.Background(), series, entry)
+		require.NoError(mt, err)
+		require.Equal(mt, repo.EntryStatusWaitlisted, confirmed.Status)
+		require.Equal(mt, "aggregate", mt.GetStartedEvent().CommandName)
+		require.Equal(mt, "findAndModify", mt.GetStartedEvent().CommandName)
+	})
+
+	mt.Run("within the cap", func(mt *mtest.T) {
+		namespace := mt.DB.Name() + ".series_entries"
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, namespace, mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+		)
+		svc := &SeriesService{Entries: repo.NewSeriesEntryRepo(mt.DB)}
+
+		confirmed, err := svc.confirmPlace(context.Background(), series, entry)
+		require.NoError(mt, err)
+		require.Equal(mt, repo.EntryStatusRegistered, confirmed.Status)
+	})
+}
+
+func TestGetSeriesRulesLocale(t *testing.T) {
+	svc := &SeriesService{}
+	tests := []struct {
+		locale string
+		title  string
+	}{
+		{"de", "Regeln für freies Spiel"},
+		{"nb-NO", "Regler for fritt spill"},
+		{"", "Fritt spel regler"},
+		{"xx", "Fritt spel regler"},
+	}
+	for _, test := range tests {
+		resp, err := svc.GetSeriesRules(context.Background(), &pb.GetSeriesRulesRequest{Locale: test.locale})
+		require.NoError(t, err)
+		require.Equal(t, test.title, resp.GetRules().GetTitle(), "locale %q", test.locale)
+	}
+}
+
+func TestCloneSeriesCopiesGuestsAndDiscardsFailedClone(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("seeding fails", func(mt *mtest.T) {
+		sourceID := primitive.NewObjectID()
+		db := mt.DB.Name()
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Leaderboard indexes
+			mtest.CreateSuccessResponse(), // Series player indexes
+		)
+		svc := &SeriesService{
+			Series:        repo.NewSeriesRepo(mt.DB),
+			Leaderboard:   repo.NewLeaderboardRepo(mt.DB),
+			SeriesPlayers: repo.NewSeriesPlayerRepo(mt.DB),
+		}
+
+		guests := bson.A{"guest1"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, db+".series", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: sourceID},
+				{Key: "guest_player_ids", Value: guests},
+			}),
+			mtest.CreateSuccessResponse(), // Insert clone
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, db+".series", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "guest_player_ids", Value: guests},
+			}),
+			mtest.CreateCursorResponse(0, db+".leaderboard", mtest.FirstBatch, bson.D{
+				{Key: "series_id", Value: sourceID.Hex()}, {Key: "player_id", Value: "p1"}, {Key: "rank", Value: 1}, {Key: "rating", Value: 1050},
+			}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "seed failed"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		mt.ClearEvents()
+
+		ctx := WithSubject(context.Background(), testSubject{email: "owner@example.com", owner: true})
+		_, err := svc.CloneSeries(ctx, &pb.CloneSeriesRequest{
+			SourceSeriesId: sourceID.Hex(),
+			Title:          "Next season",
+			SeedMode:       pb.SeriesSeedMode_SERIES_SEED_MODE_FINAL_STANDINGS,
+		})
+		require.Equal(mt, codes.Internal, status.Code(err))
+
+		var commands []string
+		for event := mt.GetStartedEvent(); event != nil; event = mt.GetStartedEvent() {
+			commands = append(commands, event.CommandName+" "+event.Command.Lookup(event.CommandName).StringValue())
+			if event.CommandName == "update" && event.Command.Lookup("update").StringValue() == "series" {
+				set := event.Command.Lookup("updates", "0", "u", "$set").Document()
+				require.Equal(mt, "guest1", set.Lookup("guest_player_ids", "0").StringValue())
+			}
+		}
+		require.Equal(mt, []string{
+			"find series", "insert series", "update series", "find series",
+			"find leaderboard", "update series_players",
+			"delete series_players", "delete series",
+		}, commands)
+	})
+}
+
+func TestEloStandingsIncludeSeedsWithoutMatches(t *testing.T) {
+	svc := &MatchService{}
+	seeds := []*repo.SeriesPlayer{
+		{PlayerID: "a", Position: 1, InitialRating: 1080},
+		{PlayerID: "b", Position: 2, InitialRating: 1020},
+		{PlayerID: "c", Position: 3},
+	}
+
+	entries, err := svc.calculateEloStandings(context.Background(), "s1", seriesTiebreakers(nil), nil, seeds, time.Now())
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for i, expected := range []struct {
+		playerID string
+		rating   int32
+	}{{"a", 1080}, {"b", 1020}, {"c", 1000}} {
+		require.Equal(t, expected.playerID, entries[i].PlayerID)
+		require.Equal(t, expected.rating, entries[i].Rating)
+		require.Equal(t, int32(i+1), entries[i].Rank)
+		require.Zero(t, entries[i].MatchesPlayed)
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// Season summary scheduling defaults
+const (
+	seriesSummaryInterval = time.Hour
+	// Ended series older than this are not picked up by Run; they are
+	// summarized on their first GetSeriesSummary instead
+	seriesSummaryLookback = 30 * 24 * time.Hour
+)
+
+// SeriesSummarizer generates and stores the season summary of a series once
+// it reaches its end, and emails it to the players who opted in
+type SeriesSummarizer struct {
+	Series        *repo.SeriesRepo
+	Matches       *repo.MatchRepo
+	Leaderboard   *repo.LeaderboardRepo
+	SeriesPlayers *repo.SeriesPlayerRepo
+	Summaries     *repo.SeriesSummaryRepo
+	Notifier      *Notifier // Emails the summary (optional)
+	Now           func() time.Time
+}
+
+// Summary returns the stored summary of an ended series, generating it first
+// if the series has none yet
+func (s *SeriesSummarizer) Summary(ctx context.Context, series *repo.Series) (*repo.SeriesSummary, error) {
+	summary, err := s.Summaries.FindBySeriesID(ctx, series.ID.Hex())
+	if err == nil {
+		return summary, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	return s.Summarize(ctx, series)
+}
+
+// Summarize generates the summary of series from its final standings and
+// matches, stores it and notifies the players
+func (s *SeriesSummarizer) Summarize(ctx context.Context, series *repo.Series) (*repo.SeriesSummary, error) {
+	seriesID := series.ID.Hex()
+	matches, err := s.Matches.FindAllBySeriesChronological(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	standings, err := s.Leaderboard.FindBySeriesOrdered(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	var seeds []*repo.SeriesPlayer
+	if s.SeriesPlayers != nil {
+		if seeds, err = s.SeriesPlayers.FindBySeriesOrdered(ctx, seriesID); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := summarizeSeries(series, matches, standings, seeds)
+	summary.GeneratedAt = s.now()
+	if err := s.Summaries.Upsert(ctx, summary); err != nil {
+		return nil, err
+	}
+
+	if s.Notifier != nil {
+		s.Notifier.SeriesSummary(ctx, series, summary)
+	}
+	return summary, nil
+}
+
+// SummarizeEndedSeries summarizes every recently ended series that has no
+// summary yet
+func (s *SeriesSummarizer) SummarizeEndedSeries(ctx context.Context) error {
+	now := s.now()
+	ended, err := s.Series.ListEndingBetween(ctx, now.Add(-seriesSummaryLookback), now)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(ended))
+	for i, series := range ended {
+		ids[i] = series.ID.Hex()
+	}
+	summarized, err := s.Summaries.SummarizedSeriesIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, series := range ended {
+		if summarized[series.ID.Hex()] {
+			continue
+		}
+		if _, err := s.Summarize(ctx, series); err != nil {
+			log.Error().Err(err).Str("seriesID", series.ID.Hex()).Msg("Failed to summarize series")
+		}
+	}
+	return nil
+}
+
+// Run summarizes ended series every hour until ctx is cancelled
+func (s *SeriesSummarizer) Run(ctx context.Context) {
+	ticker := time.NewTicker(seriesSummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SummarizeEndedSeries(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("Series summaries failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SeriesSummarizer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now().UTC()
+}
+
+// summarizeSeries works out the season awards. The champion and the most
+// matches come from the final standings, ties going to the better ranked
+// player. The other awards replay the matches in order to know each player's
+// rating, or ladder position, before every match.
+func summarizeSeries(series *repo.Series, matches []*repo.Match, standings []*repo.LeaderboardEntry, seeds []*repo.SeriesPlayer) *repo.SeriesSummary {
+	summary := &repo.SeriesSummary{
+		SeriesID:      series.ID.Hex(),
+		MatchesPlayed: int32(len(matches)),
+		Players:       int32(len(standings)),
+	}
+
+	for _, entry := range standings {
+		if summary.Champion == nil {
+			summary.Champion = &repo.SeriesAward{PlayerID: entry.PlayerID, Value: entry.Rating}
+		}
+		if entry.MatchesPlayed > 0 && (summary.MostMatches == nil || entry.MatchesPlayed > summary.MostMatches.Value) {
+			summary.MostMatches = &repo.SeriesAward{PlayerID: entry.PlayerID, Value: entry.MatchesPlayed}
+		}
+	}
+
+	replay := newRatingReplay(series, seeds)
+	streaks := make(map[string]int32)
+	for _, match := range matches {
+		if match.ScoreA == match.ScoreB {
+			replay.tie(match)
+			streaks[match.PlayerAID] = 0
+			streaks[match.PlayerBID] = 0
+			continue
+		}
+
+		winnerID, loserID := match.PlayerAID, match.PlayerBID
+		if match.ScoreB > match.ScoreA {
+			winnerID, loserID = loserID, winnerID
+		}
+
+		winnerBefore, loserBefore := replay.play(winnerID, loserID)
+		if gap := replay.gain(winnerBefore, loserBefore); gap > 0 && (summary.BiggestUpset == nil || gap > summary.BiggestUpset.Value) {
+			summary.BiggestUpset = &repo.SeriesAward{PlayerID: winnerID, Value: gap, OpponentID: loserID, MatchID: match.ID.Hex()}
+		}
+
+		streaks[winnerID]++
+		streaks[loserID] = 0
+		if summary.LongestWinStreak == nil || streaks[winnerID] > summary.LongestWinStreak.Value {
+			summary.LongestWinStreak = &repo.SeriesAward{PlayerID: winnerID, Value: streaks[winnerID]}
+		}
+	}
+
+	for _, entry := range standings {
+		start, ok := replay.initial[entry.PlayerID]
+		if !ok {
+			continue
+		}
+		if gain := replay.gain(start, replay.current[entry.PlayerID]); gain > 0 && (summary.MostImproved == nil || gain > summary.MostImproved.Value) {
+			summary.MostImproved = &repo.SeriesAward{PlayerID: entry.PlayerID, Value: gain}
+		}
+	}
+	return summary
+}
+
+// ratingReplay replays a series' matches the way RecalculateStandings does,
+// tracking ELO ratings or, for ladder series, positions
+type ratingReplay struct {
+	ladder       bool
+	ladderRules  pb.LadderRules
+	seeded       map[string]int32 // Carried-over ELO ratings
+	initial      map[string]int32 // Rating or position each player started from
+	current      map[string]int32
+	nextPosition int32
+}
+
+func newRatingReplay(series *repo.Series, seeds []*repo.SeriesPlayer) *ratingReplay {
+	r := &ratingReplay{
+		ladder:       series.Format == repo.SeriesFormatLadder,
+		ladderRules:  pb.LadderRules(series.LadderRules),
+		seeded:       make(map[string]int32),
+		initial:      make(map[string]int32),
+		current:      make(map[string]int32),
+		nextPosition: 1,
+	}
+	for _, seed := range seeds {
+		if r.ladder {
+			r.join(seed.PlayerID)
+		} else if seed.InitialRating > 0 {
+			r.seeded[seed.PlayerID] = seed.InitialRating
+		}
+	}
+	return r
+}
+
+func (r *ratingReplay) join(playerID string) {
+	if _, ok := r.current[playerID]; ok {
+		return
+	}
+	start := initialRating(r.seeded, playerID)
+	if r.ladder {
+		start = r.nextPosition
+		r.nextPosition++
+	}
+	r.initial[playerID] = start
+	r.current[playerID] = start
+}
+
+// tie enters the players of a tied match; ladders ignore ties entirely
+func (r *ratingReplay) tie(match *repo.Match) {
+	if !r.ladder {
+		r.join(match.PlayerAID)
+		r.join(match.PlayerBID)
+	}
+}
+
+// play applies a decided match and returns the ratings before it
+func (r *ratingReplay) play(winnerID, loserID string) (winnerBefore, loserBefore int32) {
+	// Ladders place new players winner first, like calculateLadderStandings
+	r.join(winnerID)
+	r.join(loserID)
+	winnerBefore, loserBefore = r.current[winnerID], r.current[loserID]
+
+	if r.ladder {
+		applyLadderResult(r.current, winnerID, loserID, r.ladderRules)
+		return winnerBefore, loserBefore
+	}
+	newWinner, newLoser := calculateELO(float64(winnerBefore), float64(loserBefore), 1, 0)
+	r.current[winnerID] = int32(newWinner)
+	r.current[loserID] = int32(newLoser)
+	return winnerBefore, loserBefore
+}
+
+// gain returns how much better to is than from: rating points for ELO,
+// positions climbed for ladders
+func (r *ratingReplay) gain(from, to int32) int32 {
+	if r.ladder {
+		return from - to
+	}
+	return to - from
+}
+
+// GetSeriesSummary returns the season summary of an ended series
+func (s *SeriesService) GetSeriesSummary(ctx context.Context, in *pb.GetSeriesSummaryRequest) (*pb.GetSeriesSummaryResponse, error) {
+	series, err := s.Series.FindByID(ctx, in.GetSeriesId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "SERIES_NOT_FOUND")
+	}
+	if err := checkSeriesVisible(ctx, s.Players, series); err != nil {
+		return nil, err
+	}
+	if s.Summarizer == nil {
+		return nil, status.Error(codes.Unimplemented, "SERIES_SUMMARY_UNAVAILABLE")
+	}
+	if series.EndsAt.After(s.Summarizer.now()) {
+		return nil, status.Error(codes.FailedPrecondition, "SERIES_NOT_ENDED")
+	}
+
+	summary, err := s.Summarizer.Summary(ctx, series)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "SERIES_SUMMARY_FAILED")
+	}
+
+	awards := []*repo.SeriesAward{summary.Champion, summary.MostMatches, summary.BiggestUpset, summary.MostImproved, summary.LongestWinStreak}
+	var ids []string
+	for _, award := range awards {
+		if award != nil {
+			ids = append(ids, award.PlayerID)
+			if award.OpponentID != "" {
+				ids = append(ids, award.OpponentID)
+			}
+		}
+	}
+	players, err := s.Players.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "PLAYER_LOOKUP_FAILED")
+	}
+
+	return &pb.GetSeriesSummaryResponse{
+		Summary: &pb.SeriesSummary{
+			SeriesId:         summary.SeriesID,
+			GeneratedAt:      timestamppb.New(summary.GeneratedAt),
+			MatchesPlayed:    summary.MatchesPlayed,
+			Players:          summary.Players,
+			Champion:         seriesAwardToProto(summary.Champion, players),
+			MostMatches:      seriesAwardToProto(summary.MostMatches, players),
+			BiggestUpset:     seriesAwardToProto(summary.BiggestUpset, players),
+			MostImproved:     seriesAwardToProto(summary.MostImproved, players),
+			LongestWinStreak: seriesAwardToProto(summary.LongestWinStreak, players),
+		},
+	}, nil
+}
+
+func seriesAwardToProto(award *repo.SeriesAward, players map[string]*repo.Player) *pb.SeriesAward {
+	if award == nil {
+		return nil
+	}
+	out := &pb.SeriesAward{
+		PlayerId:   award.PlayerID,
+		Value:      award.Value,
+		OpponentId: award.OpponentID,
+		MatchId:    award.MatchID,
+	}
+	if player, ok := players[award.PlayerID]; ok {
+		out.PlayerName = player.DisplayName
+	}
+	if opponent, ok := players[award.OpponentID]; ok {
+		out.OpponentName = opponent.DisplayName
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func summaryMatches(results ...[2]string) []*repo.Match {
+	start := time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC)
+	matches := make([]*repo.Match, len(results))
+	for i, r := range results {
+		matches[i] = &repo.Match{ID: primitive.NewObjectID(), PlayerAID: r[0], PlayerBID: r[1], ScoreA: 3, ScoreB: 1, PlayedAt: start.Add(time.Duration(i) * time.Hour)}
+	}
+	return matches
+}
+
+func TestSummarizeSeriesELO(t *testing.T) {
+	series := &repo.Series{ID: primitive.NewObjectID(), Format: int32(pb.SeriesFormat_SERIES_FORMAT_OPEN_PLAY)}
+	// Winner first
+	matches := summaryMatches([2]string{"a", "b"}, [2]string{"a", "c"}, [2]string{"b", "a"}, [2]string{"c", "b"})
+	standings := []*repo.LeaderboardEntry{
+		{PlayerID: "a", Rank: 1, Rating: 1012, MatchesPlayed: 3},
+		{PlayerID: "c", Rank: 2, Rating: 1000, MatchesPlayed: 2},
+		{PlayerID: "b", Rank: 3, Rating: 985, MatchesPlayed: 3},
+	}
+
+	summary := summarizeSeries(series, matches, standings, nil)
+	require.Equal(t, int32(4), summary.MatchesPlayed)
+	require.Equal(t, int32(3), summary.Players)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "a", Value: 1012}, summary.Champion)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "a", Value: 3}, summary.MostMatches, "ties go to the better ranked player")
+	// b (984) beat a (1031) in the third match
+	require.Equal(t, &repo.SeriesAward{PlayerID: "b", Value: 47, OpponentID: "a", MatchID: matches[2].ID.Hex()}, summary.BiggestUpset)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "a", Value: 12}, summary.MostImproved)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "a", Value: 2}, summary.LongestWinStreak)
+}
+
+func TestSummarizeSeriesLadder(t *testing.T) {
+	series := &repo.Series{ID: primitive.NewObjectID(), Format: repo.SeriesFormatLadder, LadderRules: int32(pb.LadderRules_LADDER_RULES_CLASSIC)}
+	seeds := []*repo.SeriesPlayer{{PlayerID: "x"}, {PlayerID: "y"}, {PlayerID: "z"}}
+	// z climbs from 3 to 1, then y climbs from 3 to 1
+	matches := summaryMatches([2]string{"z", "x"}, [2]string{"y", "z"})
+	standings := []*repo.LeaderboardEntry{
+		{PlayerID: "y", Rank: 1, Rating: 1, MatchesPlayed: 1},
+		{PlayerID: "z", Rank: 2, Rating: 2, MatchesPlayed: 2},
+		{PlayerID: "x", Rank: 3, Rating: 3, MatchesPlayed: 1},
+	}
+
+	summary := summarizeSeries(series, matches, standings, seeds)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "y", Value: 1}, summary.Champion)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "z", Value: 2}, summary.MostMatches)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "z", Value: 2, OpponentID: "x", MatchID: matches[0].ID.Hex()}, summary.BiggestUpset, "equal gaps keep the earlier upset")
+	require.Equal(t, &repo.SeriesAward{PlayerID: "y", Value: 1}, summary.MostImproved)
+	require.Equal(t, &repo.SeriesAward{PlayerID: "z", Value: 1}, summary.LongestWinStreak)
+}
+
+func TestSummarizeSeriesWithoutMatches(t *testing.T) {
+	summary := summarizeSeries(&repo.Series{ID: primitive.NewObjectID()}, nil, nil, nil)
+	require.Zero(t, summary.MatchesPlayed)
+	require.Nil(t, summary.Champion)
+	require.Nil(t, summary.BiggestUpset)
+	require.Nil(t, summary.LongestWinStreak)
+}
+
+func TestMatchChangesDropSeriesSummary(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("invalidate", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // Snapshot indexes
+			mtest.CreateSuccessResponse(), // Summary indexes
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		svc := &MatchService{Snapshots: repo.NewLeaderboardSnapshotRepo(mt.DB), Summaries: repo.NewSeriesSummaryRepo(mt.DB)}
+		mt.ClearEvents()
+
+		svc.invalidateSnapshots(context.Background(), "s1", time.Date(2025, 4, 1, 18, 0, 0, 0, time.UTC))
+		require.Equal(mt, "leaderboard_snapshots", mt.GetStartedEvent().Command.Lookup("delete").StringValue())
+		started := mt.GetStartedEvent()
+		require.Equal(mt, "series_summaries", started.Command.Lookup("delete").StringValue())
+		require.Equal(mt, "s1", started.Command.Lookup("deletes", "0", "q", "series_id").StringValue())
+	})
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goencoder/klubbspel/backend/internal/repo"
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+// defaultTiebreakers is the tiebreak chain of series that do not configure one
+var defaultTiebreakers = []pb.Tiebreaker{
+	pb.Tiebreaker_TIEBREAKER_HEAD_TO_HEAD,
+	pb.Tiebreaker_TIEBREAKER_SET_DIFFERENCE,
+	pb.Tiebreaker_TIEBREAKER_MATCHES_PLAYED,
+	pb.Tiebreaker_TIEBREAKER_NAME,
+}
+
+// standing is a player's line in the ELO standings before ranking
+type standing struct {
+	playerID string
+	rating   int32
+	stats    *playerMatchStats
+	name     string // Only loaded when the chain sorts by name
+}
+
+// tiebreakKey is one player's value for one tiebreaker: higher value first,
+// then name in alphabetical order
+type tiebreakKey struct {
+	value int32
+	name  string
+}
+
+func (k tiebreakKey) before(other tiebreakKey) bool {
+	if k.value != other.value {
+		return k.value > other.value
+	}
+	return k.name < other.name
+}
+
+// rankStandings orders standings by rating, breaks ties with chain and
+// finally by player ID, so the order never depends on map iteration
+func rankStandings(standings []*standing, chain []pb.Tiebreaker, matches []*repo.Match) {
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].rating != standings[j].rating {
+			return standings[i].rating > standings[j].rating
+		}
+		return standings[i].playerID < standings[j].playerID
+	})
+
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && standings[end].rating == standings[start].rating {
+			end++
+		}
+		breakTies(standings[start:end], chain, matches)
+		start = end
+	}
+}
+
+// breakTies orders a tied group by the first tiebreaker, then every group
+// still tied by the rest of the chain. Head-to-head only counts matches
+// between players of the group being broken.
+func breakTies(group []*standing, chain []pb.Tiebreaker, matches []*repo.Match) {
+	if len(group) < 2 || len(chain) == 0 {
+		return
+	}
+
+	keys := tiebreakKeys(group, chain[0], matches)
+	sort.SliceStable(group, func(i, j int) bool {
+		return keys[group[i].playerID].before(keys[group[j].playerID])
+	})
+
+	for start := 0; start < len(group); {
+		end := start + 1
+		for end < len(group) && keys[group[end].playerID] == keys[group[start].playerID] {
+			end++
+		}
+		breakTies(group[start:end], chain[1:], matches)
+		start = end
+	}
+}
+
+func tiebreakKeys(group []*standing, tiebreaker pb.Tiebreaker, matches []*repo.Match) map[string]tiebreakKey {
+	keys := make(map[string]tiebreakKey, len(group))
+	switch tiebreaker {
+	case pb.Tiebreaker_TIEBREAKER_HEAD_TO_HEAD:
+		for _, s := range group {
+			keys[s.playerID] = tiebreakKey{}
+		}
+		for _, match := range matches {
+			_, inA := keys[match.PlayerAID]
+			_, inB := keys[match.PlayerBID]
+			if !inA || !inB || match.ScoreA == match.ScoreB {
+				continue
+			}
+			winnerID := match.PlayerAID
+			if match.ScoreB > match.ScoreA {
+				winnerID = match.PlayerBID
+			}
+			keys[winnerID] = tiebreakKey{value: keys[winnerID].value + 1}
+		}
+	case pb.Tiebreaker_TIEBREAKER_SET_DIFFERENCE:
+		for _, s := range group {
+			keys[s.playerID] = tiebreakKey{value: s.stats.gamesWon - s.stats.gamesLost}
+		}
+	case pb.Tiebreaker_TIEBREAKER_MATCHES_PLAYED:
+		for _, s := range group {
+			keys[s.playerID] = tiebreakKey{value: s.stats.played}
+		}
+	case pb.Tiebreaker_TIEBREAKER_NAME:
+		for _, s := range group {
+			keys[s.playerID] = tiebreakKey{name: strings.ToLower(s.name)}
+		}
+	default:
+		for _, s := range group {
+			keys[s.playerID] = tiebreakKey{}
+		}
+	}
+	return keys
+}
+
+// seriesTiebreakers returns the stored tiebreak chain, or the default chain
+func seriesTiebreakers(stored []int32) []pb.Tiebreaker {
+	if len(stored) == 0 {
+		return defaultTiebreakers
+	}
+	return tiebreakersToProto(stored)
+}
+
+func tiebreakersFromProto(tiebreakers []pb.Tiebreaker) []int32 {
+	var out []int32
+	for _, t := range tiebreakers {
+		out = append(out, int32(t))
+	}
+	return out
+}
+
+func tiebreakersToProto(tiebreakers []int32) []pb.Tiebreaker {
+	var out []pb.Tiebreaker
+	for _, t := range tiebreakers {
+		out = append(out, pb.Tiebreaker(t))
+	}
+	return out
+}
+
+// sortsByName reports whether chain needs player names
+func sortsByName(chain []pb.Tiebreaker) bool {
+	return slices.Contains(chain, pb.Tiebreaker_TIEBREAKER_NAME)
+}
+
+// hasEqualRatings reports whether any two players share a rating
+func hasEqualRatings(ratings map[string]int32) bool {
+	seen := make(map[int32]bool, len(ratings))
+	for _, rating := range ratings {
+		if seen[rating] {
+			return true
+		}
+		seen[rating] = true
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/goencoder/klubbspel/backend/proto/gen/go/klubbspel/v1"
+)
+
+func standingIDs(standings []*standing) []string {
+	ids := make([]string, len(standings))
+	for i, s := range standings {
+		ids[i] = s.playerID
+	}
+	return ids
+}
+
+func TestRankStandingsTiebreakChain(t *testing.T) {
+	// a, b and c share a rating; d leads and e trails
+	newStandings := func() []*standing {
+		return []*standing{
+			{playerID: "e", rating: 980, stats: &playerMatchStats{played: 2}, name: "Erik"},
+			{playerID: "c", rating: 1000, stats: &playerMatchStats{played: 4, gamesWon: 9, gamesLost: 5}, name: "Cecilia"},
+			{playerID: "b", rating: 1000, stats: &playerMatchStats{played: 3, gamesWon: 6, gamesLost: 6}, name: "Bo"},
+			{playerID: "a", rating: 1000, stats: &playerMatchStats{played: 3, gamesWon: 6, gamesLost: 6}, name: "Anna"},
+			{playerID: "d", rating: 1030, stats: &playerMatchStats{played: 5}, name: "David"},
+		}
+	}
+	// b beat c twice, c beat a once; d's win over c is outside the tied group
+	matches := summaryMatches([2]string{"b", "c"}, [2]string{"b", "c"}, [2]string{"c", "a"}, [2]string{"d", "c"})
+
+	standings := newStandings()
+	rankStandings(standings, defaultTiebreakers, matches)
+	require.Equal(t, []string{"d", "b", "c", "a", "e"}, standingIDs(standings), "head-to-head within the tied group")
+
+	standings = newStandings()
+	rankStandings(standings, []pb.Tiebreaker{pb.Tiebreaker_TIEBREAKER_SET_DIFFERENCE, pb.Tiebreaker_TIEBREAKER_NAME}, matches)
+	require.Equal(t, []string{"d", "c", "a", "b", "e"}, standingIDs(standings), "set difference, then name")
+
+	standings = newStandings()
+	rankStandings(standings, []pb.Tiebreaker{pb.Tiebreaker_TIEBREAKER_MATCHES_PLAYED}, nil)
+	require.Equal(t, []string{"d", "c", "a", "b", "e"}, standingIDs(standings), "remaining ties fall back to player ID")
+}
+
+func TestSeriesTiebreakersDefault(t *testing.T) {
+	require.Equal(t, defaultTiebreakers, seriesTiebreakers(nil))
+
+	stored := tiebreakersFromProto([]pb.Tiebreaker{pb.Tiebreaker_TIEBREAKER_NAME})
+	require.Equal(t, []pb.Tiebreaker{pb.Tiebreaker_TIEBREAKER_NAME}, seriesTiebreakers(stored))
+}
+
+func TestSortLeaderboard(t *testing.T) {
+	newEntries := func() []*pb.LeaderboardEntry {
+		return []*pb.LeaderboardEntry{
+			{PlayerId: "a", Rank: 1, StandingsRank: 1, MatchesPlayed: 10, WinRate: 60, GamesWon: 20, GamesLost: 14},
+			{PlayerId: "b", Rank: 2, StandingsRank: 2, MatchesPlayed: 2, WinRate: 100, GamesWon: 6, GamesLost: 0},
+			{PlayerId: "c", Rank: 3, StandingsRank: 3, MatchesPlayed: 5, WinRate: 60, GamesWon: 12, GamesLost: 10},
+		}
+	}
+	ids := func(entries []*pb.LeaderboardEntry) (out []string) {
+		for _, e := range entries {
+			out = append(out, e.PlayerId)
+		}
+		return out
+	}
+
+	entries := sortLeaderboard(newEntries(), pb.LeaderboardSort_LEADERBOARD_SORT_WIN_RATE, 0)
+	require.Equal(t, []string{"b", "a", "c"}, ids(entries), "equal win rates keep standings order")
+	require.Equal(t, int32(1), entries[0].Rank)
+	require.Equal(t, int32(2), entries[0].StandingsRank)
+
+	entries = sortLeaderboard(newEntries(), pb.LeaderboardSort_LEADERBOARD_SORT_WIN_RATE, 5)
+	require.Equal(t, []string{"a", "c"}, ids(entries))
+
+	entries = sortLeaderboard(newEntries(), pb.LeaderboardSort_LEADERBOARD_SORT_MATCHES_PLAYED, 0)
+	require.Equal(t, []string{"a", "c", "b"}, ids(entries))
+
+	entries = sortLeaderboard(newEntries(), pb.LeaderboardSort_LEADERBOARD_SORT_GAMES_DIFFERENCE, 3)
+	require.Equal(t, []string{"a", "c"}, ids(entries))
+	require.Equal(t, []int32{1, 2}, []int32{entries[0].Rank, entries[1].Rank})
+
+	entries = sortLeaderboard(newEntries(), pb.LeaderboardSort_LEADERBOARD_SORT_UNSPECIFIED, 0)
+	require.Equal(t, []string{"a", "b", "c"}, ids(entries))
+}
@@ -1,29 +1,225 @@
 package validation
 
-// CEL validation policies for business rules
-// This is a placeholder for future CEL validation implementation using google/cel-go library
-// to evaluate complex business rules like:
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// CEL validation policies for configurable business rules.
+//
+// Rules are stored per club or per series and evaluated against a match before it
+// is saved. A rule passes when its expression evaluates to true. Available variables:
 //
-// - Match scores: no ties, winner >= 3 games
-// - Series time windows: played_at between starts_at and ends_at
-// - Club membership: if series visibility is CLUB_ONLY, both players must be in that club
+// - match:    player_a_id, player_b_id, score_a, score_b, played_at (timestamp)
+// - series:   id, club_id, format ("OPEN_PLAY" or "LADDER"), starts_at, ends_at, sets_to_play
+// - player_a: id, rating (ELO rating, or position in ladders), rank, matches_played
+// - player_b: same fields as player_a
+// - pair:     matches_week, matches_month, matches_total (earlier matches between the two players)
 //
 // Example CEL expressions:
-// - "score_a != score_b"
-// - "max(score_a, score_b) >= 3"
-// - "player_a_id != player_b_id"
-// - "played_at >= series.starts_at && played_at <= series.ends_at"
+// - "pair.matches_week < 2"
+// - "match.played_at.getDayOfWeek('Europe/Stockholm') in [1, 2, 3, 4, 5] && match.played_at.getHours('Europe/Stockholm') >= 17"
+// - "series.format != 'LADDER' || (player_a.rating - player_b.rating < 400 && player_b.rating - player_a.rating < 400)"
+
+const (
+	// MaxExpressionLength matches the length limit of the rule APIs
+	MaxExpressionLength = 2000
+	// ruleCostLimit bounds the work a single rule evaluation may do, so an
+	// expensive expression cannot hold up match reporting. Realistic rules
+	// cost well under a hundred.
+	ruleCostLimit = 10000
+	// maxCachedPrograms bounds the cache of compiled stored rules
+	maxCachedPrograms = 512
+)
+
+// RulePlayer describes one side of a match for rule evaluation
+type RulePlayer struct {
+	ID            string
+	Rating        int32
+	Rank          int32
+	MatchesPlayed int32
+}
+
+// MatchRuleInput is the data a rule expression is evaluated against
+type MatchRuleInput struct {
+	PlayerA  RulePlayer
+	PlayerB  RulePlayer
+	ScoreA   int32
+	ScoreB   int32
+	PlayedAt time.Time
+
+	SeriesID       string
+	ClubID         string
+	SeriesFormat   string
+	SeriesStartsAt time.Time
+	SeriesEndsAt   time.Time
+	SetsToPlay     int32
+
+	PairMatchesWeek  int64
+	PairMatchesMonth int64
+	PairMatchesTotal int64
+}
+
+// Rule is a compiled-on-demand business rule
+type Rule struct {
+	ID         string
+	Name       string
+	Expression string
+	ErrorCode  string
+	Messages   map[string]string // Localized violation messages keyed by locale
+}
+
+// RuleViolation is returned when a rule evaluates to false
+type RuleViolation struct {
+	Rule Rule
+}
+
+func (v *RuleViolation) Error() string {
+	return fmt.Sprintf("match violates rule %q", v.Rule.Name)
+}
 
 type CELValidator struct {
-	// Future: Add CEL environment and compiled expressions when implementing
+	env *cel.Env
+
+	mu       sync.RWMutex
+	programs map[string]cel.Program // Compiled stored rules, by expression
+}
+
+func NewCELValidator() (*CELValidator, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("match", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("series", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("player_a", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("player_b", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("pair", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	return &CELValidator{env: env, programs: make(map[string]cel.Program)}, nil
+}
+
+// Compile checks that an expression is valid and yields a boolean. The
+// program is not cached, so compiling arbitrary expressions holds no memory.
+func (v *CELValidator) Compile(expression string) (cel.Program, error) {
+	if len(expression) > MaxExpressionLength {
+		return nil, fmt.Errorf("rule is longer than %d characters", MaxExpressionLength)
+	}
+
+	ast, issues := v.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("rule must evaluate to a bool, got %s", ast.OutputType())
+	}
+
+	return v.env.Program(ast, cel.CostLimit(ruleCostLimit))
+}
+
+// program returns the compiled program of a stored rule, compiling it on
+// first use. When the cache is full it is emptied, so rules that were since
+// edited or deleted do not stay in memory.
+func (v *CELValidator) program(expression string) (cel.Program, error) {
+	v.mu.RLock()
+	prg, ok := v.programs[expression]
+	v.mu.RUnlock()
+	if ok {
+		return prg, nil
+	}
+
+	prg, err := v.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	if len(v.programs) >= maxCachedPrograms {
+		v.programs = make(map[string]cel.Program)
+	}
+	v.programs[expression] = prg
+	v.mu.Unlock()
+
+	return prg, nil
 }
 
-func NewCELValidator() *CELValidator {
-	return &CELValidator{}
+// Evaluate runs a single expression, such as one being tested, against the input
+func (v *CELValidator) Evaluate(expression string, in MatchRuleInput) (bool, error) {
+	prg, err := v.Compile(expression)
+	if err != nil {
+		return false, err
+	}
+	return evaluate(prg, in)
+}
+
+func evaluate(prg cel.Program, in MatchRuleInput) (bool, error) {
+	out, _, err := prg.Eval(in.activation())
+	if err != nil {
+		return false, err
+	}
+
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("rule must evaluate to a bool, got %s", out.Type().TypeName())
+	}
+	return passed, nil
 }
 
-func (v *CELValidator) ValidateMatch(matchData map[string]interface{}) error {
-	// Currently validation is done in the service layer
-	// This can be extended to use CEL for more complex validations
+// ValidateMatch evaluates all rules in order and returns a *RuleViolation for the
+// first rule that does not pass. Evaluation errors are returned as-is.
+func (v *CELValidator) ValidateMatch(rules []Rule, in MatchRuleInput) error {
+	for _, rule := range rules {
+		prg, err := v.program(rule.Expression)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		passed, err := evaluate(prg, in)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		if !passed {
+			return &RuleViolation{Rule: rule}
+		}
+	}
 	return nil
 }
+
+func (in MatchRuleInput) activation() map[string]interface{} {
+	return map[string]interface{}{
+		"match": map[string]interface{}{
+			"player_a_id": in.PlayerA.ID,
+			"player_b_id": in.PlayerB.ID,
+			"score_a":     int64(in.ScoreA),
+			"score_b":     int64(in.ScoreB),
+			"played_at":   in.PlayedAt,
+		},
+		"series": map[string]interface{}{
+			"id":           in.SeriesID,
+			"club_id":      in.ClubID,
+			"format":       in.SeriesFormat,
+			"starts_at":    in.SeriesStartsAt,
+			"ends_at":      in.SeriesEndsAt,
+			"sets_to_play": int64(in.SetsToPlay),
+		},
+		"player_a": in.PlayerA.fields(),
+		"player_b": in.PlayerB.fields(),
+		"pair": map[string]interface{}{
+			"matches_week":  in.PairMatchesWeek,
+			"matches_month": in.PairMatchesMonth,
+			"matches_total": in.PairMatchesTotal,
+		},
+	}
+}
+
+func (p RulePlayer) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"id":             p.ID,
+		"rating":         int64(p.Rating),
+		"rank":           int64(p.Rank),
+		"matches_played": int64(p.MatchesPlayed),
+	}
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCELValidatorEvaluate(t *testing.T) {
+	v, err := NewCELValidator()
+	if err != nil {
+		t.Fatalf("NewCELValidator() error = %v", err)
+	}
+
+	// Tuesday 2024-03-05 19:00 in Stockholm
+	playedAt := time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC)
+	input := MatchRuleInput{
+		PlayerA:         RulePlayer{ID: "a", Rating: 1400},
+		PlayerB:         RulePlayer{ID: "b", Rating: 1100},
+		ScoreA:          3,
+		ScoreB:          1,
+		PlayedAt:        playedAt,
+		SeriesFormat:    "LADDER",
+		PairMatchesWeek: 2,
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"pair.matches_week < 2", false},
+		{"pair.matches_week <= 2", true},
+		{"match.played_at.getDayOfWeek('Europe/Stockholm') in [1, 2, 3, 4, 5] && match.played_at.getHours('Europe/Stockholm') >= 17", true},
+		{"series.format != 'LADDER' || (player_a.rating - player_b.rating < 400 && player_b.rating - player_a.rating < 400)", true},
+		{"player_a.rating - player_b.rating < 200", false},
+		{"match.score_a > match.score_b", true},
+	}
+
+	for _, test := range tests {
+		passed, err := v.Evaluate(test.expression, input)
+		if err != nil {
+			t.Errorf("Evaluate(%q) error = %v", test.expression, err)
+			continue
+		}
+		if passed != test.expected {
+			t.Errorf("Evaluate(%q) = %v, expected %v", test.expression, passed, test.expected)
+		}
+	}
+}
+
+func TestCELValidatorCompileRejectsInvalidRules(t *testing.T) {
+	v, err := NewCELValidator()
+	if err != nil {
+		t.Fatalf("NewCELValidator() error = %v", err)
+	}
+
+	for _, expression := range []string{"match.score_a >", "unknown_var > 1", "'not a bool'"} {
+		if _, err := v.Compile(expression); err == nil {
+			t.Errorf("Compile(%q) expected error", expression)
+		}
+	}
+}
+
+func TestCELValidatorValidateMatchReturnsViolation(t *testing.T) {
+	v, err := NewCELValidator()
+	if err != nil {
+		t.Fatalf("NewCELValidator() error = %v", err)
+	}
+
+	rules := []Rule{
+		{ID: "1", Name: "always", Expression: "true"},
+		{ID: "2", Name: "weekly limit", Expression: "pair.matches_week < 2", ErrorCode: "RULE_MAX_PAIR_MATCHES_PER_WEEK"},
+	}
+
+	err = v.ValidateMatch(rules, MatchRuleInput{PairMatchesWeek: 2})
+	var violation *RuleViolation
+	if !errors.As(err, &violation) {
+		t.Fatalf("ValidateMatch() error = %v, expected RuleViolation", err)
+	}
+	if violation.Rule.ErrorCode != "RULE_MAX_PAIR_MATCHES_PER_WEEK" {
+		t.Errorf("violation code = %q", violation.Rule.ErrorCode)
+	}
+
+	if err := v.ValidateMatch(rules, MatchRuleInput{PairMatchesWeek: 1}); err != nil {
+		t.Errorf("ValidateMatch() unexpected error = %v", err)
+	}
+}
+
+func TestCELValidatorLimits(t *testing.T) {
+	v, err := NewCELValidator()
+	if err != nil {
+		t.Fatalf("NewCELValidator() error = %v", err)
+	}
+
+	long := "true" + strings.Repeat(" && true", MaxExpressionLength/8)
+	if _, err := v.Compile(long); err == nil {
+		t.Error("Compile() accepted an expression over the length limit")
+	}
+
+	// 10^5 iterations exceed the cost limit
+	list := "[0, 1, 2, 3, 4, 5, 6, 7, 8, 9]"
+	expensive := list + ".all(a, " + list + ".all(b, " + list + ".all(c, " + list + ".all(d, " + list + ".all(e, a + b + c + d + e >= 0)))))"
+	if _, err := v.Evaluate(expensive, MatchRuleInput{}); err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("Evaluate() error = %v, expected cost limit error", err)
+	}
+
+	// Tested expressions are not cached
+	if _, err := v.Evaluate("match.score_a > 0", MatchRuleInput{}); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(v.programs) != 0 {
+		t.Errorf("Evaluate() cached %d programs", len(v.programs))
+	}
+
+	for i := 0; i < maxCachedPrograms+10; i++ {
+		rule := Rule{Name: "generated", Expression: fmt.Sprintf("match.score_a >= %d || true", i)}
+		if err := v.ValidateMatch([]Rule{rule}, MatchRuleInput{}); err != nil {
+			t.Fatalf("ValidateMatch() error = %v", err)
+		}
+	}
+	if len(v.programs) > maxCachedPrograms {
+		t.Errorf("program cache holds %d programs, limit %d", len(v.programs), maxCachedPrograms)
+	}
+}
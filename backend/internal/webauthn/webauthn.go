@@ -0,0 +1,335 @@
+// Package webauthn verifies passkey (WebAuthn) registration and login ceremonies.
+//
+// Attestation statements are not verified: registration requests "none"
+// conveyance, so only the credential public key and the relying party binding
+// are checked. Supported key algorithms are ES256, RS256 and EdDSA.
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Authenticator data flags
+const (
+	FlagUserPresent      byte = 0x01
+	FlagUserVerified     byte = 0x04
+	FlagAttestedCredData byte = 0x40
+	FlagExtensionData    byte = 0x80
+)
+
+// COSE algorithm identifiers
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// UserVerificationRequired is the userVerification option to request. A
+// passkey is the only factor at login, so the authenticator must verify the
+// user with a PIN or biometric rather than just detect a touch.
+const UserVerificationRequired = "required"
+
+// SupportedAlgorithms lists the COSE algorithms accepted for new credentials, in order of preference
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+var (
+	ErrInvalidClientData  = errors.New("webauthn: invalid client data")
+	ErrChallengeMismatch  = errors.New("webauthn: challenge mismatch")
+	ErrOriginMismatch     = errors.New("webauthn: origin not allowed")
+	ErrRPIDMismatch       = errors.New("webauthn: relying party ID mismatch")
+	ErrUserNotPresent     = errors.New("webauthn: user presence flag not set")
+	ErrUserNotVerified    = errors.New("webauthn: user verification flag not set")
+	ErrInvalidAuthData    = errors.New("webauthn: invalid authenticator data")
+	ErrUnsupportedKey     = errors.New("webauthn: unsupported public key")
+	ErrInvalidSignature   = errors.New("webauthn: invalid signature")
+	ErrSignCountRegressed = errors.New("webauthn: signature counter did not increase")
+)
+
+// RelyingParty identifies this service to authenticators
+type RelyingParty struct {
+	ID      string   // Effective domain, e.g. "klubbspel.se"
+	Name    string   // Human-readable name shown by the authenticator
+	Origins []string // Allowed origins, e.g. "https://klubbspel.se"
+}
+
+// Credential is a verified, newly registered public key credential
+type Credential struct {
+	ID        []byte
+	PublicKey []byte // COSE_Key encoding
+	Algorithm int64
+	SignCount uint32
+	AAGUID    []byte
+}
+
+// AuthenticatorData is the parsed authenticatorData structure
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewChallenge returns 32 random bytes for a registration or login ceremony
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// ChallengeFromClientData returns the challenge echoed in clientDataJSON so the
+// caller can look up the ceremony it belongs to before verifying the response
+func ChallengeFromClientData(clientDataJSON []byte) ([]byte, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return nil, ErrInvalidClientData
+	}
+	challenge, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, ErrInvalidClientData
+	}
+	return challenge, nil
+}
+
+// VerifyRegistration checks a navigator.credentials.create() response against the
+// issued challenge and returns the credential to store
+func (rp *RelyingParty) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	decoded, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthData, err)
+	}
+	attestation, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrInvalidAuthData
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, ErrInvalidAuthData
+	}
+
+	authData, err := ParseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthData(authData); err != nil {
+		return nil, err
+	}
+	if authData.Flags&FlagAttestedCredData == 0 {
+		return nil, fmt.Errorf("%w: no attested credential", ErrInvalidAuthData)
+	}
+
+	key, err := parsePublicKey(authData.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:        authData.CredentialID,
+		PublicKey: authData.PublicKey,
+		Algorithm: key.alg,
+		SignCount: authData.SignCount,
+		AAGUID:    authData.AAGUID,
+	}, nil
+}
+
+// VerifyAssertion checks a navigator.credentials.get() response signed with a stored
+// credential and returns the authenticator's new signature counter
+func (rp *RelyingParty) VerifyAssertion(challenge, publicKey []byte, storedSignCount uint32, clientDataJSON, authenticatorData, signature []byte) (uint32, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := ParseAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+	if err := rp.verifyAuthData(authData); err != nil {
+		return 0, err
+	}
+	if authData.Flags&FlagUserVerified == 0 {
+		return 0, ErrUserNotVerified
+	}
+
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorData...), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// Authenticators without a counter always report zero; otherwise it must grow
+	if (authData.SignCount != 0 || storedSignCount != 0) && authData.SignCount <= storedSignCount {
+		return 0, ErrSignCountRegressed
+	}
+
+	return authData.SignCount, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return ErrInvalidClientData
+	}
+	if data.Type != ceremony {
+		return fmt.Errorf("%w: unexpected type %q", ErrInvalidClientData, data.Type)
+	}
+
+	received, err := base64.RawURLEncoding.DecodeString(data.Challenge)
+	if err != nil || !bytes.Equal(received, challenge) {
+		return ErrChallengeMismatch
+	}
+
+	for _, origin := range rp.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrOriginMismatch, data.Origin)
+}
+
+func (rp *RelyingParty) verifyAuthData(authData *AuthenticatorData) error {
+	expected := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData.RPIDHash, expected[:]) {
+		return ErrRPIDMismatch
+	}
+	if authData.Flags&FlagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+	return nil
+}
+
+// ParseAuthenticatorData decodes the binary authenticatorData structure
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidAuthData)
+	}
+
+	authData := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	if authData.Flags&FlagAttestedCredData == 0 {
+		return authData, nil
+	}
+
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("%w: truncated attested credential data", ErrInvalidAuthData)
+	}
+	authData.AAGUID = rest[:16]
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if len(rest) < idLen {
+		return nil, fmt.Errorf("%w: truncated credential ID", ErrInvalidAuthData)
+	}
+	authData.CredentialID = rest[:idLen]
+	rest = rest[idLen:]
+
+	_, n, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthData, err)
+	}
+	authData.PublicKey = rest[:n]
+	if len(rest) > n && authData.Flags&FlagExtensionData == 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", ErrInvalidAuthData)
+	}
+	return authData, nil
+}
+
+type publicKey struct {
+	alg    int64
+	verify func(signed, signature []byte) bool
+}
+
+// parsePublicKey decodes a COSE_Key into a signature verifier
+func parsePublicKey(raw []byte) (*publicKey, error) {
+	decoded, _, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKey, err)
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+
+	switch {
+	case kty == 2 && alg == AlgES256:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{alg: alg, verify: func(signed, signature []byte) bool {
+			digest := sha256.Sum256(signed)
+			return ecdsa.VerifyASN1(pub, digest[:], signature)
+		}}, nil
+
+	case kty == 1 && alg == AlgEdDSA:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		pub := ed25519.PublicKey(x)
+		return &publicKey{alg: alg, verify: func(signed, signature []byte) bool {
+			return ed25519.Verify(pub, signed, signature)
+		}}, nil
+
+	case kty == 3 && alg == AlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+		return &publicKey{alg: alg, verify: func(signed, signature []byte) bool {
+			digest := sha256.Sum256(signed)
+			return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("%w: kty %d alg %d", ErrUnsupportedKey, kty, alg)
+}
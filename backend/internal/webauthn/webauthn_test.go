@@ -0,0 +1,183 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+var testRP = &RelyingParty{ID: "klubbspel.se", Name: "Klubbspel", Origins: []string{"https://klubbspel.se"}}
+
+// encodeCBOR is a minimal encoder for building authenticator responses in tests
+func encodeCBOR(value interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			out := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(out[1:], uint16(n))
+			return out
+		}
+	}
+
+	switch v := value.(type) {
+	case int:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case map[interface{}]interface{}:
+		keys := make([]string, 0, len(v))
+		encoded := map[string][]byte{}
+		for key, item := range v {
+			k := string(encodeCBOR(key))
+			keys = append(keys, k)
+			encoded[k] = encodeCBOR(item)
+		}
+		sort.Strings(keys)
+		out := head(5, uint64(len(v)))
+		for _, k := range keys {
+			out = append(out, k...)
+			out = append(out, encoded[k]...)
+		}
+		return out
+	}
+	panic(fmt.Sprintf("unsupported type %T", value))
+}
+
+func clientDataJSON(ceremony string, challenge []byte, origin string) []byte {
+	return []byte(fmt.Sprintf(`{"type":%q,"challenge":%q,"origin":%q}`,
+		ceremony, base64.RawURLEncoding.EncodeToString(challenge), origin))
+}
+
+func authenticatorData(rpID string, flags byte, signCount uint32, credentialID, coseKey []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte(nil), rpIDHash[:]...)
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, signCount)
+	if credentialID != nil {
+		out = append(out, make([]byte, 16)...) // AAGUID
+		out = binary.BigEndian.AppendUint16(out, uint16(len(credentialID)))
+		out = append(out, credentialID...)
+		out = append(out, coseKey...)
+	}
+	return out
+}
+
+func ecKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := make([]byte, 32), make([]byte, 32)
+	priv.X.FillBytes(x)
+	priv.Y.FillBytes(y)
+	cose := encodeCBOR(map[interface{}]interface{}{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+	return priv, cose
+}
+
+func sign(t *testing.T, priv *ecdsa.PrivateKey, authData, clientData []byte) []byte {
+	t.Helper()
+	clientHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signature
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	priv, cose := ecKey(t)
+	credentialID := []byte("credential-1")
+
+	challenge, err := NewChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	attestation := encodeCBOR(map[interface{}]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[interface{}]interface{}{},
+		"authData": authenticatorData("klubbspel.se", FlagUserPresent|FlagAttestedCredData, 0, credentialID, cose),
+	})
+
+	credential, err := testRP.VerifyRegistration(challenge, clientDataJSON("webauthn.create", challenge, "https://klubbspel.se"), attestation)
+	if err != nil {
+		t.Fatalf("VerifyRegistration: %v", err)
+	}
+	if string(credential.ID) != string(credentialID) || credential.Algorithm != AlgES256 {
+		t.Fatalf("unexpected credential %+v", credential)
+	}
+
+	loginChallenge, _ := NewChallenge()
+	authData := authenticatorData("klubbspel.se", FlagUserPresent|FlagUserVerified, 5, nil, nil)
+	clientData := clientDataJSON("webauthn.get", loginChallenge, "https://klubbspel.se")
+	signature := sign(t, priv, authData, clientData)
+
+	count, err := testRP.VerifyAssertion(loginChallenge, credential.PublicKey, 4, clientData, authData, signature)
+	if err != nil {
+		t.Fatalf("VerifyAssertion: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("sign count = %d, want 5", count)
+	}
+
+	tests := []struct {
+		name      string
+		challenge []byte
+		stored    uint32
+		authData  []byte
+		client    []byte
+		expected  error
+	}{
+		{"wrong challenge", challenge, 4, authData, clientData, ErrChallengeMismatch},
+		{"replayed counter", loginChallenge, 5, authData, clientData, ErrSignCountRegressed},
+		{"wrong origin", loginChallenge, 4, authData, clientDataJSON("webauthn.get", loginChallenge, "https://evil.example"), ErrOriginMismatch},
+		{"wrong ceremony", loginChallenge, 4, authData, clientDataJSON("webauthn.create", loginChallenge, "https://klubbspel.se"), ErrInvalidClientData},
+		{"wrong rp", loginChallenge, 4, authenticatorData("evil.example", FlagUserPresent, 5, nil, nil), clientData, ErrRPIDMismatch},
+		{"user not present", loginChallenge, 4, authenticatorData("klubbspel.se", 0, 5, nil, nil), clientData, ErrUserNotPresent},
+		{"user not verified", loginChallenge, 4, authenticatorData("klubbspel.se", FlagUserPresent, 5, nil, nil), clientData, ErrUserNotVerified},
+	}
+
+	for _, test := range tests {
+		sig := sign(t, priv, test.authData, test.client)
+		_, err := testRP.VerifyAssertion(test.challenge, credential.PublicKey, test.stored, test.client, test.authData, sig)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: got %v, want %v", test.name, err, test.expected)
+		}
+	}
+
+	// A signature over different data must be rejected
+	if _, err := testRP.VerifyAssertion(loginChallenge, credential.PublicKey, 4, clientData, authData, sign(t, priv, authData, []byte("{}"))); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered signature: got %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestDecodeCBORRejectsMalformedInput(t *testing.T) {
+	inputs := [][]byte{
+		{},
+		{0x5a, 0xff, 0xff, 0xff, 0xff}, // byte string longer than input
+		{0x9f},                         // indefinite-length array
+		{0xa1, 0x80, 0x00},             // array as map key
+	}
+	for _, input := range inputs {
+		if _, _, err := decodeCBOR(input); err == nil {
+			t.Errorf("decodeCBOR(%x) succeeded, want error", input)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import { RulesTestPage } from '@/pages/RulesTestPage'
 import { SeriesDetailPage } from '@/pages/SeriesDetailPage'
 import { SeriesListPage } from '@/pages/SeriesListPage'
 import { SettingsPage } from '@/pages/SettingsPage'
+import { UnsubscribePage } from '@/pages/UnsubscribePage'
 import { useAppStore } from '@/store'
 import { useEffect } from 'react'
 import { useTranslation } from 'react-i18next'
@@ -41,6 +42,7 @@ function App() {
             <Route path="/players" element={<PlayersPage />} />
             <Route path="/leaderboard" element={<LeaderboardPage />} />
             <Route path="/settings" element={<SettingsPage />} />
+            <Route path="/unsubscribe" element={<UnsubscribePage />} />
             <Route path="/login" element={<LoginPage />} />
             <Route path="/auth/login" element={<LoginPage />} />
             <Route path="/rules-test" element={<RulesTestPage />} />
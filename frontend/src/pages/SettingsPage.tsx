@@ -2,8 +2,10 @@ import { Button } from '@/components/ui/button'
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
 import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
 // Removed Select import - using native dropdown to avoid scroll lock
 import apiClient from '@/services/api'
+import type { NotificationPreferences } from '@/types/api'
 import { useAppStore } from '@/store'
 import { useAuthStore } from '@/store/auth'
 import { useEffect, useState } from 'react'
@@ -17,6 +19,8 @@ export function SettingsPage() {
   const [firstName, setFirstName] = useState('')
   const [lastName, setLastName] = useState('')
   const [isUpdatingProfile, setIsUpdatingProfile] = useState(false)
+  const [notifications, setNotifications] = useState<NotificationPreferences>({})
+  const [isUpdatingNotifications, setIsUpdatingNotifications] = useState(false)
 
   useEffect(() => {
     if (user) {
@@ -37,6 +41,30 @@ export function SettingsPage() {
     }
   }, [user])
 
+  useEffect(() => {
+    if (!user) {return}
+    apiClient.getNotificationPreferences()
+      .then(setNotifications)
+      .catch(() => toast.error(t('settings.notifications.load.failed')))
+  }, [user, t])
+
+  const handleNotificationChange = async (key: keyof NotificationPreferences, enabled: boolean) => {
+    const previous = notifications
+    const next = { ...notifications, [key]: enabled }
+    setNotifications(next)
+    setIsUpdatingNotifications(true)
+    try {
+      setNotifications(await apiClient.updateNotificationPreferences(next))
+    } catch (_error) {
+      setNotifications(previous)
+      toast.error(t('settings.notifications.update.failed'))
+    } finally {
+      setIsUpdatingNotifications(false)
+    }
+  }
+
+  const notificationOptions: (keyof NotificationPreferences)[] = ['matchReported', 'ladderOvertaken', 'seriesEnding', 'seriesSummary', 'dailyDigest']
+
   const handleLanguageChange = async (newLanguage: string) => {
     try {
       setLanguage(newLanguage)
@@ -176,6 +204,35 @@ export function SettingsPage() {
             </div>
           </CardContent>
         </Card>
+
+        {/* Notification Settings */}
+        {user && (
+          <Card>
+            <CardHeader>
+              <CardTitle>{t('settings.notifications')}</CardTitle>
+              <CardDescription>
+                {t('settings.notifications.description')}
+              </CardDescription>
+            </CardHeader>
+            <CardContent>
+              <div className="space-y-4">
+                {notificationOptions.map((key) => (
+                  <div key={key} className="flex items-center justify-between gap-4">
+                    <Label htmlFor={`notification-${key}`} className="text-sm">
+                      {t(`settings.notifications.${key}`)}
+                    </Label>
+                    <Switch
+                      id={`notification-${key}`}
+                      checked={notifications[key] ?? false}
+                      disabled={isUpdatingNotifications}
+                      onCheckedChange={(checked) => handleNotificationChange(key, checked)}
+                    />
+                  </div>
+                ))}
+              </div>
+            </CardContent>
+          </Card>
+        )}
       </div>
     </div>
   )
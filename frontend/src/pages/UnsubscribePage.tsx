@@ -0,0 +1,50 @@
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import apiClient from '@/services/api'
+import { BellOff } from 'lucide-react'
+import { useEffect, useState } from 'react'
+import { useTranslation } from 'react-i18next'
+import { Link, useSearchParams } from 'react-router-dom'
+
+type UnsubscribeState = 'loading' | 'done' | 'failed'
+
+export function UnsubscribePage() {
+  const { t } = useTranslation()
+  const [searchParams] = useSearchParams()
+  const [state, setState] = useState<UnsubscribeState>('loading')
+
+  useEffect(() => {
+    const token = searchParams.get('token')
+    if (!token) {
+      setState('failed')
+      return
+    }
+    apiClient.unsubscribe(token)
+      .then(() => setState('done'))
+      .catch(() => setState('failed'))
+  }, [searchParams])
+
+  return (
+    <div className="max-w-md mx-auto">
+      <Card>
+        <CardHeader>
+          <CardTitle className="flex items-center gap-2">
+            <BellOff className="h-5 w-5" />
+            {t('notifications.unsubscribe.title')}
+          </CardTitle>
+          <CardDescription>
+            {state === 'loading' && t('common.loading')}
+            {state === 'done' && t('notifications.unsubscribe.done')}
+            {state === 'failed' && t('notifications.unsubscribe.failed')}
+          </CardDescription>
+        </CardHeader>
+        {state !== 'loading' && (
+          <CardContent>
+            <Link to="/settings" className="text-sm text-primary underline">
+              {t('notifications.unsubscribe.manage')}
+            </Link>
+          </CardContent>
+        )}
+      </Card>
+    </div>
+  )
+}
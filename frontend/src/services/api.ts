@@ -33,14 +33,20 @@ import type {
   ListPlayersResponse,
   ListSeriesRequest,
   ListSeriesResponse,
+  NotificationPreferences,
+  NotificationPreferencesResponse,
   MergePlayerRequest,
   MergePlayerResponse,
   Player,
+  PlayerStats,
+  HeadToHead,
   ReportMatchRequest,
   ReportMatchResponse,
   ReportMatchV2Request,
   ReportMatchV2Response,
   Series,
+  SeriesSummary,
+  UnsubscribeResponse,
   UpdateClubRequest,
   UpdateMatchRequest,
   UpdateMatchResponse,
@@ -138,6 +144,7 @@ class ApiClient {
         const apiError: ApiError = {
           code: errorData.code || `HTTP_${response.status}`,
           message: errorData.message || response.statusText,
+          args: errorData.args,
           details: errorData.details
         }
         
@@ -261,6 +268,14 @@ class ApiClient {
     return response.player
   }
 
+  async getPlayerStats(id: string): Promise<PlayerStats> {
+    return this.get<PlayerStats>(`/v1/players/${id}/stats`)
+  }
+
+  async getHeadToHead(playerId: string, opponentId: string): Promise<HeadToHead> {
+    return this.get<HeadToHead>(`/v1/players/${playerId}/head-to-head/${opponentId}`)
+  }
+
   async createPlayer(data: CreatePlayerRequest): Promise<CreatePlayerResponse> {
     return this.post<CreatePlayerResponse>('/v1/players', data)
   }
@@ -324,6 +339,11 @@ class ApiClient {
     return response.series
   }
 
+  async getSeriesSummary(seriesId: string): Promise<SeriesSummary> {
+    const response = await this.get<{ summary: SeriesSummary }>(`/v1/series/${seriesId}/summary`)
+    return response.summary
+  }
+
   async createSeries(data: CreateSeriesRequest): Promise<Series> {
     const response = await this.post<{ series: Series }>('/v1/series', data)
     return response.series
@@ -388,6 +408,9 @@ class ApiClient {
     if (params.pageSize) {searchParams.append('pageSize', params.pageSize.toString())}
     if (params.cursorAfter) {searchParams.append('cursorAfter', params.cursorAfter)}
     if (params.cursorBefore) {searchParams.append('cursorBefore', params.cursorBefore)}
+    if (params.sortBy) {searchParams.append('sortBy', params.sortBy)}
+    if (params.minMatches) {searchParams.append('minMatches', params.minMatches.toString())}
+    if (params.asOf) {searchParams.append('asOf', params.asOf)}
 
     const query = searchParams.toString()
     return this.get<GetLeaderboardResponse>(
@@ -483,6 +506,25 @@ class ApiClient {
     return this.get<{ user: AuthUser }>('/v1/auth/me')
   }
 
+  // === NOTIFICATION API METHODS ===
+
+  // Get the current user's notification preferences
+  async getNotificationPreferences(): Promise<NotificationPreferences> {
+    const response = await this.get<NotificationPreferencesResponse>('/v1/notifications/preferences')
+    return response.preferences ?? {}
+  }
+
+  // Replace the current user's notification preferences
+  async updateNotificationPreferences(preferences: NotificationPreferences): Promise<NotificationPreferences> {
+    const response = await this.patch<NotificationPreferencesResponse>('/v1/notifications/preferences', { preferences })
+    return response.preferences ?? {}
+  }
+
+  // Turn off notifications using the signed token from an email link
+  async unsubscribe(token: string): Promise<UnsubscribeResponse> {
+    return this.post<UnsubscribeResponse>(`/v1/notifications/unsubscribe?token=${encodeURIComponent(token)}`)
+  }
+
   // Utility method to cancel specific requests
   cancelRequest(requestId: string): void {
     this.abortControllers.get(requestId)?.abort()
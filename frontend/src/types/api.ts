@@ -4,6 +4,7 @@
 export interface ApiError {
   code: string
   message: string
+  args?: Record<string, string>
   details?: unknown[]
 }
 
@@ -51,6 +52,19 @@ export type LadderRules =
   | 'LADDER_RULES_CLASSIC'
   | 'LADDER_RULES_AGGRESSIVE'
 
+// Breaks ties between players with equal ELO ratings, applied in order
+export type Tiebreaker =
+  | 'TIEBREAKER_HEAD_TO_HEAD'
+  | 'TIEBREAKER_SET_DIFFERENCE'
+  | 'TIEBREAKER_MATCHES_PLAYED'
+  | 'TIEBREAKER_NAME'
+
+export type LeaderboardSort =
+  | 'LEADERBOARD_SORT_UNSPECIFIED'
+  | 'LEADERBOARD_SORT_WIN_RATE'
+  | 'LEADERBOARD_SORT_MATCHES_PLAYED'
+  | 'LEADERBOARD_SORT_GAMES_DIFFERENCE'
+
 // Club types
 export interface Club {
   id: string
@@ -104,6 +118,56 @@ export interface Player {
   lastLoginAt?: string
 }
 
+// Player statistics
+export interface SportRecord {
+  sport: Sport  // SPORT_UNSPECIFIED for the overall record
+  matchesPlayed: number
+  matchesWon: number
+  matchesLost: number
+  winRate: number  // Percentage (0-100)
+  averageSetsWon: number
+  averageSetsLost: number
+}
+
+export interface Streak {
+  length: number
+  startedAt: string
+  endedAt: string
+}
+
+export interface PlayerMatch {
+  matchId: string
+  seriesId: string
+  seriesTitle: string
+  sport: Sport
+  opponentId: string
+  opponentName: string
+  setsWon: number
+  setsLost: number
+  won: boolean
+  playedAt: string
+  opponentCurrentRating: number  // ELO rating today, not at match time; 0 for ladder series
+}
+
+export interface PlayerStats {
+  playerId: string
+  overall?: SportRecord
+  bySport?: SportRecord[]
+  longestWinStreak?: Streak
+  longestLossStreak?: Streak
+  currentStreak?: number  // Positive for wins in a row, negative for losses
+  form?: PlayerMatch[]
+  bestWin?: PlayerMatch
+}
+
+export interface HeadToHead {
+  playerId: string
+  opponentId: string
+  overall?: SportRecord
+  bySport?: SportRecord[]
+  matches?: PlayerMatch[]
+}
+
 // Club membership for players
 export interface ClubMembership {
   clubId: string
@@ -209,6 +273,29 @@ export interface Series {
   ladderRules?: LadderRules
   scoringProfile: ScoringProfile
   setsToPlay: number  // For table tennis: 3 or 5
+  tiebreakers?: Tiebreaker[]  // Empty means head-to-head, set difference, matches played, name
+}
+
+// Season awards; ladder series count positions where ELO series count rating points
+export interface SeriesAward {
+  playerId: string
+  playerName: string
+  value: number
+  opponentId?: string   // Biggest upset only
+  opponentName?: string
+  matchId?: string
+}
+
+export interface SeriesSummary {
+  seriesId: string
+  generatedAt: string
+  matchesPlayed: number
+  players: number
+  champion?: SeriesAward
+  mostMatches?: SeriesAward
+  biggestUpset?: SeriesAward
+  mostImproved?: SeriesAward
+  longestWinStreak?: SeriesAward
 }
 
 export interface CreateSeriesRequest {
@@ -222,6 +309,7 @@ export interface CreateSeriesRequest {
   ladderRules?: LadderRules
   scoringProfile?: ScoringProfile
   setsToPlay?: number
+  tiebreakers?: Tiebreaker[]
 }
 
 export interface UpdateSeriesRequest {
@@ -366,6 +454,7 @@ export interface LeaderboardEntry {
   gamesLost: number
   gameWinRate: number
   rankChange: number
+  standingsRank: number  // Position in the standings, regardless of sortBy and minMatches
 }
 
 export interface GetLeaderboardRequest {
@@ -373,6 +462,9 @@ export interface GetLeaderboardRequest {
   pageSize?: number
   cursorAfter?: string
   cursorBefore?: string
+  sortBy?: LeaderboardSort
+  minMatches?: number
+  asOf?: string  // RFC 3339; standings counting only matches played at or before this time
 }
 
 export interface GetLeaderboardResponse {
@@ -383,4 +475,20 @@ export interface GetLeaderboardResponse {
   hasPreviousPage: boolean
   totalPlayers: number
   lastUpdated: string
-}
\ No newline at end of file
+}
+// Notification types
+export interface NotificationPreferences {
+  matchReported?: boolean
+  ladderOvertaken?: boolean
+  seriesEnding?: boolean
+  seriesSummary?: boolean
+  dailyDigest?: boolean
+}
+
+export interface NotificationPreferencesResponse {
+  preferences?: NotificationPreferences
+}
+
+export interface UnsubscribeResponse {
+  notificationType?: string
+}